@@ -40,11 +40,12 @@ const (
 // StartLogger starts the file logger
 func StartLogger() error {
 	os.MkdirAll(StepLogDir(), 0755)
-	LogFile, err := os.OpenFile(LogFilePath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	logFile, err := os.OpenFile(LogFilePath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
 	if err != nil {
 		return err
 	}
-	Logger := logrus.New()
+	LogFile = logFile
+	Logger = logrus.New()
 	Logger.SetOutput(LogFile)
 	return nil
 }
@@ -74,6 +75,11 @@ func LogFilePath() string {
 	return filepath.Join(AgentHomeDir, "agent.log")
 }
 
+// AuditLogPath returns the absolute path of the agent's API audit log
+func AuditLogPath() string {
+	return filepath.Join(AgentHomeDir, "audit.log")
+}
+
 // AssetDir returns the absolute path to the asset directory
 func AssetDir() string {
 	return filepath.Join(AgentHomeDir, `assets`)
@@ -99,11 +105,15 @@ func TouchInitFile() error {
 
 // GetServiceConfig returns the installable service config
 func GetServiceConfig() *service.Config {
+	args := []string{"run"}
+	if ControllerURL != "" {
+		args = []string{"--controller", ControllerURL, "run"}
+	}
 	return &service.Config{
 		Name:             SvcName,
 		DisplayName:      SvcDisplayName,
 		Description:      SvcDescription,
-		Arguments:        []string{"run"},
+		Arguments:        args,
 		WorkingDirectory: AgentHomeDir,
 		Executable:       ExePath,
 	}
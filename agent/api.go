@@ -1,9 +1,19 @@
 package agent
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
+// LogPollInterval is how often the logs of an in progress step are checked for new output while being streamed
+var LogPollInterval = 500 * time.Millisecond
+
 // ReqInitialize is used to initialize the configuration state
 func (e *Engine) ReqInitialize(c *gin.Context) {
 	if Initialized() {
@@ -31,103 +41,308 @@ func (e *Engine) ReqGetStatus(c *gin.Context) {
 	c.JSON(200, e.GetStatus())
 }
 
-// ReqGetState returns a full dump of the agent's state
+// ReqGetState returns a full dump of the agent's state, with any passwords and secrets redacted
 func (e *Engine) ReqGetState(c *gin.Context) {
-	c.JSON(200, e.Config)
+	state := e.State()
+	if state == nil {
+		c.JSON(200, state)
+		return
+	}
+	state.RLock()
+	data, err := json.Marshal(state)
+	redactor := state.Redactor()
+	state.RUnlock()
+	if err != nil {
+		c.JSON(500, map[string]string{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	c.Data(200, "application/json; charset=utf-8", []byte(redactor.Redact(string(data))))
 }
 
 // ReqGetSteps returns a full dump of the agent's steps
 func (e *Engine) ReqGetSteps(c *gin.Context) {
-	c.JSON(200, map[string]string{
-		"status": "ok",
-		"path":   c.Request.RequestURI,
-		"source": c.ClientIP(),
-	})
+	state, ok := e.requireState(c)
+	if !ok {
+		return
+	}
+	state.RLock()
+	defer state.RUnlock()
+	c.JSON(200, state.Steps)
 }
 
 // ReqGetCurrentStep returns the currently working step (if there is one)
 func (e *Engine) ReqGetCurrentStep(c *gin.Context) {
-	c.JSON(200, map[string]string{
-		"status": "ok",
-		"path":   c.Request.RequestURI,
-		"source": c.ClientIP(),
-	})
+	state, ok := e.requireState(c)
+	if !ok {
+		return
+	}
+	state.RLock()
+	defer state.RUnlock()
+	if state.CurrentStep == nil {
+		c.JSON(404, map[string]string{
+			"status":  "error",
+			"message": "no step is currently in progress",
+		})
+		return
+	}
+	c.JSON(200, state.CurrentStep)
 }
 
 // ReqGetCompletedSteps returns the list of completed steps
 func (e *Engine) ReqGetCompletedSteps(c *gin.Context) {
-	c.JSON(200, map[string]string{
-		"status": "ok",
-		"path":   c.Request.RequestURI,
-		"source": c.ClientIP(),
-	})
+	state, ok := e.requireState(c)
+	if !ok {
+		return
+	}
+	c.JSON(200, state.CompletedSteps())
 }
 
 // ReqGetAwaitingSteps returns the list of steps awaiting run
 func (e *Engine) ReqGetAwaitingSteps(c *gin.Context) {
-	c.JSON(200, map[string]string{
-		"status": "ok",
-		"path":   c.Request.RequestURI,
-		"source": c.ClientIP(),
-	})
+	state, ok := e.requireState(c)
+	if !ok {
+		return
+	}
+	c.JSON(200, state.AwaitingSteps())
 }
 
 // ReqGetSpecificStep returns details about a specified step
 func (e *Engine) ReqGetSpecificStep(c *gin.Context) {
-	c.JSON(200, map[string]string{
-		"status":  "ok",
-		"path":    c.Request.RequestURI,
-		"source":  c.ClientIP(),
-		"step_id": c.Param("id"),
-	})
+	step, ok := e.requireStep(c)
+	if !ok {
+		return
+	}
+	c.JSON(200, step)
 }
 
 // ReqGetStepLogStdout returns the step's stdout, streaming if it's in progress
 func (e *Engine) ReqGetStepLogStdout(c *gin.Context) {
-	c.JSON(200, map[string]string{
-		"status":  "ok",
-		"path":    c.Request.RequestURI,
-		"source":  c.ClientIP(),
-		"step_id": c.Param("id"),
-	})
+	step, ok := e.requireStep(c)
+	if !ok {
+		return
+	}
+	streamStepLogs(c, e.State(), step, step.StdoutFile)
 }
 
 // ReqGetStepLogStderr returns the step's stderr, streaming if it's in progress
 func (e *Engine) ReqGetStepLogStderr(c *gin.Context) {
-	c.JSON(200, map[string]string{
-		"status":  "ok",
-		"path":    c.Request.RequestURI,
-		"source":  c.ClientIP(),
-		"step_id": c.Param("id"),
-	})
+	step, ok := e.requireStep(c)
+	if !ok {
+		return
+	}
+	streamStepLogs(c, e.State(), step, step.StderrFile)
 }
 
 // ReqGetStepLogAll returns the step's logs, combined into one streaming chunk
 func (e *Engine) ReqGetStepLogAll(c *gin.Context) {
-	c.JSON(200, map[string]string{
-		"status":  "ok",
-		"path":    c.Request.RequestURI,
-		"source":  c.ClientIP(),
-		"step_id": c.Param("id"),
-	})
+	step, ok := e.requireStep(c)
+	if !ok {
+		return
+	}
+	streamStepLogs(c, e.State(), step, step.StdoutFile, step.StderrFile)
 }
 
-// ReqPushProvision is what kicks off the provisioning process
+// ReqPushProvision is what kicks off the provisioning process. The request body is a new revision of the
+// agent's state, which is handed to the worker. Steps which completed under a previous revision and are
+// unchanged are not run again.
 func (e *Engine) ReqPushProvision(c *gin.Context) {
+	if AsyncWorker == nil {
+		c.JSON(500, map[string]string{
+			"status":  "error",
+			"message": "async worker is nil",
+		})
+		return
+	}
+
+	state := &State{}
+	err := c.ShouldBindJSON(state)
+	if err != nil {
+		c.JSON(400, map[string]string{
+			"status":  "error",
+			"message": fmt.Sprintf("invalid state: %v", err),
+		})
+		return
+	}
+	if len(state.Steps) == 0 {
+		c.JSON(400, map[string]string{
+			"status":  "error",
+			"message": "state does not contain any steps",
+		})
+		return
+	}
+
+	state.CurrentStep = nil
+	state.CurrentState = "pending"
+	state.Errored = false
+	state.ErrorMessage = ""
+	state.InitializedAt = time.Now().UTC()
+	state.CompletedAt = time.Time{}
+
+	err = AsyncWorker.Push(state, ConfigFile())
+	if err != nil {
+		code := 500
+		switch err {
+		case ErrWorkerBusy, ErrStaleRevision, ErrDuplicateRevision:
+			code = 409
+		}
+		c.JSON(code, map[string]string{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if !Initialized() {
+		err = TouchInitFile()
+		if err != nil {
+			Logger.Errorf("could not create the initialized file: %v", err)
+		}
+	}
+
 	c.JSON(200, map[string]string{
-		"status":  "ok",
-		"path":    c.Request.RequestURI,
-		"source":  c.ClientIP(),
-		"step_id": c.Param("id"),
+		"status":   "ok",
+		"revision": strconv.FormatInt(state.Revision, 10),
 	})
 }
 
-// ReqSelfDestruct removes all sensitive data and deletes the server
-func (e *Engine) ReqSelfDestruct(c *gin.Context) {
-	c.JSON(200, map[string]string{
-		"status":  "ok",
-		"path":    c.Request.RequestURI,
-		"source":  c.ClientIP(),
-		"step_id": c.Param("id"),
+// requireState returns the loaded state, responding with an error if the agent has not loaded one
+func (e *Engine) requireState(c *gin.Context) (*State, bool) {
+	state := e.State()
+	if state == nil {
+		c.JSON(404, map[string]string{
+			"status":  "error",
+			"message": "agent has not been initialized",
+		})
+		return nil, false
+	}
+	return state, true
+}
+
+// requireStep returns the step referenced by the id parameter, responding with an error if it does not exist
+func (e *Engine) requireStep(c *gin.Context) (*Step, bool) {
+	state, ok := e.requireState(c)
+	if !ok {
+		return nil, false
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, map[string]string{
+			"status":  "error",
+			"message": fmt.Sprintf("invalid step id %q", c.Param("id")),
+		})
+		return nil, false
+	}
+	step := state.FindStep(id)
+	if step == nil {
+		c.JSON(404, map[string]string{
+			"status":  "error",
+			"message": fmt.Sprintf("step %d does not exist", id),
+		})
+		return nil, false
+	}
+	return step, true
+}
+
+// streamStepLogs writes the contents of a step's log files to the response. If the step is in progress, the
+// files are followed until the step ends or the client disconnects. The step is a copy, so it's progress is
+// checked against the state.
+func streamStepLogs(c *gin.Context, state *State, step *Step, files ...string) {
+	if step.StdoutFile == "" {
+		c.JSON(404, map[string]string{
+			"status":  "error",
+			"message": "step has not been started",
+		})
+		return
+	}
+
+	if !step.InProgress() {
+		found := false
+		for _, name := range files {
+			if _, err := os.Stat(name); err == nil {
+				found = true
+			}
+		}
+		if !found {
+			c.JSON(404, map[string]string{
+				"status":  "error",
+				"message": "no logs were recorded for step",
+			})
+			return
+		}
+	}
+
+	readers := make([]*os.File, len(files))
+	defer func() {
+		for _, f := range readers {
+			if f != nil {
+				f.Close()
+			}
+		}
+	}()
+
+	buf := make([]byte, 32*1024)
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Status(200)
+	c.Stream(func(w io.Writer) bool {
+		// checked before reading so that output written as the step finishes is not lost
+		done := !state.StepInProgress(step.ID)
+		for i, name := range files {
+			if readers[i] == nil {
+				f, err := os.Open(name)
+				if err != nil {
+					continue
+				}
+				readers[i] = f
+			}
+			_, err := io.CopyBuffer(w, readers[i], buf)
+			if err != nil {
+				return false
+			}
+		}
+		if done {
+			return false
+		}
+		time.Sleep(LogPollInterval)
+		return true
 	})
 }
+
+// ReqSelfDestruct removes all sensitive data and deletes the agent, responding with a signed receipt of everything
+// that was removed. The agent exits shortly after.
+func (e *Engine) ReqSelfDestruct(c *gin.Context) {
+	if AsyncWorker != nil && !AsyncWorker.Available() {
+		c.JSON(409, map[string]string{
+			"status":  "error",
+			"message": ErrWorkerBusy.Error(),
+		})
+		return
+	}
+
+	req := &SelfDestructRequest{}
+	if c.Request.ContentLength != 0 {
+		err := c.ShouldBindJSON(req)
+		if err != nil {
+			c.JSON(400, map[string]string{
+				"status":  "error",
+				"message": fmt.Sprintf("invalid self destruct request: %v", err),
+			})
+			return
+		}
+	}
+
+	Logger.Warnf("self destruct requested by %s", c.ClientIP())
+	receipt, err := e.SelfDestruct(req)
+	if err != nil {
+		c.JSON(500, map[string]string{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(200, receipt)
+	go e.exitAfterSelfDestruct()
+}
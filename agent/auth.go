@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// AuditRecord is written to the audit log for every request made to the agent
+type AuditRecord struct {
+	Time          time.Time     `json:"time"`
+	RemoteAddr    string        `json:"remote_addr"`
+	Method        string        `json:"method"`
+	Path          string        `json:"path"`
+	Status        int           `json:"status"`
+	Client        string        `json:"client,omitempty"`
+	Authenticated bool          `json:"authenticated"`
+	Latency       time.Duration `json:"latency"`
+}
+
+// ServerTLSConfig returns the TLS config of the agent's API using the credentials the build placed in the
+// agent's home directory. Client certificates are verified against the build's CA when presented, and
+// RequireClientCert rejects any request without one.
+func ServerTLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(AgentHomeDir, CertFilename), filepath.Join(AgentHomeDir, KeyFilename))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load the agent certificate")
+	}
+	pool, err := loadCertPool(filepath.Join(AgentHomeDir, CACertFilename))
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ClientName returns the common name of the verified client certificate of a request (if there is one)
+func ClientName(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return r.TLS.VerifiedChains[0][0].Subject.CommonName
+}
+
+// RequireClientCert rejects any request which did not present a client certificate issued by the build's CA
+func RequireClientCert(c *gin.Context) {
+	if ClientName(c.Request) != ClientCommonName {
+		Logger.Warnf("rejected unauthenticated request for %s from %s", c.Request.URL.Path, c.ClientIP())
+		c.AbortWithStatusJSON(401, map[string]string{
+			"status":  "error",
+			"message": "unauthorized",
+		})
+		return
+	}
+	c.Next()
+}
+
+// AuditLogger returns a middleware which writes an AuditRecord as a JSON line to w for every request
+func AuditLogger(w io.Writer) gin.HandlerFunc {
+	mu := new(sync.Mutex)
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		name := ClientName(c.Request)
+		data, err := json.Marshal(&AuditRecord{
+			Time:          start.UTC(),
+			RemoteAddr:    c.ClientIP(),
+			Method:        c.Request.Method,
+			Path:          c.Request.URL.Path,
+			Status:        c.Writer.Status(),
+			Client:        name,
+			Authenticated: name == ClientCommonName,
+			Latency:       time.Since(start),
+		})
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_, err = w.Write(append(data, '\n'))
+		if err != nil {
+			Logger.Errorf("could not write to the audit log: %v", err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Client talks to the API of a push mode agent, authenticating with the operator's client certificate from a
+// build's data directory.
+type Client struct {
+	URL     string
+	DataDir string
+	HTTP    *http.Client
+}
+
+// NewClient creates a client for the agent of the provisioned host with the provided ID, listening on addr (a host, or
+// host:port when the agent does not use ServerPort), using the credentials in the build's data directory
+func NewClient(datadir, addr, id string) (*Client, error) {
+	tlsConfig, err := LoadClientTLSConfig(datadir, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, fmt.Sprintf("%d", ServerPort))
+	}
+	return &Client{
+		URL:     fmt.Sprintf("https://%s", addr),
+		DataDir: datadir,
+		HTTP: &http.Client{
+			Timeout: 60 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: tlsConfig,
+			},
+		},
+	}, nil
+}
+
+// Status returns the status of the agent
+func (c *Client) Status() (*Status, error) {
+	status := &Status{}
+	err := c.do("GET", "/api/status", nil, status)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// SelfDestruct asks the agent to remove itself from the host, returning it's receipt once the signature has been
+// verified against the build's CA. The signed receipt is returned as well so that it can be kept as proof.
+func (c *Client) SelfDestruct(req *SelfDestructRequest) (*Receipt, *SignedReceipt, error) {
+	ca, err := LoadCertificateAuthority(filepath.Join(c.DataDir, BuildCACertFilename), filepath.Join(c.DataDir, BuildCAKeyFilename))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not load the build's agent CA to verify the receipt")
+	}
+	sr := &SignedReceipt{}
+	err = c.do("POST", "/api/self-destruct", req, sr)
+	if err != nil {
+		return nil, nil, err
+	}
+	receipt, err := ca.VerifyReceipt(sr)
+	if err != nil {
+		return nil, sr, err
+	}
+	return receipt, sr, nil
+}
+
+// do performs a JSON request against the agent, decoding the response into out (if provided)
+func (c *Client) do(method, path string, in interface{}, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.URL+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("agent returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
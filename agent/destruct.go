@@ -0,0 +1,300 @@
+package agent
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SelfDestructExitDelay is how long the agent keeps running after a self destruct so that it's receipt can be sent
+var SelfDestructExitDelay = 3 * time.Second
+
+// SelfDestructRequest is the optional body of a self destruct request
+type SelfDestructRequest struct {
+	// CleanupScript is run before anything is removed
+	CleanupScript string `json:"cleanup_script,omitempty"`
+
+	// Language of the cleanup script (shell, powershell or cmd - default = shell on Linux, cmd on Windows)
+	Language string `json:"language,omitempty"`
+}
+
+// CleanupResult records the outcome of a self destruct's cleanup script
+type CleanupResult struct {
+	Language   string `json:"language"`
+	ExitStatus int    `json:"exit_status"`
+	Output     string `json:"output,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Receipt lists everything a self destruct removed from the host, and anything it failed to
+type Receipt struct {
+	ID             string            `json:"id"`
+	Hostname       string            `json:"hostname"`
+	StartedAt      time.Time         `json:"started_at"`
+	CompletedAt    time.Time         `json:"completed_at"`
+	Cleanup        *CleanupResult    `json:"cleanup,omitempty"`
+	ServiceRemoved bool              `json:"service_removed"`
+	BinaryRemoved  bool              `json:"binary_removed"`
+	BinaryDeferred bool              `json:"binary_deferred,omitempty"`
+	Removed        []string          `json:"removed"`
+	Failed         map[string]string `json:"failed,omitempty"`
+}
+
+// SignedReceipt is a receipt signed with the agent's private key. The certificate is included so the receipt can be
+// verified against the build's CA after the agent's credentials have been removed from the host.
+type SignedReceipt struct {
+	Receipt     json.RawMessage `json:"receipt"`
+	Signature   []byte          `json:"signature"`
+	Certificate string          `json:"certificate"`
+}
+
+// fail records a path that could not be removed
+func (r *Receipt) fail(path string, err error) {
+	if r.Failed == nil {
+		r.Failed = map[string]string{}
+	}
+	r.Failed[path] = err.Error()
+}
+
+// SelfDestruct runs the optional cleanup script, uninstalls the agent's service, and deletes the agent's home directory
+// and binary. The credentials used to sign the receipt are loaded before they are deleted. Failures are recorded in
+// the receipt rather than stopping the self destruct.
+func (e *Engine) SelfDestruct(req *SelfDestructRequest) (*SignedReceipt, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(AgentHomeDir, CertFilename), filepath.Join(AgentHomeDir, KeyFilename))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load the agent certificate to sign the receipt")
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &Receipt{
+		StartedAt: time.Now().UTC(),
+		Removed:   []string{},
+	}
+	receipt.Hostname, _ = os.Hostname()
+	if len(leaf.Subject.OrganizationalUnit) > 0 {
+		receipt.ID = leaf.Subject.OrganizationalUnit[0]
+	}
+
+	if state := e.State(); state != nil {
+		state.Lock()
+		state.CurrentState = "destroying"
+		state.Unlock()
+	}
+
+	if req != nil && req.CleanupScript != "" {
+		receipt.Cleanup = runCleanupScript(req)
+		Logger.Infof("cleanup script exited with status %d", receipt.Cleanup.ExitStatus)
+	}
+
+	svc := e.Service
+	if svc == nil {
+		svc, err = GetService()
+	}
+	if err == nil {
+		err = svc.Uninstall()
+	}
+	if err != nil {
+		receipt.fail("service "+SvcName, err)
+	} else {
+		receipt.ServiceRemoved = true
+		receipt.Removed = append(receipt.Removed, "service "+SvcName)
+	}
+
+	// nothing may hold a file open in the home directory while it is removed
+	Logger.SetOutput(ioutil.Discard)
+	if LogFile != nil {
+		LogFile.Close()
+	}
+	if e.AuditLog != nil {
+		e.AuditLog.Close()
+	}
+	os.Chdir(os.TempDir())
+	removeTree(AgentHomeDir, receipt)
+
+	err = removeBinary(ExePath)
+	if err == errRemovalDeferred {
+		receipt.BinaryDeferred = true
+	} else if err != nil {
+		receipt.fail(ExePath, err)
+	} else {
+		receipt.BinaryRemoved = true
+		receipt.Removed = append(receipt.Removed, ExePath)
+	}
+
+	receipt.CompletedAt = time.Now().UTC()
+	return signReceipt(receipt, cert)
+}
+
+// VerifyReceipt checks a signed receipt was produced by an agent certificate issued by the CA, returning the receipt
+func (ca *CertificateAuthority) VerifyReceipt(sr *SignedReceipt) (*Receipt, error) {
+	block, _ := pem.Decode([]byte(sr.Certificate))
+	if block == nil {
+		return nil, errors.New("receipt does not contain a PEM certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Cert)
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:     pool,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "receipt was not signed by an agent of this build")
+	}
+	err = cert.CheckSignature(x509.ECDSAWithSHA256, sr.Receipt, sr.Signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid receipt signature")
+	}
+	receipt := &Receipt{}
+	err = json.Unmarshal(sr.Receipt, receipt)
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// signReceipt signs the JSON encoding of a receipt with the agent's private key
+func signReceipt(receipt *Receipt, cert tls.Certificate) (*SignedReceipt, error) {
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := cert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("agent private key can not be used for signing")
+	}
+	digest := sha256.Sum256(data)
+	sig, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedReceipt{
+		Receipt:     data,
+		Signature:   sig,
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})),
+	}, nil
+}
+
+// removeTree deletes a directory, recording every file and directory within it on the receipt
+func removeTree(root string, receipt *Receipt) {
+	paths := []string{}
+	filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			receipt.fail(p, err)
+			return nil
+		}
+		paths = append(paths, p)
+		return nil
+	})
+	// deepest paths first so directories are empty by the time they are removed
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	for _, p := range paths {
+		err := os.Remove(p)
+		if err != nil && !os.IsNotExist(err) {
+			receipt.fail(p, err)
+			continue
+		}
+		receipt.Removed = append(receipt.Removed, p)
+	}
+	sort.Strings(receipt.Removed)
+}
+
+var errRemovalDeferred = errors.New("removal deferred until the agent exits")
+
+// removeBinary deletes the agent's executable. Windows does not allow a running executable to be deleted, so a
+// detached shell is left to delete it once the agent has exited.
+func removeBinary(exe string) error {
+	if runtime.GOOS != "windows" {
+		return os.Remove(exe)
+	}
+	delay := int(SelfDestructExitDelay/time.Second) + 5
+	cmd := exec.Command("cmd", "/C", fmt.Sprintf(`ping 127.0.0.1 -n %d > nul & del /F /Q "%s"`, delay, exe))
+	err := cmd.Start()
+	if err != nil {
+		return err
+	}
+	return errRemovalDeferred
+}
+
+// runCleanupScript writes the cleanup script of a self destruct request to a temporary file and runs it
+func runCleanupScript(req *SelfDestructRequest) *CleanupResult {
+	lang := req.Language
+	if lang == "" {
+		lang = "shell"
+		if runtime.GOOS == "windows" {
+			lang = "cmd"
+		}
+	}
+	result := &CleanupResult{Language: lang}
+
+	ext := ".sh"
+	switch lang {
+	case "powershell":
+		ext = ".ps1"
+	case "cmd":
+		ext = ".bat"
+	}
+	f, err := ioutil.TempFile("", "laforge-cleanup-*"+ext)
+	if err != nil {
+		result.ExitStatus = -1
+		result.Error = err.Error()
+		return result
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(req.CleanupScript)
+	f.Close()
+	if err != nil {
+		result.ExitStatus = -1
+		result.Error = err.Error()
+		return result
+	}
+
+	var cmd *exec.Cmd
+	switch lang {
+	case "powershell":
+		cmd = exec.Command("powershell", "-NoLogo", "-NonInteractive", "-NoProfile", "-ExecutionPolicy", "Bypass", "-File", f.Name())
+	case "cmd":
+		cmd = exec.Command("cmd", "/C", f.Name())
+	default:
+		cmd = exec.Command("/bin/sh", f.Name())
+	}
+	output, err := cmd.CombinedOutput()
+	result.Output = string(output)
+	if err != nil {
+		step := &Step{}
+		step.SetExitError(err)
+		result.ExitStatus = step.ExitStatus
+		result.Error = step.ExitMessage
+	}
+	return result
+}
+
+// exitAfterSelfDestruct stops the agent once the receipt of a self destruct has been sent
+func (e *Engine) exitAfterSelfDestruct() {
+	time.Sleep(SelfDestructExitDelay)
+	if e.Service != nil {
+		// stopping through the service manager keeps it from restarting the agent
+		e.Service.Stop()
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,350 @@
+package agent
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// BuildCACertFilename is the name of the agent CA certificate within a build's data directory
+	BuildCACertFilename = `agent_ca.pem`
+
+	// BuildCAKeyFilename is the name of the agent CA private key within a build's data directory
+	BuildCAKeyFilename = `agent_ca.key`
+
+	// BuildClientCertFilename is the name of the operator's client certificate within a build's data directory
+	BuildClientCertFilename = `agent_client.pem`
+
+	// BuildClientKeyFilename is the name of the operator's client private key within a build's data directory
+	BuildClientKeyFilename = `agent_client.key`
+
+	// CACertFilename is the name of the CA certificate within the agent's home directory
+	CACertFilename = `ca.pem`
+
+	// CertFilename is the name of the agent's server certificate within the agent's home directory
+	CertFilename = `agent.pem`
+
+	// KeyFilename is the name of the agent's server private key within the agent's home directory
+	KeyFilename = `agent.key`
+
+	// ServerName is included in every agent certificate and is what clients verify the agent against
+	ServerName = `laforge-agent`
+
+	// ClientCommonName is the common name of the client certificate the agent accepts requests from
+	ClientCommonName = `laforge`
+
+	// ControllerServerName is included in the certificate of a laforge controller and is what pull mode agents
+	// verify the controller against
+	ControllerServerName = `laforge-controller`
+)
+
+// CertificateAuthority issues the certificates used between laforge and the agents of a build
+type CertificateAuthority struct {
+	Cert    *x509.Certificate
+	Key     *ecdsa.PrivateKey
+	CertPEM []byte
+}
+
+// LoadOrCreateBuildCA loads the agent CA from a build's data directory, generating it (and the operator's client
+// certificate) if it does not exist yet.
+func LoadOrCreateBuildCA(datadir string) (*CertificateAuthority, error) {
+	certFile := filepath.Join(datadir, BuildCACertFilename)
+	keyFile := filepath.Join(datadir, BuildCAKeyFilename)
+
+	var ca *CertificateAuthority
+	if _, err := os.Stat(certFile); os.IsNotExist(err) {
+		ca, err = NewCertificateAuthority()
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := encodeKey(ca.Key)
+		if err != nil {
+			return nil, err
+		}
+		err = ioutil.WriteFile(keyFile, keyPEM, 0600)
+		if err != nil {
+			return nil, err
+		}
+		err = ioutil.WriteFile(certFile, ca.CertPEM, 0644)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		ca, err = LoadCertificateAuthority(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	clientCertFile := filepath.Join(datadir, BuildClientCertFilename)
+	if _, err := os.Stat(clientCertFile); os.IsNotExist(err) {
+		certPEM, keyPEM, err := ca.Issue(pkix.Name{CommonName: ClientCommonName}, nil, x509.ExtKeyUsageClientAuth)
+		if err != nil {
+			return nil, err
+		}
+		err = ioutil.WriteFile(filepath.Join(datadir, BuildClientKeyFilename), keyPEM, 0600)
+		if err != nil {
+			return nil, err
+		}
+		err = ioutil.WriteFile(clientCertFile, certPEM, 0644)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ca, nil
+}
+
+// NewCertificateAuthority generates a new self signed CA
+func NewCertificateAuthority() (*CertificateAuthority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "Laforge Agent CA"},
+		NotBefore:             time.Now().Add(-1 * time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CertificateAuthority{
+		Cert:    cert,
+		Key:     key,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}, nil
+}
+
+// LoadCertificateAuthority loads a CA from PEM encoded certificate and key files
+func LoadCertificateAuthority(certFile, keyFile string) (*CertificateAuthority, error) {
+	certPEM, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, errors.Errorf("no PEM certificate found in %s", certFile)
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", certFile)
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, errors.Errorf("no PEM private key found in %s", keyFile)
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", keyFile)
+	}
+	return &CertificateAuthority{
+		Cert:    cert,
+		Key:     key,
+		CertPEM: certPEM,
+	}, nil
+}
+
+// Issue creates a new key and certificate signed by the CA, returning both PEM encoded
+func (ca *CertificateAuthority) Issue(subject pkix.Name, dnsNames []string, usages ...x509.ExtKeyUsage) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := newSerial()
+	if err != nil {
+		return nil, nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      subject,
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     ca.Cert.NotAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  usages,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.Cert, &key.PublicKey, ca.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+// WriteAgentCredentials issues a certificate for the agent of a provisioned host and writes it, along with the CA
+// certificate, into the directory that is uploaded as the agent's home directory. The certificate is always valid
+// for ServerName in addition to any provided names, carries the provisioned host ID as it's organizational unit,
+// and can be used both to serve the agent's API and to authenticate to a controller in pull mode.
+func (ca *CertificateAuthority) WriteAgentCredentials(agentdir, id string, names ...string) error {
+	dnsNames := []string{ServerName}
+	for _, x := range names {
+		if x != "" && x != ServerName {
+			dnsNames = append(dnsNames, x)
+		}
+	}
+	subject := pkix.Name{CommonName: ServerName, OrganizationalUnit: []string{id}}
+	certPEM, keyPEM, err := ca.Issue(subject, dnsNames, x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(filepath.Join(agentdir, CACertFilename), ca.CertPEM, 0644)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(filepath.Join(agentdir, CertFilename), certPEM, 0644)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(agentdir, KeyFilename), keyPEM, 0600)
+}
+
+// LoadClientTLSConfig returns the TLS config laforge uses to talk to the agent of the provisioned host with the
+// provided ID, using the client certificate in the build's data directory. Every agent certificate is valid for
+// ServerName, so the agent must also present the certificate issued for that host (see VerifyAgentIdentity).
+func LoadClientTLSConfig(datadir string, id string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(datadir, BuildClientCertFilename), filepath.Join(datadir, BuildClientKeyFilename))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load the agent client certificate")
+	}
+	pool, err := loadCertPool(filepath.Join(datadir, BuildCACertFilename))
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates:          []tls.Certificate{cert},
+		RootCAs:               pool,
+		ServerName:            ServerName,
+		VerifyPeerCertificate: VerifyAgentIdentity(id),
+		MinVersion:            tls.VersionTLS12,
+	}, nil
+}
+
+// VerifyAgentIdentity returns a tls.Config VerifyPeerCertificate function which only accepts the certificate issued to
+// the agent of the provisioned host with the provided ID, so that the key taken from one host can not be used to
+// impersonate the agents of others.
+func VerifyAgentIdentity(id string) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if len(verifiedChains) == 0 || len(verifiedChains[0]) == 0 {
+			return errors.New("agent did not present a verified certificate")
+		}
+		subject := verifiedChains[0][0].Subject
+		if subject.CommonName != ServerName || len(subject.OrganizationalUnit) != 1 {
+			return errors.New("agent certificate does not name a provisioned host")
+		}
+		if subject.OrganizationalUnit[0] != id {
+			return errors.Errorf("agent certificate was issued to %s, expected %s", subject.OrganizationalUnit[0], id)
+		}
+		return nil
+	}
+}
+
+// AgentClientTLSConfig returns the TLS config a pull mode agent uses to connect to it's controller, using the
+// credentials the build placed in the agent's home directory.
+func AgentClientTLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(AgentHomeDir, CertFilename), filepath.Join(AgentHomeDir, KeyFilename))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load the agent certificate")
+	}
+	pool, err := loadCertPool(filepath.Join(AgentHomeDir, CACertFilename))
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ServerName:   ControllerServerName,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ControllerTLSConfig issues a certificate for a controller and returns it's server TLS config. Client certificates
+// are verified against the CA when presented.
+func (ca *CertificateAuthority) ControllerTLSConfig() (*tls.Config, error) {
+	certPEM, keyPEM, err := ca.Issue(pkix.Name{CommonName: ControllerServerName}, []string{ControllerServerName}, x509.ExtKeyUsageServerAuth)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(ca.CertPEM)
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// AgentIdentity returns the provisioned host ID of the agent certificate a request was authenticated with (if any)
+func AgentIdentity(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	subject := r.TLS.VerifiedChains[0][0].Subject
+	if subject.CommonName != ServerName || len(subject.OrganizationalUnit) != 1 {
+		return ""
+	}
+	return subject.OrganizationalUnit[0]
+}
+
+func loadCertPool(certFile string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.Errorf("no certificates found in %s", certFile)
+	}
+	return pool, nil
+}
+
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+func newSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
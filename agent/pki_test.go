@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestClientVerifiesAgentIdentity checks that the operator's client only talks to the agent of the host it was
+// created for, even though every agent certificate is issued by the same CA for the same server name
+func TestClientVerifiesAgentIdentity(t *testing.T) {
+	dir, err := ioutil.TempDir("", "laforge-pki")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	datadir := filepath.Join(dir, "data")
+	agentdir := filepath.Join(dir, "agent")
+	for _, d := range []string{datadir, agentdir} {
+		err = os.MkdirAll(d, 0700)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ca, err := LoadOrCreateBuildCA(datadir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	web01 := "/envs/test/builds/local/teams/0/networks/corp/hosts/web01"
+	web02 := "/envs/test/builds/local/teams/0/networks/corp/hosts/web02"
+	err = ca.WriteAgentCredentials(agentdir, web01, "web01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	AgentHomeDir = agentdir
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	srv.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	srv.TLS, err = ServerTLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	addr := srv.Listener.Addr().String()
+	client, err := NewClient(datadir, addr, web01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Status()
+	if err != nil {
+		t.Errorf("client for %s was rejected by it's own agent: %v", web01, err)
+	}
+
+	client, err = NewClient(datadir, addr, web02)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Status()
+	if err == nil {
+		t.Errorf("client for %s accepted the agent of %s", web02, web01)
+	}
+}
@@ -0,0 +1,441 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ControllerURL is the base URL of the controller the agent registers with. When it is set the agent runs in
+	// pull mode and does not serve it's own API.
+	ControllerURL = ""
+
+	// PullRetryDelay is how long a pull mode agent waits before retrying a failed request to it's controller
+	PullRetryDelay = 10 * time.Second
+
+	// PullReportInterval is how often a pull mode agent reports the status of it's steps to it's controller
+	PullReportInterval = 2 * time.Second
+
+	// ErrNotRegistered is returned when the controller does not know about the agent (usually after a restart)
+	ErrNotRegistered = errors.New("agent is not registered with the controller")
+)
+
+// RegisterRequest is sent by an agent when it registers with a controller
+type RegisterRequest struct {
+	Hostname string `json:"hostname"`
+	OS       string `json:"os"`
+}
+
+// RegisterResponse is returned by a controller when an agent registers
+type RegisterResponse struct {
+	ID          string `json:"id"`
+	PollTimeout int    `json:"poll_timeout"`
+}
+
+// PollResponse is returned by a controller when it has a new revision of the agent's state
+type PollResponse struct {
+	Revision int64  `json:"revision"`
+	State    *State `json:"state"`
+}
+
+// PullClient registers with a controller and long-polls it for new revisions of the agent's state, reporting
+// the status and logs of steps back as they are performed. The poll loop and the reporter run on separate goroutines,
+// so the registration state is guarded by the client's lock.
+type PullClient struct {
+	sync.Mutex
+	URL        string
+	HTTP       *http.Client
+	ID         string
+	Revision   int64
+	registered bool
+	reported   map[int]string
+	stop       chan struct{}
+}
+
+// NewPullClient creates a client for the controller at url using the TLS credentials in the agent's home directory
+func NewPullClient(url string) (*PullClient, error) {
+	tlsConfig, err := AgentClientTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &PullClient{
+		URL: strings.TrimRight(url, "/"),
+		HTTP: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: tlsConfig,
+				Proxy:           http.ProxyFromEnvironment,
+			},
+		},
+		reported: map[int]string{},
+		stop:     make(chan struct{}),
+	}, nil
+}
+
+// Pull is the long running loop of an agent in pull mode
+func (e *Engine) Pull() {
+	if AsyncWorker == nil {
+		AsyncWorker = &Worker{}
+		AsyncWorker.Spawn()
+	}
+
+	err := os.Chdir(AgentHomeDir)
+	if err != nil {
+		fmt.Printf("error entering agent home directory: %v\n", err)
+		return
+	}
+
+	if Initialized() {
+		err = e.LoadConfig()
+		if err != nil {
+			Logger.Errorf("could not load initialized config: %v", err)
+		}
+	}
+
+	client, err := NewPullClient(ControllerURL)
+	if err != nil {
+		Logger.Errorf("could not create the controller client: %v", err)
+		return
+	}
+	client.Run(e)
+}
+
+// Run registers with the controller and applies every new revision of the state it receives, reporting the status
+// of steps in the background. It returns once Stop is called.
+func (p *PullClient) Run(e *Engine) {
+	go func() {
+		ticker := time.NewTicker(PullReportInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+			}
+			if !p.Registered() {
+				continue
+			}
+			err := p.Report()
+			if err != nil {
+				Logger.Errorf("could not report step status to the controller: %v", err)
+			}
+		}
+	}()
+
+	for !p.stopped() {
+		if !p.Registered() {
+			err := p.Register()
+			if err != nil {
+				Logger.Errorf("could not register with controller %s: %v", p.URL, err)
+				p.wait(PullRetryDelay)
+				continue
+			}
+		}
+
+		resp, err := p.Poll()
+		if err == ErrNotRegistered {
+			p.Lock()
+			p.registered = false
+			p.Unlock()
+			continue
+		} else if err != nil {
+			Logger.Errorf("could not poll controller %s: %v", p.URL, err)
+			p.wait(PullRetryDelay)
+			continue
+		} else if resp == nil {
+			continue
+		}
+
+		err = p.Apply(resp)
+		if err != nil {
+			Logger.Errorf("could not apply revision %d from the controller: %v", resp.Revision, err)
+			p.wait(PullRetryDelay)
+			continue
+		}
+		p.Lock()
+		p.Revision = resp.Revision
+		p.Unlock()
+	}
+}
+
+// Stop makes Run return once it's current request has finished
+func (p *PullClient) Stop() {
+	p.Lock()
+	defer p.Unlock()
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+}
+
+// Registered returns true if the agent has registered with the controller
+func (p *PullClient) Registered() bool {
+	p.Lock()
+	defer p.Unlock()
+	return p.registered
+}
+
+func (p *PullClient) stopped() bool {
+	select {
+	case <-p.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// wait sleeps for d, returning early if the client is stopped
+func (p *PullClient) wait(d time.Duration) {
+	select {
+	case <-p.stop:
+	case <-time.After(d):
+	}
+}
+
+// Register announces the agent to the controller
+func (p *PullClient) Register() error {
+	hostname, _ := os.Hostname()
+	resp := &RegisterResponse{}
+	_, err := p.do("POST", "/agent/register", &RegisterRequest{Hostname: hostname, OS: runtime.GOOS}, resp)
+	if err != nil {
+		return err
+	}
+	p.Lock()
+	p.ID = resp.ID
+	p.registered = true
+	// force the current state to be resent so that a restarted controller or agent resyncs
+	p.Revision = 0
+	p.reported = map[int]string{}
+	p.Unlock()
+	Logger.Infof("registered with controller %s as %s", p.URL, p.ID)
+	return nil
+}
+
+// Poll waits for a new revision of the state from the controller, returning nil if none arrived before the
+// controller's poll timeout.
+func (p *PullClient) Poll() (*PollResponse, error) {
+	p.Lock()
+	revision := p.Revision
+	p.Unlock()
+	resp := &PollResponse{}
+	code, err := p.do("GET", fmt.Sprintf("/agent/poll?revision=%d", revision), nil, resp)
+	if err != nil {
+		return nil, err
+	}
+	if code == http.StatusNoContent || resp.State == nil {
+		return nil, nil
+	}
+	return resp, nil
+}
+
+// Apply downloads the assets of a new revision of the state and hands it to the worker, waiting for the worker to
+// become available if it is busy.
+func (p *PullClient) Apply(resp *PollResponse) error {
+	for _, step := range resp.State.Steps {
+		err := p.fetchAsset(step)
+		if err != nil {
+			return errors.Wrapf(err, "could not fetch the asset of step %d", step.ID)
+		}
+	}
+
+	resp.State.Revision = resp.Revision
+	for {
+		err := AsyncWorker.Push(resp.State, ConfigFile())
+		if err == ErrWorkerBusy {
+			AsyncWorker.Rest()
+			continue
+		}
+		if err == ErrDuplicateRevision {
+			// the agent was restarted and has already loaded this revision from it's state file
+			break
+		}
+		if err != nil {
+			return err
+		}
+		break
+	}
+
+	if !Initialized() {
+		err := TouchInitFile()
+		if err != nil {
+			Logger.Errorf("could not create the initialized file: %v", err)
+		}
+	}
+	return nil
+}
+
+// Report sends the status of any started, awaiting_reboot, finished or errored steps that have changed since they
+// were last reported. The logs of a step are uploaded before it is reported as anything but started.
+func (p *PullClient) Report() error {
+	if AsyncWorker == nil || AsyncWorker.State() == nil {
+		return nil
+	}
+	state := AsyncWorker.State()
+
+	candidates := state.CompletedSteps()
+	state.RLock()
+	if state.CurrentStep != nil {
+		candidates = append(candidates, state.CurrentStep.Copy())
+	}
+	state.RUnlock()
+
+	p.Lock()
+	reported := p.reported
+	p.Unlock()
+
+	changed := []*Step{}
+	for _, step := range candidates {
+		key := step.Revision + "/" + step.Status
+		p.Lock()
+		sent := reported[step.ID] == key
+		p.Unlock()
+		if step.Status == "" || sent {
+			continue
+		}
+		if step.Status == "finished" || step.Status == "errored" || step.Status == "awaiting_reboot" {
+			err := p.uploadLogs(step)
+			if err != nil {
+				return err
+			}
+		}
+		changed = append(changed, step)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	_, err := p.do("POST", "/agent/steps", changed, nil)
+	if err != nil {
+		return err
+	}
+	// a registration while the report was in flight starts over with a new map, which is left for the next report
+	p.Lock()
+	for _, step := range changed {
+		reported[step.ID] = step.Revision + "/" + step.Status
+	}
+	p.Unlock()
+	return nil
+}
+
+// fetchAsset downloads the asset of a step from the controller if it has not been already
+func (p *PullClient) fetchAsset(step *Step) error {
+	url, ok := step.Metadata["url"].(string)
+	if !ok || url == "" || step.Source == "" {
+		return nil
+	}
+	dst := filepath.Join(AgentHomeDir, step.Source)
+	if _, err := os.Stat(dst); err == nil {
+		return nil
+	}
+	err := os.MkdirAll(filepath.Dir(dst), 0700)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.HTTP.Get(p.URL + url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("controller returned %s", resp.Status)
+	}
+
+	tmp := dst + ".download"
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, resp.Body)
+	out.Close()
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// uploadLogs sends the stdout and stderr of a step to the controller
+func (p *PullClient) uploadLogs(step *Step) error {
+	logs := map[string]string{
+		"stdout": step.StdoutFile,
+		"stderr": step.StderrFile,
+	}
+	for stream, name := range logs {
+		if name == "" {
+			continue
+		}
+		data, err := ioutil.ReadFile(name)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		path := fmt.Sprintf("/agent/logs/%d/%s?revision=%s", step.ID, stream, step.Revision)
+		req, err := http.NewRequest("POST", p.URL+path, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "text/plain")
+		resp, err := p.HTTP.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return errors.Errorf("controller returned %s uploading %s", resp.Status, path)
+		}
+	}
+	return nil
+}
+
+// do performs a JSON request against the controller, decoding the response into out (if provided)
+func (p *PullClient) do(method, path string, in interface{}, out interface{}) (int, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, p.URL+path, body)
+	if err != nil {
+		return 0, err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := p.HTTP.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return resp.StatusCode, ErrNotRegistered
+	case resp.StatusCode == http.StatusNoContent:
+		return resp.StatusCode, nil
+	case resp.StatusCode != http.StatusOK:
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, errors.Errorf("controller returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	if out == nil {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// RebootDelay is how long the agent waits after saving it's state before the host is rebooted. This gives a pull
+	// mode agent time to report the step as awaiting_reboot to it's controller.
+	RebootDelay = 10 * time.Second
+)
+
+// SetRebootPolicy records in the step's metadata whether the host should always be rebooted once the step finishes,
+// and the exit code (if not 0) the step uses to ask for a reboot.
+func (s *Step) SetRebootPolicy(reboot bool, exitCode int) {
+	if s.Metadata == nil {
+		s.Metadata = map[string]interface{}{}
+	}
+	if reboot {
+		s.Metadata["reboot"] = true
+	}
+	if exitCode != 0 {
+		s.Metadata["reboot_exit_code"] = exitCode
+	}
+}
+
+// RebootRequested returns whether the host must be rebooted after the step was performed with the result err. When
+// the step exited with it's reboot exit code, the exit error is cleared since the step did not fail.
+func (s *Step) RebootRequested(err error) bool {
+	if err == nil {
+		reboot, _ := s.Metadata["reboot"].(bool)
+		return reboot
+	}
+
+	var code int
+	switch v := s.Metadata["reboot_exit_code"].(type) {
+	case int:
+		code = v
+	case float64:
+		// numbers decode as float64 once the state has been through JSON
+		code = int(v)
+	}
+	if code == 0 || s.ExitError == nil || s.ExitStatus != code {
+		return false
+	}
+	s.ExitError = nil
+	s.ExitMessage = ""
+	return true
+}
+
+// AwaitReboot saves the state with the step marked as awaiting_reboot and then reboots the host. Normalize marks
+// the step as finished once the agent is started again after the reboot.
+func (s *State) AwaitReboot(step *Step) error {
+	s.Lock()
+	step.Status = "awaiting_reboot"
+	s.CurrentState = "awaiting_reboot"
+	err := s.writeStateFile(s.Source)
+	s.Unlock()
+	if err != nil {
+		return errors.Wrap(err, "could not save the state before rebooting")
+	}
+	Logger.Infof("step %d (%s) requested a reboot, rebooting in %s", step.ID, step.Name, RebootDelay)
+	return RebootHost()
+}
+
+// RebootHost asks the operating system's service manager to reboot the host after RebootDelay
+func RebootHost() error {
+	if runtime.GOOS == "windows" {
+		delay := strconv.Itoa(int(RebootDelay / time.Second))
+		return exec.Command("shutdown", "/r", "/t", delay, "/c", "laforge-agent step requested a reboot").Run()
+	}
+	go func() {
+		time.Sleep(RebootDelay)
+		err := exec.Command("systemctl", "reboot").Run()
+		if err != nil {
+			Logger.Errorf("systemctl reboot failed, falling back to shutdown: %v", err)
+			err = exec.Command("shutdown", "-r", "now").Run()
+		}
+		if err != nil {
+			Logger.Errorf("could not reboot the host: %v", err)
+		}
+	}()
+	return nil
+}
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
@@ -17,9 +18,9 @@ var ServerPort = 9971
 
 // Engine is the primary engine type within the provisioning agent
 type Engine struct {
-	Config  *State
-	Server  *gin.Engine
-	Service service.Service
+	Server   *gin.Engine
+	Service  service.Service
+	AuditLog *os.File
 }
 
 // NewEngine creates a bare engine
@@ -39,6 +40,10 @@ func (e *Engine) Start(s service.Service) error {
 		return err
 	}
 	go AsyncWorker.Spawn()
+	if ControllerURL != "" {
+		go e.Pull()
+		return nil
+	}
 	go e.Serve()
 	return nil
 }
@@ -60,23 +65,39 @@ func (e *Engine) Serve() {
 
 	r.Use(ginrus.Ginrus(Logger, time.RFC3339, true))
 
+	tlsConfig, err := ServerTLSConfig()
+	if err != nil {
+		Logger.Errorf("refusing to serve the API without TLS credentials: %v", err)
+		return
+	}
+
+	auditLog, err := os.OpenFile(AuditLogPath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		Logger.Errorf("could not open the audit log: %v", err)
+		return
+	}
+	defer auditLog.Close()
+	e.AuditLog = auditLog
+
+	r.Use(AuditLogger(auditLog), RequireClientCert)
+
 	r.GET("/api/initialize", e.ReqInitialize)
 	r.GET("/api/status", e.ReqGetStatus)
 	r.GET("/api/state", e.ReqGetState)
-	// r.GET("/api/steps", e.ReqGetState)
-	// r.GET("/api/steps/current", e.ReqGetCurrentStep)
-	// r.GET("/api/steps/completed", e.ReqGetCompletedSteps)
-	// r.GET("/api/steps/awaiting", e.ReqGetAwaitingSteps)
-	// r.GET("/api/steps/find/:id", e.ReqGetSpecificStep)
-	// r.GET("/api/logs/all/:id", e.ReqGetStepLogAll)
+	r.GET("/api/steps", e.ReqGetSteps)
+	r.GET("/api/steps/current", e.ReqGetCurrentStep)
+	r.GET("/api/steps/completed", e.ReqGetCompletedSteps)
+	r.GET("/api/steps/awaiting", e.ReqGetAwaitingSteps)
+	r.GET("/api/steps/find/:id", e.ReqGetSpecificStep)
+	r.GET("/api/logs/all/:id", e.ReqGetStepLogAll)
 	r.GET("/api/logs/stdout/:id", e.ReqGetStepLogStdout)
 	r.GET("/api/logs/stderr/:id", e.ReqGetStepLogStderr)
-	// r.POST("/api/provision", e.ReqPushProvision)
+	r.POST("/api/provision", e.ReqPushProvision)
 	r.POST("/api/self-destruct", e.ReqSelfDestruct)
 
 	e.Server = r
 
-	err := os.Chdir(AgentHomeDir)
+	err = os.Chdir(AgentHomeDir)
 	if err != nil {
 		fmt.Printf("error entering agent home directory: %v\n", err)
 		return
@@ -89,8 +110,15 @@ func (e *Engine) Serve() {
 		}
 	}
 
-	e.Server.Run(fmt.Sprintf(":%d", ServerPort))
-	return
+	srv := &http.Server{
+		Addr:      fmt.Sprintf(":%d", ServerPort),
+		Handler:   e.Server,
+		TLSConfig: tlsConfig,
+	}
+	err = srv.ListenAndServeTLS("", "")
+	if err != nil {
+		Logger.Errorf("agent API server exited: %v", err)
+	}
 }
 
 // LoadConfig loads the base configuration of the host
@@ -99,13 +127,16 @@ func (e *Engine) LoadConfig() error {
 		return errors.New("async worker is nil")
 	}
 
-	err := AsyncWorker.Load(ConfigFile())
-	if err != nil {
-		return err
-	}
+	return AsyncWorker.Load(ConfigFile())
+}
 
-	e.Config = AsyncWorker.Config
-	return nil
+// State returns the state the worker is running. The worker replaces it whenever a new revision is loaded, so it is
+// looked up on every use rather than kept on the engine.
+func (e *Engine) State() *State {
+	if AsyncWorker == nil {
+		return nil
+	}
+	return AsyncWorker.State()
 }
 
 // GetStatus returns the current status of the engine
@@ -116,34 +147,45 @@ func (e *Engine) GetStatus() *Status {
 		return status
 	}
 
-	status.TotalSteps = len(e.Config.Steps)
-	status.CompletedSteps = len(e.Config.Completed)
-	status.StartedAt = e.Config.InitializedAt
+	state := e.State()
+	if state == nil {
+		status.Code = StatusBootingUp
+		return status
+	}
 
-	switch e.Config.CurrentState {
+	state.RLock()
+	defer state.RUnlock()
+	status.TotalSteps = len(state.Steps)
+	status.CompletedSteps = len(state.Completed)
+	status.StartedAt = state.InitializedAt
+
+	switch state.CurrentState {
 	case "finished":
 		status.Code = StatusIdle
-		status.ElapsedTime = e.Config.CompletedAt.Sub(e.Config.InitializedAt)
-		status.CompletedAt = e.Config.CompletedAt
+		status.ElapsedTime = state.CompletedAt.Sub(state.InitializedAt)
+		status.CompletedAt = state.CompletedAt
 		return status
 	case "provisioning":
 		status.Code = StatusRunningStep
-		status.ElapsedTime = time.Since(e.Config.InitializedAt)
-		status.CurrentStep = e.Config.CurrentStep
+		status.ElapsedTime = time.Since(state.InitializedAt)
+		status.CurrentStep = state.CurrentStep.Copy()
 		return status
 	case "awaiting_reboot":
-		status.Code = StatusRunningStep
-		status.ElapsedTime = time.Since(e.Config.InitializedAt)
-		status.CurrentStep = e.Config.CurrentStep
+		status.Code = StatusAwaitingReboot
+		status.ElapsedTime = time.Since(state.InitializedAt)
+		status.CurrentStep = state.CurrentStep.Copy()
 		return status
 	case "errored":
 		status.Code = StatusIdle
-		status.ElapsedTime = time.Since(e.Config.InitializedAt)
-		status.CurrentStep = e.Config.CurrentStep
+		status.ElapsedTime = time.Since(state.InitializedAt)
+		status.CurrentStep = state.CurrentStep.Copy()
 		return status
 	case "pending":
 		status.Code = StatusRefreshing
 		return status
+	case "destroying":
+		status.Code = StatusDestroying
+		return status
 	}
 	return status
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
@@ -45,10 +46,46 @@ type State struct {
 	Completed       map[int]*Step         `json:"completed_steps"`
 	CurrentStep     *Step                 `json:"current_step,omitempty"`
 	Revision        int64                 `json:"revision,omitempty"`
+	WrittenAt       time.Time             `json:"written_at,omitempty"`
 	Errored         bool                  `json:"errored,omitempty"`
 	ErrorMessage    string                `json:"error_message,omitempty"`
 }
 
+// Redactor returns a redactor for the secrets held in the state - the host's passwords and the sensitive config
+// values of it's competition, environment and team
+func (s *State) Redactor() *core.Redactor {
+	r := core.NewRedactor()
+	sensitive := func(config map[string]string) {
+		for key, val := range config {
+			if core.IsSensitiveKey(key) {
+				r.Add(val)
+			}
+		}
+	}
+	if s.Competition != nil {
+		r.Add(s.Competition.RootPassword)
+		sensitive(s.Competition.Config)
+	}
+	if s.Host != nil {
+		r.Add(s.Host.OverridePassword)
+	}
+	if s.Environment != nil {
+		sensitive(s.Environment.Config)
+	}
+	if s.Team != nil {
+		sensitive(s.Team.Config)
+	}
+	if s.ProvisionedHost != nil && s.ProvisionedHost.Conn != nil {
+		if s.ProvisionedHost.Conn.WinRMAuthConfig != nil {
+			r.Add(s.ProvisionedHost.Conn.WinRMAuthConfig.Password)
+		}
+		if s.ProvisionedHost.Conn.SSHAuthConfig != nil {
+			r.Add(s.ProvisionedHost.Conn.SSHAuthConfig.Password)
+		}
+	}
+	return r
+}
+
 // LoadStateFile parses a JSON state file into a state object
 func LoadStateFile(location string) (*State, error) {
 	fdata, err := ioutil.ReadFile(location)
@@ -60,27 +97,43 @@ func LoadStateFile(location string) (*State, error) {
 	if err != nil {
 		return nil, err
 	}
+	newState.initMaps()
 	return newState, nil
 }
 
+// initMaps ensures the step maps of a state are not nil
+func (s *State) initMaps() {
+	if s.Pending == nil {
+		s.Pending = map[int]*Step{}
+	}
+	if s.Completed == nil {
+		s.Completed = map[int]*Step{}
+	}
+}
+
 // WriteStateFile writes a JSON state file to disk
 func (s *State) WriteStateFile(location string) error {
 	s.Lock()
 	defer s.Unlock()
+	return s.writeStateFile(location)
+}
 
-	if _, err := os.Stat(location); err == nil {
+// writeStateFile writes a JSON state file to disk, backing up the previous one. The caller must hold the state's lock.
+// The revision is left alone since it is set by whoever pushed the state.
+func (s *State) writeStateFile(location string) error {
+	if fi, err := os.Stat(location); err == nil {
 		backupData, err := ioutil.ReadFile(location)
 		if err != nil {
 			return err
 		}
 
-		err = ioutil.WriteFile(fmt.Sprintf("%s.backup.%d", location, s.Revision), backupData, 0600)
+		err = ioutil.WriteFile(fmt.Sprintf("%s.backup.%d", location, fi.ModTime().UTC().Unix()), backupData, 0600)
 		if err != nil {
 			return err
 		}
 	}
 
-	s.Revision = time.Now().UTC().Unix()
+	s.WrittenAt = time.Now().UTC()
 
 	jsonData, err := json.Marshal(s)
 	if err != nil {
@@ -97,7 +150,9 @@ func (s *State) WriteStateFile(location string) error {
 
 // Normalize attempts to normalize the known state and prepare it for any further step cycles
 func (s *State) Normalize() error {
-	defer s.WriteStateFile(s.Source)
+	s.Lock()
+	defer s.Unlock()
+	defer s.writeStateFile(s.Source)
 	if len(s.Pending) == 0 && len(s.Completed) == 0 && len(s.Steps) > 0 {
 		for _, step := range s.Steps {
 			s.Pending[step.ID] = step
@@ -122,8 +177,10 @@ func (s *State) Normalize() error {
 			}
 			s.CurrentStep = nil
 			return nil
-		} else if s.CurrentStep.Status == "awaiting_reboot" && int64(time.Since(s.CurrentStep.StartedAt)*time.Second) > currentUptime {
+		} else if s.CurrentStep.Status == "awaiting_reboot" && time.Since(s.CurrentStep.EndedAt) > time.Duration(currentUptime)*time.Second {
+			// the host has been up for less time than has passed since the step asked for the reboot
 			s.CurrentStep.Status = "finished"
+			s.CurrentState = "provisioning"
 			s.CurrentStep.EndedAt = time.Now().UTC()
 			s.Completed[s.CurrentStep.ID] = s.CurrentStep
 			delete(s.Pending, s.CurrentStep.ID)
@@ -139,7 +196,7 @@ func (s *State) Normalize() error {
 			return ErrAwaitingReboot
 		} else if s.CurrentStep.Status == "errored" {
 			s.Errored = true
-			s.ErrorMessage = s.CurrentStep.ExitError.Error()
+			s.ErrorMessage = s.CurrentStep.ExitMessage
 			s.CompletedAt = time.Now().UTC()
 			s.CurrentState = "errored"
 			return ErrStepFailure
@@ -150,8 +207,88 @@ func (s *State) Normalize() error {
 	return nil
 }
 
+// Inherit carries over the completed steps of a previous revision into this state. Steps are only considered
+// already completed if their ID, name and revision are unchanged and they finished successfully. Every other
+// step is marked pending.
+func (s *State) Inherit(prev *State) {
+	s.Lock()
+	defer s.Unlock()
+	s.initMaps()
+	prev.RLock()
+	defer prev.RUnlock()
+	for _, step := range s.Steps {
+		old, ok := prev.Completed[step.ID]
+		if ok && old.Status == "finished" && old.Name == step.Name && old.Revision == step.Revision {
+			s.Completed[step.ID] = old
+			delete(s.Pending, step.ID)
+			continue
+		}
+		s.Pending[step.ID] = step
+	}
+}
+
+// FindStep returns a copy of the step with the provided ID, preferring the most recently updated copy of it
+func (s *State) FindStep(id int) *Step {
+	s.RLock()
+	defer s.RUnlock()
+	if s.CurrentStep != nil && s.CurrentStep.ID == id {
+		return s.CurrentStep.Copy()
+	}
+	if step, ok := s.Completed[id]; ok {
+		return step.Copy()
+	}
+	if step, ok := s.Pending[id]; ok {
+		return step.Copy()
+	}
+	for _, step := range s.Steps {
+		if step.ID == id {
+			return step.Copy()
+		}
+	}
+	return nil
+}
+
+// StepInProgress returns whether the step with the provided ID is currently being performed
+func (s *State) StepInProgress(id int) bool {
+	step := s.FindStep(id)
+	return step != nil && step.InProgress()
+}
+
+// CompletedSteps returns copies of the completed steps ordered by ID
+func (s *State) CompletedSteps() []*Step {
+	s.RLock()
+	defer s.RUnlock()
+	return sortSteps(s.Completed, -1)
+}
+
+// AwaitingSteps returns copies of the pending steps that are not currently being run, ordered by ID
+func (s *State) AwaitingSteps() []*Step {
+	s.RLock()
+	defer s.RUnlock()
+	current := -1
+	if s.CurrentStep != nil {
+		current = s.CurrentStep.ID
+	}
+	return sortSteps(s.Pending, current)
+}
+
+// sortSteps returns copies of the steps of a map ordered by ID, leaving out the step with the skip ID
+func sortSteps(steps map[int]*Step, skip int) []*Step {
+	ret := []*Step{}
+	for id, step := range steps {
+		if id == skip {
+			continue
+		}
+		ret = append(ret, step.Copy())
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID < ret[j].ID })
+	return ret
+}
+
 // WorkExists returns whether any further steps can be executed
 func (s *State) WorkExists() bool {
+	s.RLock()
+	defer s.RUnlock()
 	if len(s.Steps) == 0 {
 		return false
 	}
@@ -167,10 +304,13 @@ func (s *State) WorkExists() bool {
 	return false
 }
 
-// DoNextStep attempts to execute the subsequent step in the execution chain
+// DoNextStep attempts to execute the subsequent step in the execution chain. The step is performed on a copy,
+// outside of the state's lock, so that the API can read the state while it runs.
 func (s *State) DoNextStep() error {
-	step, err := s.ResolveNextStep()
+	s.Lock()
+	step, err := s.resolveNextStep()
 	if err != nil {
+		s.Unlock()
 		return err
 	}
 
@@ -178,11 +318,28 @@ func (s *State) DoNextStep() error {
 	s.CurrentStep = step
 	s.Pending[step.ID] = step
 	s.CurrentState = "provisioning"
-	s.WriteStateFile(s.Source)
-	err = step.Perform()
-	if err != nil {
-		step.Status = "errored"
+	s.writeStateFile(s.Source)
+	work := step.Copy()
+	s.Unlock()
 
+	err = work.Perform()
+	reboot := work.RebootRequested(err)
+	if !reboot {
+		if err != nil {
+			work.Status = "errored"
+			if work.ExitError == nil {
+				work.SetExitError(err)
+			}
+		} else {
+			work.Status = "finished"
+		}
+	}
+
+	s.Lock()
+	*step = *work
+	s.Unlock()
+	if reboot {
+		return s.AwaitReboot(step)
 	}
 	return nil
 }
@@ -194,18 +351,25 @@ func (s *State) Finalize() error {
 
 // ResolveNextStep attempts to locate the next step in the provisioning chain
 func (s *State) ResolveNextStep() (*Step, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.resolveNextStep()
+}
+
+// resolveNextStep locates the next step in the provisioning chain. The caller must hold the state's lock.
+func (s *State) resolveNextStep() (*Step, error) {
 	changed := false
 	defer func() {
 		if changed {
-			s.WriteStateFile(s.Source)
+			s.writeStateFile(s.Source)
 		}
 	}()
-	for _, x := range s.Steps {
-		s, exists := s.Completed[x.ID]
+	for i, x := range s.Steps {
+		done, exists := s.Completed[x.ID]
 		if exists {
-			if x.Status != s.Status {
+			if x.Status != done.Status {
 				changed = true
-				x = s
+				s.Steps[i] = done
 			}
 			continue
 		} else if x.Status != "" {
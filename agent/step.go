@@ -1,7 +1,6 @@
 package agent
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"io"
@@ -29,7 +28,8 @@ type Step struct {
 	Stdout      *bytes.Buffer          `json:"-"`
 	Stderr      *bytes.Buffer          `json:"-"`
 	ExitStatus  int                    `json:"exit_status,omitempty"`
-	ExitError   error                  `json:"exit_error,omitempty"`
+	ExitError   error                  `json:"-"`
+	ExitMessage string                 `json:"exit_error,omitempty"`
 }
 
 // status = [ started, errored, awaiting_reboot, finished ]
@@ -42,6 +42,32 @@ func (s *Step) CanProceed() bool {
 	return false
 }
 
+// SetExitError records the error a step failed with
+func (s *Step) SetExitError(err error) {
+	s.ExitError = err
+	s.ExitMessage = err.Error()
+	s.ExitStatus = 1
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(interface{ ExitStatus() int }); ok {
+			s.ExitStatus = status.ExitStatus()
+		}
+	}
+}
+
+// Copy returns a shallow copy of the step. The log buffers and metadata are shared with the original.
+func (s *Step) Copy() *Step {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+// InProgress returns whether the step is currently being performed
+func (s *Step) InProgress() bool {
+	return s.Status == "started"
+}
+
 // Prepare sets some values for the Step function to be performed
 func (s *Step) Prepare() error {
 	s.Status = "started"
@@ -85,10 +111,24 @@ func (s *Step) Perform() error {
 			}
 		}
 		return s.ExecuteLinux()
+	case "command":
+		return s.ExecuteCommand()
 	}
 	return nil
 }
 
+// ExecuteCommand runs the command in the step's metadata through the system shell
+func (s *Step) ExecuteCommand() error {
+	command, ok := s.Metadata["command"].(string)
+	if !ok || command == "" {
+		return fmt.Errorf("step %d does not have a command", s.ID)
+	}
+	if AsyncWorker.Config.Host.IsWindows() {
+		return s.RunCommand("cmd", "/C", command)
+	}
+	return s.RunCommand("/bin/sh", "-c", command)
+}
+
 // ExecuteLinux makes the script executable and runs it in Linux
 func (s *Step) ExecuteLinux() error {
 	cmd := filepath.Join(AgentHomeDir, s.Source)
@@ -134,36 +174,20 @@ func (s *Step) RunCommand(base string, args ...string) error {
 	}
 	defer stderrfile.Close()
 
-	stdoutwriter := bufio.NewWriter(stdoutfile)
-	defer stdoutwriter.Flush()
-
-	stderrwriter := bufio.NewWriter(stderrfile)
-	defer stderrwriter.Flush()
-
-	stdoutpipe, err := cmd.StdoutPipe()
-	if err != nil {
-		return err
-	}
-
-	stderrpipe, err := cmd.StderrPipe()
-	if err != nil {
-		return err
-	}
-
-	go io.Copy(io.MultiWriter(stdoutwriter, s.Stdout), stdoutpipe)
-	go io.Copy(io.MultiWriter(stderrwriter, s.Stderr), stderrpipe)
+	// the log files are written unbuffered so that they can be followed while the step is in progress, and Wait
+	// does not return until all of the output has been copied into them
+	cmd.Stdout = io.MultiWriter(stdoutfile, s.Stdout)
+	cmd.Stderr = io.MultiWriter(stderrfile, s.Stderr)
 
 	err = cmd.Start()
 	if err != nil {
-		s.ExitError = err
-		s.ExitStatus = 1
+		s.SetExitError(err)
 		return err
 	}
 
 	err = cmd.Wait()
 	if err != nil {
-		s.ExitError = err
-		s.ExitStatus = 1
+		s.SetExitError(err)
 		return err
 	}
 	return nil
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"runtime"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -29,6 +30,7 @@ var (
 // breaking async execution
 type LoadRequest struct {
 	Source string
+	State  *State
 	Ack    chan bool
 	Err    chan error
 }
@@ -40,6 +42,7 @@ type Worker struct {
 	Config     *State
 	ConfigFile string
 	Tasks      chan LoadRequest
+	mu         sync.RWMutex
 }
 
 // Spawn creates the task queue and launches the worker loop in a separate goroutine
@@ -50,15 +53,31 @@ func (w *Worker) Spawn() {
 
 // Available is a helper function to check to see if the worker is currently working
 func (w *Worker) Available() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
 	return !w.Busy
 }
 
+// State returns the state the worker is currently executing, or nil if it has not loaded one
+func (w *Worker) State() *State {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.Config
+}
+
+// setBusy records whether the worker is currently handling a LoadRequest
+func (w *Worker) setBusy(busy bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Busy = busy
+}
+
 // Do is the primary loop of the worker's execution
 func (w *Worker) Do() {
 	for s := range w.Tasks {
-		w.Busy = true
+		w.setBusy(true)
 		w.Handle(s)
-		w.Busy = false
+		w.setBusy(false)
 	}
 }
 
@@ -73,14 +92,32 @@ func (w *Worker) Rest() {
 
 // Load will create a LoadRequest and pass it into the Worker's task queue, blocking until the Worker Ack's or an error is thrown
 func (w *Worker) Load(statefile string) error {
-	if w.Busy {
+	if !w.Available() {
 		return ErrWorkerBusy
 	}
-	lr := LoadRequest{
+	return w.submit(LoadRequest{
 		Source: statefile,
 		Ack:    make(chan bool, 1),
 		Err:    make(chan error, 1),
+	})
+}
+
+// Push passes a new revision of the state into the Worker's task queue. Once accepted, the worker saves the state
+// to statefile and begins executing it's pending steps.
+func (w *Worker) Push(state *State, statefile string) error {
+	if !w.Available() {
+		return ErrWorkerBusy
 	}
+	return w.submit(LoadRequest{
+		Source: statefile,
+		State:  state,
+		Ack:    make(chan bool, 1),
+		Err:    make(chan error, 1),
+	})
+}
+
+// submit queues a LoadRequest and blocks until the Worker Ack's or an error is thrown
+func (w *Worker) submit(lr LoadRequest) error {
 	w.Tasks <- lr
 	select {
 	case <-lr.Ack:
@@ -94,11 +131,16 @@ func (w *Worker) Load(statefile string) error {
 
 // Handle is the primary loop for managing the execution of a Config's lifecycle
 func (w *Worker) Handle(lr LoadRequest) {
-	state, err := LoadStateFile(lr.Source)
-	if err != nil {
-		lr.Err <- err
-		return
+	var err error
+	state := lr.State
+	if state == nil {
+		state, err = LoadStateFile(lr.Source)
+		if err != nil {
+			lr.Err <- err
+			return
+		}
 	}
+	state.initMaps()
 
 	if w.Config != nil {
 		if state.Revision == w.Config.Revision {
@@ -108,14 +150,26 @@ func (w *Worker) Handle(lr LoadRequest) {
 		} else if state.Revision < w.Config.Revision {
 			lr.Err <- ErrStaleRevision
 			return
+		} else if lr.State == nil {
+			lr.Err <- ErrRevisionMismatch
+			return
+		}
+		state.Inherit(w.Config)
+	}
+
+	state.Source = lr.Source
+	if lr.State != nil {
+		err = state.WriteStateFile(lr.Source)
+		if err != nil {
+			lr.Err <- err
+			return
 		}
-		lr.Err <- ErrRevisionMismatch
-		return
 	}
 
+	w.mu.Lock()
 	w.Config = state
 	w.ConfigFile = lr.Source
-	w.Config.Source = lr.Source
+	w.mu.Unlock()
 	lr.Ack <- true
 
 	ticker := time.NewTicker(1 * time.Second)
@@ -142,6 +196,7 @@ func (w *Worker) Handle(lr LoadRequest) {
 		}
 		err = w.Config.DoNextStep()
 		if err != nil {
+			Logger.Errorf("error attempting to perform the next step: %v", err)
 			return
 		}
 		w.Rest()
@@ -0,0 +1,186 @@
+// +build !windows
+
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gen0cide/laforge/core"
+)
+
+// setupTestAgent points the agent at a temporary home directory and starts a worker along with an API server
+// backed by it
+func setupTestAgent(t *testing.T) *httptest.Server {
+	dir, err := ioutil.TempDir("", "laforge-agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	AgentHomeDir = dir
+	err = os.MkdirAll(StepLogDir(), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Logger = logrus.New()
+	Logger.SetOutput(ioutil.Discard)
+	AsyncWorker = &Worker{}
+	AsyncWorker.Spawn()
+
+	gin.SetMode(gin.TestMode)
+	e := NewEngine()
+	r := gin.New()
+	r.GET("/api/status", e.ReqGetStatus)
+	r.GET("/api/state", e.ReqGetState)
+	r.GET("/api/steps", e.ReqGetSteps)
+	r.GET("/api/steps/current", e.ReqGetCurrentStep)
+	r.GET("/api/steps/completed", e.ReqGetCompletedSteps)
+	r.GET("/api/steps/awaiting", e.ReqGetAwaitingSteps)
+	r.GET("/api/steps/find/:id", e.ReqGetSpecificStep)
+	r.GET("/api/logs/all/:id", e.ReqGetStepLogAll)
+	r.POST("/api/provision", e.ReqPushProvision)
+	return httptest.NewServer(r)
+}
+
+func testState(revision int64, steps int) *State {
+	s := &State{
+		Host:     &core.Host{OS: "ubuntu"},
+		Revision: revision,
+	}
+	for i := 1; i <= steps; i++ {
+		s.Steps = append(s.Steps, &Step{
+			ID:       i,
+			Revision: fmt.Sprintf("%d", revision),
+			Name:     fmt.Sprintf("step%d", i),
+			StepType: "command",
+			Metadata: map[string]interface{}{
+				"command": fmt.Sprintf("echo step %d; sleep 0.2", i),
+			},
+		})
+	}
+	return s
+}
+
+func push(t *testing.T, url string, state *State) int {
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(url+"/api/provision", "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// awaitWorker waits for the worker to finish handling the pushed state
+func awaitWorker(t *testing.T) {
+	deadline := time.Now().Add(30 * time.Second)
+	for !AsyncWorker.Available() {
+		if time.Now().After(deadline) {
+			t.Fatal("worker did not finish performing the steps")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// TestWorkerWithPollingHandlers performs steps while the API is polled, so that it fails under -race if the worker
+// mutates the state without holding it's lock
+func TestWorkerWithPollingHandlers(t *testing.T) {
+	srv := setupTestAgent(t)
+	defer srv.Close()
+	defer os.RemoveAll(AgentHomeDir)
+
+	code := push(t, srv.URL, testState(5, 2))
+	if code != 200 {
+		t.Fatalf("push returned %d", code)
+	}
+
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	for _, path := range []string{"/api/status", "/api/state", "/api/steps", "/api/steps/current", "/api/steps/completed", "/api/steps/awaiting", "/api/steps/find/1", "/api/logs/all/1"} {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				resp, err := http.Get(srv.URL + path)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				//nolint:errcheck
+				ioutil.ReadAll(resp.Body)
+				resp.Body.Close()
+				time.Sleep(10 * time.Millisecond)
+			}
+		}(path)
+	}
+
+	awaitWorker(t)
+	close(done)
+	wg.Wait()
+
+	completed := AsyncWorker.State().CompletedSteps()
+	if len(completed) != 2 {
+		t.Fatalf("expected 2 completed steps, got %d", len(completed))
+	}
+	for _, step := range completed {
+		if step.Status != "finished" {
+			t.Errorf("step %d is %s: %s", step.ID, step.Status, step.ExitMessage)
+		}
+	}
+}
+
+// TestWorkerKeepsPushedRevision ensures writing the state file does not change the revision it was pushed with
+func TestWorkerKeepsPushedRevision(t *testing.T) {
+	srv := setupTestAgent(t)
+	defer srv.Close()
+	defer os.RemoveAll(AgentHomeDir)
+
+	code := push(t, srv.URL, testState(5, 1))
+	if code != 200 {
+		t.Fatalf("push returned %d", code)
+	}
+	awaitWorker(t)
+
+	saved, err := LoadStateFile(ConfigFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Revision != 5 {
+		t.Errorf("expected the saved revision to be 5, got %d", saved.Revision)
+	}
+	if saved.WrittenAt.IsZero() {
+		t.Error("expected the saved state to record when it was written")
+	}
+
+	code = push(t, srv.URL, testState(5, 1))
+	if code != 409 {
+		t.Errorf("expected a duplicate revision to be rejected, got %d", code)
+	}
+	code = push(t, srv.URL, testState(6, 1))
+	if code != 200 {
+		t.Errorf("expected the next revision to be accepted, got %d", code)
+	}
+	awaitWorker(t)
+	if rev := AsyncWorker.State().Revision; rev != 6 {
+		t.Errorf("expected revision 6 to be loaded, got %d", rev)
+	}
+}
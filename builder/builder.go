@@ -4,9 +4,13 @@ package builder
 
 import (
 	"fmt"
-	"os"
+	"io"
 
+	"github.com/gen0cide/laforge/builder/docker"
+	"github.com/gen0cide/laforge/builder/tfaws"
 	"github.com/gen0cide/laforge/builder/tfgcp"
+	"github.com/gen0cide/laforge/builder/tflibvirt"
+	"github.com/gen0cide/laforge/builder/vagrant"
 	"github.com/gen0cide/laforge/core/cli"
 
 	validations "github.com/gen0cide/laforge/builder/buildutil/valdations"
@@ -14,6 +18,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/gen0cide/laforge/builder/buildutil"
 	"github.com/gen0cide/laforge/builder/null"
+	"github.com/gen0cide/laforge/builder/plugin"
 	"github.com/gen0cide/laforge/core"
 	"github.com/pkg/errors"
 )
@@ -21,14 +26,20 @@ import (
 var (
 	// ValidBuilders retains a map of ID to empty Builder objects.
 	ValidBuilders = map[string]Builder{
-		"tfgcp": tfgcp.New(),
-		// "tfaws": tfaws.New(),
+		"tfgcp":     tfgcp.New(),
+		"tfaws":     tfaws.New(),
+		"tflibvirt": tflibvirt.New(),
+		"docker":    docker.New(),
+		"vagrant":   vagrant.New(),
 		// "tfibm": tfibm.New(),
 		"null": null.New(),
 	}
 
 	// ErrNoBuilderFound is thrown when a builder is not a known valid builder parameter
 	ErrNoBuilderFound = errors.New("builder type defined in environment was not recognized")
+
+	// ErrValidationFailed is thrown when the environment does not meet one of the builder's requirements
+	ErrValidationFailed = errors.New("build requirement failed")
 )
 
 // BuildEngine is the primary interface for building components in gscript.
@@ -99,7 +110,10 @@ func New(base *core.Laforge, overwrite, update bool) (*BuildEngine, error) {
 
 	bldr, found := ValidBuilders[base.CurrentEnv.Builder]
 	if !found {
-		return nil, buildutil.Throw(ErrNoBuilderFound, "Invalid builder defined", &buildutil.V{"provided": base.CurrentEnv.Builder})
+		bldr, err = LookupPlugin(base.CurrentEnv.Builder)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	cli.SetLogName(fmt.Sprintf("%s/%s", color.WhiteString("builder"), color.HiGreenString(base.CurrentEnv.Builder)))
@@ -109,8 +123,24 @@ func New(base *core.Laforge, overwrite, update bool) (*BuildEngine, error) {
 	}, nil
 }
 
+// LookupPlugin attempts to locate an out of tree builder plugin for the builder ID in the PATH.
+func LookupPlugin(id string) (Builder, error) {
+	p, err := plugin.Lookup(id)
+	if err == plugin.ErrPluginNotFound {
+		return nil, buildutil.Throw(ErrNoBuilderFound, "Invalid builder defined", &buildutil.V{"provided": id, "plugin": plugin.ExecutableName(id)})
+	}
+	if err != nil {
+		return nil, buildutil.Throw(err, "Builder plugin could not be loaded", &buildutil.V{"provided": id, "plugin": plugin.ExecutableName(id)})
+	}
+	cli.Logger.Infof("Using builder plugin %s (%s %s)", p.Path, p.Name(), p.Version())
+	return p, nil
+}
+
 // Do performs the waterfall of functions on the given context with it's designated builder
 func (b *BuildEngine) Do() error {
+	if closer, ok := b.Builder.(io.Closer); ok {
+		defer closer.Close()
+	}
 	err := b.Builder.SetLaforge(b.Base)
 	if err != nil {
 		return buildutil.Throw(err, "failed to set laforge", nil)
@@ -122,7 +152,7 @@ func (b *BuildEngine) Do() error {
 		if !x.Check(b.Base) {
 			cli.Logger.Errorf("Build Requirement Failed: %s", x.Name)
 			cli.Logger.Errorf("  Resolution > %s", x.Resolution)
-			os.Exit(1)
+			return buildutil.Throw(ErrValidationFailed, x.Name, &buildutil.V{"resolution": x.Resolution})
 		}
 	}
 	err = b.Builder.CheckRequirements()
@@ -0,0 +1,735 @@
+// Package docker implements a Laforge Builder module for generating docker-compose projects. Every team is rendered
+// into its own compose project, which makes it a lightweight alternative to VM based builders for Linux-only environments.
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gen0cide/laforge/builder/docker/static"
+	"github.com/gen0cide/laforge/core/cli"
+
+	"github.com/gen0cide/laforge/builder/buildutil/templates"
+	validations "github.com/gen0cide/laforge/builder/buildutil/valdations"
+
+	"github.com/pkg/errors"
+
+	"github.com/gen0cide/laforge/agent"
+	"github.com/gen0cide/laforge/builder/buildutil"
+	"github.com/gen0cide/laforge/core"
+)
+
+// Definition of builder meta-data.
+const (
+	ID          = `docker`
+	Name        = `Docker Compose Builder`
+	Description = `generates a docker-compose project per team for environments made up of Linux hosts`
+	Author      = `Alex Levinson <github.com/gen0cide>`
+	Version     = `0.0.1`
+)
+
+// Hook types determine when a provisioning step is performed inside of a container.
+const (
+	// HookBuild performs the step as an image build step (the default).
+	HookBuild = `build`
+
+	// HookEntrypoint performs the step from the container's entrypoint the first time it starts.
+	HookEntrypoint = `entrypoint`
+
+	// HookSkip omits the step from the container entirely.
+	HookSkip = `skip`
+)
+
+var (
+	rules = validations.Validations{
+		validations.Requirement{
+			Name:       "Environment maintainer not defined",
+			Resolution: "add a maintainer block to your environment configuration",
+			Check:      validations.FieldNotEmpty(core.Environment{}, "Maintainer"),
+		},
+		validations.Requirement{
+			Name:       "docker executable not located in path",
+			Resolution: "download and ensure that the docker CLI is installed to a valid location in your PATH",
+			Check:      validations.ExistsInPath("docker"),
+		},
+		validations.Requirement{
+			Name:       "docker-compose executable not located in path",
+			Resolution: "download and ensure that docker-compose is installed to a valid location in your PATH",
+			Check:      validations.ExistsInPath("docker-compose"),
+		},
+		validations.Requirement{
+			Name:       "no teams specified",
+			Resolution: "make sure to set your team_count inside your environment config block to at least 1.",
+			Check:      validations.FieldNotEmpty(core.Environment{}, "team_count"),
+		},
+		validations.Requirement{
+			Name:       "No networks have been included",
+			Resolution: "Use the included_network \"$network_id\" { ... } block inside of your environment config to include networks.",
+			Check:      validations.FieldNotEmpty(core.Environment{}, "IncludedNetworks"),
+		},
+		validations.Requirement{
+			Name:       "No hosts were included",
+			Resolution: "Check your included_network blocks. The field included_hosts = [ ... ] should be populated with host IDs.",
+			Check:      validations.FieldNotEmpty(core.Environment{}, "IncludedHosts"),
+		},
+		validations.Requirement{
+			Name:       "No CIDR defined for network",
+			Resolution: "Check that network declarations have a cidr = ... defined in them.",
+			Check:      validations.FieldNotEmpty(core.Network{}, "CIDR"),
+		},
+		validations.Requirement{
+			Name:       "No OS defined for a host",
+			Resolution: "Check that all host declarations have an os = ... attribute defined.",
+			Check:      validations.FieldNotEmpty(core.Host{}, "OS"),
+		},
+		validations.Requirement{
+			Name:       "No hostname defined for a host",
+			Resolution: "Check that all host declarations have a hostname = ... attribute defined.",
+			Check:      validations.FieldNotEmpty(core.Host{}, "Hostname"),
+		},
+		validations.Requirement{
+			Name:       "Windows hosts cannot be built as containers",
+			Resolution: "Containers share the Linux kernel of the docker host. Remove Windows hosts from your included_hosts, or build this environment with a VM based builder such as tfgcp or tfaws.",
+			Check:      noWindowsHosts,
+		},
+		validations.Requirement{
+			Name:       "Host last_octet collides with the docker network gateway",
+			Resolution: "Docker reserves the network address and the first address of every subnet for its gateway. Make sure every host uses a last_octet greater than 1.",
+			Check:      lastOctetsAvoidGateway,
+		},
+		validations.Requirement{
+			Name:       "No container image available for a host",
+			Resolution: "Define a docker_image_$os value inside your environment config = { ... } block, or set a docker_image var on the host.",
+			Check:      imagesResolvable,
+		},
+		validations.Requirement{
+			Name:       "Invalid docker_hook defined for a provisioner",
+			Resolution: "The docker_hook var of a script or command must be one of build, entrypoint, or skip.",
+			Check:      hooksValid,
+		},
+	}
+
+	templatesToLoad = []string{
+		"docker-compose.yml.tmpl",
+	}
+
+	additionalTemplates = []string{
+		"Dockerfile.tmpl",
+		"entrypoint.sh.tmpl",
+	}
+
+	primaryTemplate = "docker-compose.yml.tmpl"
+
+	// defaultImages maps well known host operating systems to public images.
+	defaultImages = map[string]string{
+		"ubuntu": "ubuntu:18.04",
+		"debian": "debian:stretch",
+		"centos": "centos:7",
+		"fedora": "fedora:30",
+		"alpine": "alpine:3.9",
+		"kali":   "kalilinux/kali-linux-docker",
+	}
+
+	// assetDir is where scripts and files are staged inside of each container.
+	assetDir = "/opt/laforge/assets"
+)
+
+// DockerComposeBuilder implements a laforge builder that packages an environment into
+// one docker-compose project per team, with every provisioned network represented as
+// a user defined docker network.
+type DockerComposeBuilder struct {
+	sync.RWMutex
+
+	// Required for the Builder interface
+	Base *core.Laforge
+
+	// A place to store the templates
+	Library *templates.Library
+
+	// Images maps included host IDs to the image their container is built from
+	Images map[string]string
+
+	// AgentCA issues the TLS credentials of each host's agent
+	AgentCA *agent.CertificateAuthority
+}
+
+// Get retrieves an element from the embedded KV store
+func (t *DockerComposeBuilder) Get(key string) string {
+	t.Lock()
+	defer t.Unlock()
+	res, ok := t.Base.CurrentBuild.Config[key]
+	if ok {
+		return res
+	}
+	r0, e0 := t.Base.CurrentEnv.Config[key]
+	if e0 {
+		t.Base.CurrentBuild.Config[key] = r0
+		return r0
+	}
+	return ""
+}
+
+// Set assigns an element to the embedded KV store
+func (t *DockerComposeBuilder) Set(key string, val interface{}) {
+	t.Lock()
+	defer t.Unlock()
+	t.Base.CurrentBuild.Config[key] = fmt.Sprintf("%v", val)
+}
+
+// New creates an empty DockerComposeBuilder
+func New() *DockerComposeBuilder {
+	lib := templates.NewLibrary()
+	return &DockerComposeBuilder{
+		Library: lib,
+		Images:  map[string]string{},
+	}
+}
+
+// ID implements the Builder interface (returns the ID of the builder - usually the go package name)
+func (t *DockerComposeBuilder) ID() string {
+	return ID
+}
+
+// Name implements the Builder interface (returns the name of the builder - usually titleized version of the type)
+func (t *DockerComposeBuilder) Name() string {
+	return Name
+}
+
+// Description implements the Builder interface (returns the builder's description)
+func (t *DockerComposeBuilder) Description() string {
+	return Description
+}
+
+// Author implements the Builder interface (author's name and contact info)
+func (t *DockerComposeBuilder) Author() string {
+	return Author
+}
+
+// Version implements the Builder interface (builder version)
+func (t *DockerComposeBuilder) Version() string {
+	return Version
+}
+
+// Validations implements the Builder interface (builder checks)
+func (t *DockerComposeBuilder) Validations() validations.Validations {
+	return rules
+}
+
+// SetLaforge implements the Builder interface
+func (t *DockerComposeBuilder) SetLaforge(base *core.Laforge) error {
+	t.Base = base
+	if !base.ClearToBuild {
+		return buildutil.Throw(errors.New("context is not cleared to build"), "Laforge has encountered an error and cannot continue to build. This is likely a bug in LaForge.", nil)
+	}
+	for _, x := range append(templatesToLoad, additionalTemplates...) {
+		d, err := static.ReadFile(x)
+		if err != nil {
+			return buildutil.Throw(err, "could not read template", &buildutil.V{"template_name": x})
+		}
+		_, err = t.Library.AddBook(x, d)
+		if err != nil {
+			return buildutil.Throw(err, "could not parse template", &buildutil.V{"template_name": x})
+		}
+	}
+	return nil
+}
+
+// CheckRequirements implements the Builder interface
+func (t *DockerComposeBuilder) CheckRequirements() error {
+	if len(t.Base.CurrentBuild.Teams) > 1 {
+		cli.Logger.Warnf("Building %d teams onto a single docker host. Every team reuses the same network CIDRs, so only one team project should be up at a time.", len(t.Base.CurrentBuild.Teams))
+	}
+	return nil
+}
+
+// PrepareAssets implements the Builder interface
+func (t *DockerComposeBuilder) PrepareAssets() error {
+	agentCA, err := agent.LoadOrCreateBuildCA(filepath.Join(t.Base.CurrentBuild.Dir, "data"))
+	if err != nil {
+		return buildutil.Throw(err, "Could not prepare the agent certificate authority in the build directory", nil)
+	}
+	t.AgentCA = agentCA
+
+	for hostid, host := range t.Base.CurrentEnv.IncludedHosts {
+		image, err := LookupImage(t.Base, host)
+		if err != nil {
+			return buildutil.Throw(err, "Validation for this passed, but the host's image could not be resolved. Likely a bug. Please report.", &buildutil.V{"host_id": hostid, "os": host.OS})
+		}
+		t.Images[hostid] = image
+
+		for _, dep := range host.Dependencies {
+			depHost, ok := t.Base.CurrentEnv.IncludedHosts[dep.HostID]
+			if !ok {
+				return buildutil.Throw(errors.Errorf("host %s depends on host %s, which is not found in environment", host.ID, dep.HostID), "The host listed a dependency to another host which is not included in any network within the current environment.", &buildutil.V{"source_host": hostid, "depends_on_host": dep.HostID})
+			}
+			dep.Host = depHost
+
+			depNet, ok := t.Base.CurrentEnv.IncludedNetworks[dep.NetworkID]
+			if !ok {
+				return buildutil.Throw(errors.Errorf("host %s depends on network %s, which is not found in environment", host.ID, dep.NetworkID), "The host listed a dependency to another network which is not included within the current environment.", &buildutil.V{"source_host": hostid, "depends_on_host": dep.HostID, "depends_on_network": dep.NetworkID})
+			}
+			dep.Network = depNet
+
+			hostInNetwork := false
+			for _, x := range t.Base.CurrentEnv.HostByNetwork[dep.NetworkID] {
+				if x.ID == dep.Host.ID {
+					hostInNetwork = true
+					break
+				}
+			}
+			if !hostInNetwork {
+				return buildutil.Throw(errors.Errorf("host %s depends on host %s, which is not included in network %s", host.ID, dep.HostID, dep.NetworkID), "The host listed a dependency to another host, and while the network exists and is included, this host is not present within this network assignment.", &buildutil.V{"source_host": hostid, "depends_on_host": dep.HostID, "depends_on_network": dep.NetworkID})
+			}
+		}
+	}
+
+	return nil
+}
+
+// GenerateScripts implements the Builder interface
+func (t *DockerComposeBuilder) GenerateScripts() error {
+	wg := new(sync.WaitGroup)
+	errChan := make(chan error, 1)
+	finChan := make(chan bool, 1)
+	user := t.Base.User
+	ctx, err := templates.NewContext(
+		t.Base,
+		t.Base.CurrentBuild,
+		t.Base.CurrentCompetition,
+		t.Base.CurrentCompetition.DNS,
+		t.Base.CurrentEnv,
+		user,
+	)
+	if err != nil {
+		return err
+	}
+	for _, teamObj := range t.Base.CurrentBuild.Teams {
+		for _, pnetObj := range teamObj.ProvisionedNetworks {
+			for _, phostObj := range pnetObj.ProvisionedHosts {
+				for _, stepObj := range phostObj.StepsByOffset {
+					script, ok := stepObj.Provisioner.(*core.Script)
+					if !ok || script.Source == "" {
+						continue
+					}
+					wg.Add(1)
+					go func(team *core.Team, pn *core.ProvisionedNetwork, ph *core.ProvisionedHost, ps *core.ProvisioningStep, scriptObj *core.Script) {
+						defer wg.Done()
+						scriptCtx := ctx.Clone()
+						err := scriptCtx.Attach(team, pn.Network, ph.Host, scriptObj, pn, ph, ps, ph.Conn)
+						if err != nil {
+							errChan <- err
+							return
+						}
+						assetPath := filepath.Join(team.RelBuildPath, "networks", pn.Network.Base(), "hosts", ph.Host.Base(), "assets", filepath.Base(scriptObj.Source))
+						fileData, err := t.Library.Execute(scriptObj.Path(), scriptCtx)
+						if err != nil {
+							errChan <- buildutil.Throw(err, "script template failed", &buildutil.V{
+								"script": scriptObj.Path(),
+								"host":   ph.Path(),
+							})
+							return
+						}
+						err = ioutil.WriteFile(assetPath, fileData, 0755)
+						if err != nil {
+							errChan <- err
+							return
+						}
+					}(teamObj, pnetObj, phostObj, stepObj, script)
+				}
+			}
+		}
+	}
+
+	go func() {
+		wg.Wait()
+		close(finChan)
+	}()
+
+	select {
+	case <-finChan:
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+// StageDependencies implements the Builder interface
+func (t *DockerComposeBuilder) StageDependencies() error {
+	if t.Base.StateManager == nil {
+		return errors.New("builder cannot stage dependencies with nil state manager")
+	}
+	build := t.Base.CurrentBuild
+	for _, team := range build.Teams {
+		teamDir := filepath.Join(build.Dir, "teams", fmt.Sprintf("%v", team.TeamNumber))
+		team.RelBuildPath = teamDir
+		os.MkdirAll(teamDir, 0755)
+		core.TouchGitKeep(teamDir)
+		teamCfg, err := core.RenderHCLv2Object(team)
+		if err != nil {
+			return err
+		}
+		teamCfgFile := filepath.Join(teamDir, "team.laforge")
+		err = ioutil.WriteFile(teamCfgFile, teamCfg, 0644)
+		if err != nil {
+			return err
+		}
+		for _, pn := range team.ProvisionedNetworks {
+			netdir := filepath.Join(teamDir, "networks", pn.Base())
+			os.MkdirAll(netdir, 0755)
+			core.TouchGitKeep(netdir)
+			data, err := core.RenderHCLv2Object(pn)
+			if err != nil {
+				return err
+			}
+			netfile := filepath.Join(netdir, "provisioned_network.laforge")
+			err = ioutil.WriteFile(netfile, data, 0644)
+			if err != nil {
+				return err
+			}
+			for _, ph := range pn.ProvisionedHosts {
+				hostdir := filepath.Join(netdir, "hosts", ph.Base())
+				agentdir := filepath.Join(hostdir, "agent")
+				assetdir := filepath.Join(hostdir, "assets")
+				stepdir := filepath.Join(hostdir, "steps")
+				os.MkdirAll(agentdir, 0755)
+				os.MkdirAll(assetdir, 0755)
+				os.MkdirAll(stepdir, 0755)
+				core.TouchGitKeep(agentdir)
+				core.TouchGitKeep(assetdir)
+				core.TouchGitKeep(stepdir)
+				phID := path.Join(team.Path(), "networks", pn.Network.Base(), "hosts", ph.Host.Base())
+				err = t.AgentCA.WriteAgentCredentials(agentdir, phID, ph.Host.Hostname)
+				if err != nil {
+					return err
+				}
+				data, err = core.RenderHCLv2Object(ph)
+				if err != nil {
+					return err
+				}
+				hostfile := filepath.Join(hostdir, "provisioned_host.laforge")
+				err = ioutil.WriteFile(hostfile, data, 0644)
+				if err != nil {
+					return err
+				}
+				for _, ps := range ph.ProvisioningSteps {
+					stepfile := filepath.Join(stepdir, fmt.Sprintf("%s.laforge", ps.Base()))
+					data, err = core.RenderHCLv2Object(ps)
+					if err != nil {
+						return err
+					}
+					err = ioutil.WriteFile(stepfile, data, 0644)
+					if err != nil {
+						return err
+					}
+					if rfile, ok := ps.Provisioner.(*core.RemoteFile); ok {
+						rfileName, err := rfile.AssetName()
+						if err != nil {
+							return err
+						}
+
+						dstPath := filepath.Join(assetdir, rfileName)
+						if _, err := os.Stat(dstPath); os.IsNotExist(err) {
+							copyErr := rfile.CopyTo(dstPath)
+							if copyErr != nil {
+								return copyErr
+							}
+						}
+					}
+					if script, ok := ps.Provisioner.(*core.Script); ok {
+						if _, ok := t.Library.Books[script.Path()]; ok {
+							continue
+						}
+						if script.Source == "" {
+							continue
+						}
+						for _, callfile := range script.Caller {
+							pr, ok := t.Base.PathRegistry.DB[callfile]
+							if !ok {
+								continue
+							}
+							lfr, ok := pr.Mapping[script.Source]
+							if !ok {
+								continue
+							}
+							data, err := ioutil.ReadFile(lfr.AbsPath)
+							if err != nil {
+								return err
+							}
+							_, err = t.Library.AddBook(script.Path(), data)
+							if err != nil {
+								return err
+							}
+							break
+						}
+					}
+				}
+			}
+		}
+
+	}
+	return nil
+}
+
+// Render implements the Builder interface
+func (t *DockerComposeBuilder) Render() error {
+	wg := new(sync.WaitGroup)
+	errChan := make(chan error, 1)
+	finChan := make(chan bool, 1)
+	for _, team := range t.Base.CurrentBuild.Teams {
+		wg.Add(1)
+		go func(team *core.Team) {
+			defer wg.Done()
+
+			teamDir := team.RelBuildPath
+			user := t.Base.User
+			ctx, err := templates.NewContext(
+				t.Base,
+				t.Base.CurrentBuild,
+				t.Base.CurrentCompetition,
+				t.Base.CurrentCompetition.DNS,
+				t.Base.CurrentEnv,
+				user,
+				team,
+			)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			project := ProjectName(t.Base.CurrentEnv, team)
+			ctx.Set("project", project)
+			for _, pn := range team.ProvisionedNetworks {
+				for _, ph := range pn.ProvisionedHosts {
+					err = t.renderHost(ctx, team, pn, ph)
+					if err != nil {
+						errChan <- buildutil.Throw(err, "could not render container", &buildutil.V{
+							"team": team.Path(),
+							"host": ph.Path(),
+						})
+						return
+					}
+				}
+			}
+			cfgData, err := t.Library.ExecuteGroup(primaryTemplate, templatesToLoad, ctx)
+			if err != nil {
+				errChan <- buildutil.Throw(err, "template failed", &buildutil.V{
+					"team": team.Path(),
+					"dir":  teamDir,
+				})
+				return
+			}
+			err = ioutil.WriteFile(filepath.Join(teamDir, "docker-compose.yml"), cfgData, 0644)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			envData := fmt.Sprintf("COMPOSE_PROJECT_NAME=%s\n", project)
+			err = ioutil.WriteFile(filepath.Join(teamDir, ".env"), []byte(envData), 0644)
+			if err != nil {
+				errChan <- err
+				return
+			}
+		}(team)
+	}
+	go func() {
+		wg.Wait()
+		close(finChan)
+	}()
+
+	select {
+	case <-finChan:
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+// renderHost writes the Dockerfile and entrypoint hooks for a single provisioned host into its build context.
+func (t *DockerComposeBuilder) renderHost(teamCtx *templates.Context, team *core.Team, pn *core.ProvisionedNetwork, ph *core.ProvisionedHost) error {
+	ctx := teamCtx.Clone()
+	err := ctx.Attach(pn.Network, ph.Host, pn, ph, ph.Conn)
+	if err != nil {
+		return err
+	}
+	image, ok := t.Images[ph.Host.Path()]
+	if !ok {
+		return errors.Errorf("no image was resolved for host %s", ph.Host.Path())
+	}
+	ctx.Set("image", image)
+	if command := ph.Host.Vars["docker_command"]; command != "" {
+		cmdJSON, err := json.Marshal([]string{"/bin/sh", "-c", command})
+		if err != nil {
+			return err
+		}
+		ctx.Set("command", string(cmdJSON))
+	}
+
+	for _, ps := range ph.StepsByOffset {
+		hook := StepHook(ps.Provisioner)
+		ctx.Set(fmt.Sprintf("step_hook_%d", ps.StepNumber), hook)
+		if hook == HookSkip {
+			continue
+		}
+		var asset string
+		var argv []string
+		ignoreErrors := false
+		switch p := ps.Provisioner.(type) {
+		case *core.Script:
+			asset = filepath.Base(p.Source)
+			argv = append([]string{filepath.Join(assetDir, asset)}, p.Args...)
+			ignoreErrors = p.IgnoreErrors
+		case *core.Command:
+			argv = append([]string{p.Program}, p.Args...)
+			ignoreErrors = p.IgnoreErrors
+		case *core.RemoteFile:
+			asset, err = p.AssetName()
+			if err != nil {
+				return err
+			}
+		}
+		ctx.Set(fmt.Sprintf("step_asset_%d", ps.StepNumber), asset)
+		if len(argv) == 0 {
+			continue
+		}
+		shell := ShellJoin(argv)
+		if ignoreErrors {
+			shell = fmt.Sprintf("%s || true", shell)
+			ctx.Set(fmt.Sprintf("step_run_%d", ps.StepNumber), shell)
+		} else {
+			argvJSON, err := json.Marshal(argv)
+			if err != nil {
+				return err
+			}
+			ctx.Set(fmt.Sprintf("step_run_%d", ps.StepNumber), string(argvJSON))
+		}
+		ctx.Set(fmt.Sprintf("step_shell_%d", ps.StepNumber), shell)
+	}
+
+	hostDir := filepath.Join(team.RelBuildPath, "networks", pn.Network.Base(), "hosts", ph.Host.Base())
+	files := map[string]string{
+		"Dockerfile.tmpl":    "Dockerfile",
+		"entrypoint.sh.tmpl": "entrypoint.sh",
+	}
+	for tmpl, filename := range files {
+		data, err := t.Library.Execute(tmpl, ctx)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filepath.Join(hostDir, filename), data, 0755)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProjectName returns the docker-compose project name used for a team of the environment.
+func ProjectName(env *core.Environment, team *core.Team) string {
+	return strings.ToLower(fmt.Sprintf("%s-t%d", env.Base(), team.TeamNumber))
+}
+
+// StepHook determines how a provisioner is performed inside of a container. Remote files are always
+// copied in at build time. Scripts and commands run at build time unless they define a docker_hook var.
+// DNS records are skipped, as compose provides name resolution between services on its own.
+func StepHook(p core.Provisioner) string {
+	switch v := p.(type) {
+	case *core.RemoteFile:
+		if v.Disabled {
+			return HookSkip
+		}
+		return HookBuild
+	case *core.Script:
+		if v.Disabled {
+			return HookSkip
+		}
+		return hookFromVars(v.Vars)
+	case *core.Command:
+		if v.Disabled {
+			return HookSkip
+		}
+		return hookFromVars(v.Vars)
+	}
+	return HookSkip
+}
+
+func hookFromVars(vars map[string]string) string {
+	if hook := vars["docker_hook"]; hook != "" {
+		return hook
+	}
+	return HookBuild
+}
+
+// ShellJoin quotes every argument for a POSIX shell and joins them into a single command line.
+func ShellJoin(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = "'" + strings.Replace(arg, "'", `'"'"'`, -1) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// LookupImage resolves the image a host's container is built from. A host can declare a docker_image var,
+// otherwise the environment's docker_image_$os config value is used, falling back to a well known public image.
+func LookupImage(base *core.Laforge, host *core.Host) (string, error) {
+	if image := host.Vars["docker_image"]; image != "" {
+		return image, nil
+	}
+	osName := strings.ToLower(host.OS)
+	key := fmt.Sprintf("docker_image_%s", osName)
+	if image := base.CurrentEnv.Config[key]; image != "" {
+		return image, nil
+	}
+	if image, ok := defaultImages[osName]; ok {
+		return image, nil
+	}
+	return "", errors.Errorf("host %s has no docker_image var and the environment does not define %s", host.ID, key)
+}
+
+func noWindowsHosts(base *core.Laforge) bool {
+	for id, host := range base.CurrentEnv.IncludedHosts {
+		if host.IsWindows() {
+			cli.Logger.Errorf("host %s has failed a validation: os %s cannot run in a container", id, host.OS)
+			return false
+		}
+	}
+	return true
+}
+
+func lastOctetsAvoidGateway(base *core.Laforge) bool {
+	for id, host := range base.CurrentEnv.IncludedHosts {
+		if host.LastOctet < 2 {
+			cli.Logger.Errorf("host %s has failed a validation: last_octet %d is reserved by docker", id, host.LastOctet)
+			return false
+		}
+	}
+	return true
+}
+
+func imagesResolvable(base *core.Laforge) bool {
+	for id, host := range base.CurrentEnv.IncludedHosts {
+		if _, err := LookupImage(base, host); err != nil {
+			cli.Logger.Errorf("host %s has failed a validation: %v", id, err)
+			return false
+		}
+	}
+	return true
+}
+
+func hooksValid(base *core.Laforge) bool {
+	for id, host := range base.CurrentEnv.IncludedHosts {
+		for _, p := range host.Provisioners {
+			switch StepHook(p) {
+			case HookBuild, HookEntrypoint, HookSkip:
+				continue
+			}
+			cli.Logger.Errorf("host %s has failed a validation: provisioner %s has an unknown docker_hook", id, p.Path())
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,280 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/gen0cide/laforge"
+	"github.com/gen0cide/laforge/builder/buildutil"
+	validations "github.com/gen0cide/laforge/builder/buildutil/valdations"
+	"github.com/gen0cide/laforge/core"
+	"github.com/gen0cide/laforge/core/cli"
+	"github.com/pkg/errors"
+)
+
+// ErrPluginNotFound is returned by Lookup when no plugin executable exists for a builder
+var ErrPluginNotFound = errors.New("builder plugin not found in PATH")
+
+// Builder implements the laforge Builder interface by proxying every call to an external plugin process.
+type Builder struct {
+	sync.Mutex
+
+	// Base is the laforge base the build is performed on
+	Base *core.Laforge
+
+	// Info is the plugin's self description returned during the handshake
+	Info Info
+
+	// Path is the location of the plugin executable
+	Path string
+
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	stdout  *bufio.Reader
+	counter int
+	closed  bool
+}
+
+// ExecutableName returns the name of the plugin executable for a builder ID.
+func ExecutableName(id string) string {
+	return ExecutablePrefix + id
+}
+
+// Lookup locates the plugin executable for a builder ID in the PATH, starts it, and performs the handshake.
+// ErrPluginNotFound is returned if there is no such executable.
+func Lookup(id string) (*Builder, error) {
+	exePath, err := exec.LookPath(ExecutableName(id))
+	if err != nil {
+		return nil, ErrPluginNotFound
+	}
+
+	b := &Builder{Path: exePath}
+	err = b.start()
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not start builder plugin %s", exePath)
+	}
+
+	err = b.call(MethodHandshake, HandshakeParams{ProtocolVersion: ProtocolVersion, LaforgeVersion: laforge.Version}, &b.Info)
+	if err != nil {
+		b.Close()
+		return nil, errors.Wrapf(err, "handshake with builder plugin %s failed", exePath)
+	}
+	if b.Info.ProtocolVersion != ProtocolVersion {
+		b.Close()
+		return nil, errors.Errorf("builder plugin %s speaks protocol version %d, but laforge requires version %d", exePath, b.Info.ProtocolVersion, ProtocolVersion)
+	}
+	if b.Info.ID == "" {
+		b.Info.ID = id
+	}
+	return b, nil
+}
+
+func (b *Builder) start() error {
+	b.cmd = exec.Command(b.Path)
+	b.cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", EnvProtocolVersion, ProtocolVersion))
+	b.cmd.Stderr = os.Stderr
+	stdin, err := b.cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := b.cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	b.stdin = stdin
+	b.stdout = bufio.NewReader(stdout)
+	return b.cmd.Start()
+}
+
+// call performs a single request/response round trip with the plugin, decoding the result into out.
+func (b *Builder) call(method string, params interface{}, out interface{}) error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed {
+		return errors.Errorf("builder plugin %s has already been shut down", b.Path)
+	}
+
+	b.counter++
+	req := Request{
+		Version: ProtocolVersion,
+		ID:      b.counter,
+		Method:  method,
+	}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return errors.Wrapf(err, "could not encode %s parameters", method)
+		}
+		req.Params = data
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	_, err = b.stdin.Write(append(data, '\n'))
+	if err != nil {
+		return errors.Wrapf(err, "could not send %s to builder plugin", method)
+	}
+
+	line, err := b.stdout.ReadBytes('\n')
+	if err != nil {
+		return errors.Wrapf(err, "builder plugin exited before responding to %s", method)
+	}
+	resp := Response{}
+	err = json.Unmarshal(line, &resp)
+	if err != nil {
+		return errors.Wrapf(err, "builder plugin sent a malformed response to %s", method)
+	}
+	if resp.ID != req.ID {
+		return errors.Errorf("builder plugin responded to request %d while %d was expected", resp.ID, req.ID)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if out == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, out)
+}
+
+// step performs one of the builder interface methods, merging any returned config into the build.
+func (b *Builder) step(method string, params interface{}) error {
+	res := Result{}
+	err := b.call(method, params, &res)
+	if err != nil {
+		if perr, ok := err.(*Error); ok {
+			reason := perr.Reason
+			if reason == "" {
+				reason = fmt.Sprintf("builder plugin %s failed during %s", b.Info.ID, method)
+			}
+			vars := buildutil.V(perr.Vars)
+			return buildutil.Throw(perr, reason, &vars)
+		}
+		return err
+	}
+	if b.Base != nil && b.Base.CurrentBuild != nil {
+		if b.Base.CurrentBuild.Config == nil {
+			b.Base.CurrentBuild.Config = map[string]string{}
+		}
+		for k, v := range res.Config {
+			b.Base.CurrentBuild.Config[k] = v
+		}
+	}
+	return nil
+}
+
+// Close shuts the plugin process down. It is safe to call more than once.
+func (b *Builder) Close() error {
+	b.Lock()
+	if b.closed {
+		b.Unlock()
+		return nil
+	}
+	b.Unlock()
+
+	err := b.call(MethodShutdown, nil, nil)
+	if err != nil {
+		cli.Logger.Debugf("builder plugin %s did not acknowledge shutdown: %v", b.Path, err)
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	b.closed = true
+	b.stdin.Close()
+	return b.cmd.Wait()
+}
+
+// ID implements the Builder interface (returns the ID of the builder - usually the go package name)
+func (b *Builder) ID() string {
+	return b.Info.ID
+}
+
+// Name implements the Builder interface (returns the name of the builder - usually titleized version of the type)
+func (b *Builder) Name() string {
+	return b.Info.Name
+}
+
+// Description implements the Builder interface (returns the builder's description)
+func (b *Builder) Description() string {
+	return b.Info.Description
+}
+
+// Author implements the Builder interface (author's name and contact info)
+func (b *Builder) Author() string {
+	return b.Info.Author
+}
+
+// Version implements the Builder interface (builder version)
+func (b *Builder) Version() string {
+	return b.Info.Version
+}
+
+// Validations implements the Builder interface (builder checks). The plugin evaluates it's
+// requirements against the snapshot it received and reports which of them passed.
+func (b *Builder) Validations() validations.Validations {
+	results := []ValidationResult{}
+	err := b.call(MethodValidations, nil, &results)
+	if err != nil {
+		return validations.Validations{
+			validations.Requirement{
+				Name:       fmt.Sprintf("builder plugin %s could not evaluate it's validations: %v", b.Info.ID, err),
+				Resolution: fmt.Sprintf("check that %s is compatible with laforge %s", b.Path, laforge.Version),
+				Check:      func(base *core.Laforge) bool { return false },
+			},
+		}
+	}
+	vals := validations.Validations{}
+	for _, x := range results {
+		passed := x.Passed
+		vals = append(vals, validations.Requirement{
+			Name:       x.Name,
+			Resolution: x.Resolution,
+			Check:      func(base *core.Laforge) bool { return passed },
+		})
+	}
+	return vals
+}
+
+// SetLaforge implements the Builder interface
+func (b *Builder) SetLaforge(base *core.Laforge) error {
+	b.Base = base
+	if !base.ClearToBuild {
+		return buildutil.Throw(errors.New("context is not cleared to build"), "Laforge has encountered an error and cannot continue to build. This is likely a bug in LaForge.", nil)
+	}
+	snap, err := NewSnapshot(base)
+	if err != nil {
+		return buildutil.Throw(err, "could not serialize the build for the builder plugin", &buildutil.V{"plugin": b.Path})
+	}
+	return b.step(MethodSetLaforge, snap)
+}
+
+// CheckRequirements implements the Builder interface
+func (b *Builder) CheckRequirements() error {
+	return b.step(MethodCheckRequirements, nil)
+}
+
+// PrepareAssets implements the Builder interface
+func (b *Builder) PrepareAssets() error {
+	return b.step(MethodPrepareAssets, nil)
+}
+
+// GenerateScripts implements the Builder interface
+func (b *Builder) GenerateScripts() error {
+	return b.step(MethodGenerateScripts, nil)
+}
+
+// StageDependencies implements the Builder interface
+func (b *Builder) StageDependencies() error {
+	return b.step(MethodStageDependencies, nil)
+}
+
+// Render implements the Builder interface
+func (b *Builder) Render() error {
+	return b.step(MethodRender, nil)
+}
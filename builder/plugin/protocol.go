@@ -0,0 +1,154 @@
+// Package plugin implements the protocol that allows Laforge builders to live outside of the laforge source tree.
+//
+// A plugin is an executable named laforge-builder-$name located in the user's PATH. When an environment's builder
+// is not one of the built in builders, laforge starts the plugin and speaks to it with newline delimited JSON
+// over the plugin's stdin and stdout. Every Request receives exactly one Response. Anything the plugin writes to
+// stderr is passed through to the user's terminal.
+//
+// The conversation always begins with a handshake, followed by the methods of the Builder interface in the same
+// order the BuildEngine calls them. Finally laforge sends a shutdown request and closes the plugin's stdin.
+package plugin
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/gen0cide/laforge/core"
+	"github.com/pkg/errors"
+)
+
+// ProtocolVersion is the version of the plugin protocol spoken by this version of laforge.
+// It is incremented whenever a change is made that is not backwards compatible.
+const ProtocolVersion = 1
+
+// ExecutablePrefix is prepended to a builder's ID to locate it's plugin executable in the PATH.
+const ExecutablePrefix = `laforge-builder-`
+
+// EnvProtocolVersion is the environment variable used to advertise the protocol version to a plugin.
+const EnvProtocolVersion = `LAFORGE_BUILDER_PROTOCOL`
+
+// Method names understood by plugins. They mirror the methods of the Builder interface.
+const (
+	MethodHandshake         = `handshake`
+	MethodSetLaforge        = `set_laforge`
+	MethodValidations       = `validations`
+	MethodCheckRequirements = `check_requirements`
+	MethodPrepareAssets     = `prepare_assets`
+	MethodGenerateScripts   = `generate_scripts`
+	MethodStageDependencies = `stage_dependencies`
+	MethodRender            = `render`
+	MethodShutdown          = `shutdown`
+)
+
+// Request is a single call sent from laforge to a plugin.
+type Request struct {
+	Version int             `json:"version"`
+	ID      int             `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a plugin's answer to a single Request.
+type Response struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// Result is the payload returned by every method other than handshake and validations. Any values in Config
+// are merged into the build's config map, much like a built in builder calling Set().
+type Result struct {
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// Error describes a failure inside of a plugin.
+type Error struct {
+	Message string                 `json:"message"`
+	Reason  string                 `json:"reason,omitempty"`
+	Vars    map[string]interface{} `json:"vars,omitempty"`
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// HandshakeParams are sent by laforge to begin a conversation.
+type HandshakeParams struct {
+	ProtocolVersion int    `json:"protocol_version"`
+	LaforgeVersion  string `json:"laforge_version"`
+}
+
+// Info is returned by a plugin during the handshake and describes the builder it implements.
+type Info struct {
+	ProtocolVersion int    `json:"protocol_version"`
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Author          string `json:"author"`
+	Version         string `json:"version"`
+}
+
+// ValidationResult is the outcome of a single requirement the plugin evaluated against the snapshot.
+type ValidationResult struct {
+	Name       string `json:"name"`
+	Resolution string `json:"resolution"`
+	Passed     bool   `json:"passed"`
+}
+
+// Snapshot is the serialized view of a build that is handed to a plugin by set_laforge. It contains the
+// merged configuration objects the build is generated from, the teams with all of their provisioned
+// networks, hosts, and steps, as well as the state snapshot graph.
+type Snapshot struct {
+	BaseDir       string                      `json:"base_dir"`
+	BuildDir      string                      `json:"build_dir"`
+	Competition   *core.Competition           `json:"competition"`
+	Environment   *core.Environment           `json:"environment"`
+	Build         *core.Build                 `json:"build"`
+	Teams         []*core.Team                `json:"teams"`
+	Networks      map[string]*core.Network    `json:"networks"`
+	Hosts         map[string]*core.Host       `json:"hosts"`
+	HostByNetwork map[string][]string         `json:"host_by_network"`
+	Scripts       map[string]*core.Script     `json:"scripts"`
+	Commands      map[string]*core.Command    `json:"commands"`
+	RemoteFiles   map[string]*core.RemoteFile `json:"remote_files"`
+	DNSRecords    map[string]*core.DNSRecord  `json:"dns_records"`
+	AMIs          map[string]*core.AMI        `json:"amis"`
+	State         *core.Snapshot              `json:"state,omitempty"`
+}
+
+// NewSnapshot serializes the current build of a laforge base so that it can be sent to a plugin.
+func NewSnapshot(base *core.Laforge) (*Snapshot, error) {
+	if base.CurrentEnv == nil || base.CurrentBuild == nil {
+		return nil, errors.New("laforge base does not have a current environment and build")
+	}
+	snap := &Snapshot{
+		BaseDir:       base.BaseDir,
+		BuildDir:      base.CurrentBuild.Dir,
+		Competition:   base.CurrentCompetition,
+		Environment:   base.CurrentEnv,
+		Build:         base.CurrentBuild,
+		Teams:         []*core.Team{},
+		Networks:      base.CurrentEnv.IncludedNetworks,
+		Hosts:         base.CurrentEnv.IncludedHosts,
+		HostByNetwork: map[string][]string{},
+		Scripts:       base.Scripts,
+		Commands:      base.Commands,
+		RemoteFiles:   base.RemoteFiles,
+		DNSRecords:    base.DNSRecords,
+		AMIs:          base.AMIs,
+	}
+	for _, team := range base.CurrentBuild.Teams {
+		snap.Teams = append(snap.Teams, team)
+	}
+	sort.Slice(snap.Teams, func(i, j int) bool { return snap.Teams[i].TeamNumber < snap.Teams[j].TeamNumber })
+	for netid, hosts := range base.CurrentEnv.HostByNetwork {
+		for _, h := range hosts {
+			snap.HostByNetwork[netid] = append(snap.HostByNetwork[netid], h.Path())
+		}
+	}
+	if base.StateManager != nil {
+		snap.State = base.StateManager.Current
+	}
+	return snap, nil
+}
@@ -0,0 +1,131 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Server is implemented by out of tree builders. Serve exposes a Server to laforge over stdio.
+type Server interface {
+	// Info describes the builder. The protocol version is filled in by Serve.
+	Info() Info
+
+	// SetLaforge receives the serialized build
+	SetLaforge(snap *Snapshot) error
+
+	// Validations evaluates the builder's requirements against the snapshot
+	Validations() []ValidationResult
+
+	CheckRequirements() error
+	PrepareAssets() error
+	GenerateScripts() error
+	StageDependencies() error
+	Render() error
+}
+
+// Configurer can optionally be implemented by a Server. The returned values are sent back to
+// laforge after every step and merged into the build's config map.
+type Configurer interface {
+	Config() map[string]string
+}
+
+// Serve answers laforge's requests on stdin and stdout until laforge shuts the plugin down.
+func Serve(s Server) error {
+	return ServeIO(s, os.Stdin, os.Stdout)
+}
+
+// ServeIO answers requests read from in, writing responses to out, until a shutdown request is received
+// or the input is closed.
+func ServeIO(s Server, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	enc := json.NewEncoder(out)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF && len(line) == 0 {
+			return nil
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		req := Request{}
+		err = json.Unmarshal(line, &req)
+		if err != nil {
+			return errors.Wrap(err, "received a malformed request from laforge")
+		}
+
+		resp := Response{ID: req.ID}
+		result, err := dispatch(s, &req)
+		if err != nil {
+			perr, ok := err.(*Error)
+			if !ok {
+				perr = &Error{Message: err.Error()}
+			}
+			resp.Error = perr
+		} else if result != nil {
+			data, err := json.Marshal(result)
+			if err != nil {
+				return err
+			}
+			resp.Result = data
+		}
+
+		err = enc.Encode(resp)
+		if err != nil {
+			return err
+		}
+		if req.Method == MethodShutdown {
+			return nil
+		}
+	}
+}
+
+func dispatch(s Server, req *Request) (interface{}, error) {
+	if req.Version != ProtocolVersion {
+		return nil, errors.Errorf("plugin speaks protocol version %d, but received a version %d request", ProtocolVersion, req.Version)
+	}
+
+	var err error
+	switch req.Method {
+	case MethodHandshake:
+		info := s.Info()
+		info.ProtocolVersion = ProtocolVersion
+		return info, nil
+	case MethodValidations:
+		return s.Validations(), nil
+	case MethodShutdown:
+		return nil, nil
+	case MethodSetLaforge:
+		snap := &Snapshot{}
+		err = json.Unmarshal(req.Params, snap)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not decode snapshot")
+		}
+		err = s.SetLaforge(snap)
+	case MethodCheckRequirements:
+		err = s.CheckRequirements()
+	case MethodPrepareAssets:
+		err = s.PrepareAssets()
+	case MethodGenerateScripts:
+		err = s.GenerateScripts()
+	case MethodStageDependencies:
+		err = s.StageDependencies()
+	case MethodRender:
+		err = s.Render()
+	default:
+		return nil, errors.Errorf("unknown method %s", req.Method)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := Result{}
+	if c, ok := s.(Configurer); ok {
+		res.Config = c.Config()
+	}
+	return res, nil
+}
@@ -0,0 +1,947 @@
+// Package tfaws implements a Laforge Builder module for generating terraform configurations that target Amazon Web Services.
+package tfaws
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gen0cide/laforge/agent"
+	"github.com/gen0cide/laforge/builder/tfaws/static"
+	"github.com/gen0cide/laforge/core/cli"
+	"github.com/hashicorp/hcl/hcl/printer"
+
+	"github.com/gen0cide/laforge/builder/buildutil/templates"
+	validations "github.com/gen0cide/laforge/builder/buildutil/valdations"
+
+	"github.com/pkg/errors"
+
+	"github.com/gen0cide/laforge/builder/buildutil"
+	"github.com/gen0cide/laforge/core"
+)
+
+// Definition of builder meta-data.
+const (
+	ID          = `tfaws`
+	Name        = `Terraform AWS Builder`
+	Description = `generates terraform configurations that isolate teams into VPCs on Amazon Web Services`
+	Author      = `Alex Levinson <github.com/gen0cide>`
+	Version     = `0.0.1`
+)
+
+var (
+	rules = validations.Validations{
+		validations.Requirement{
+			Name:       "Environment maintainer not defined",
+			Resolution: "add a maintainer block to your environment configuration",
+			Check:      validations.FieldNotEmpty(core.Environment{}, "Maintainer"),
+		},
+		validations.Requirement{
+			Name:       "DNS not defined",
+			Resolution: "add a DNS block to your competition configuration",
+			Check:      validations.FieldNotEmpty(core.Competition{}, "DNS"),
+		},
+		validations.Requirement{
+			Name:       "DNS Root Domain not defined",
+			Resolution: "set the root_domain parameter in your DNS config block",
+			Check:      validations.FieldNotEmpty(core.DNS{}, "RootDomain"),
+		},
+		validations.Requirement{
+			Name:       "terraform executable not located in path",
+			Resolution: "download and ensure that terraform CLI is installed to a valid location in your PATH",
+			Check:      validations.ExistsInPath("terraform"),
+		},
+		validations.Requirement{
+			Name:       "AWS Access Key not defined",
+			Resolution: "define an aws_access_key value inside your environment config = { ... } block.",
+			Check:      validations.HasConfigKey(core.Environment{}, "aws_access_key"),
+		},
+		validations.Requirement{
+			Name:       "AWS Secret Key not defined",
+			Resolution: "define an aws_secret_key value inside your environment config = { ... } block.",
+			Check:      validations.HasConfigKey(core.Environment{}, "aws_secret_key"),
+		},
+		validations.Requirement{
+			Name:       "AWS Region not defined",
+			Resolution: "define an aws_region value inside your environment config = { ... } block.",
+			Check:      validations.HasConfigKey(core.Environment{}, "aws_region"),
+		},
+		validations.Requirement{
+			Name:       "AWS Availability Zone not defined",
+			Resolution: "define an aws_availability_zone value inside your environment config = { ... } block.",
+			Check:      validations.HasConfigKey(core.Environment{}, "aws_availability_zone"),
+		},
+		validations.Requirement{
+			Name:       "AWS S3 state bucket not defined",
+			Resolution: "define an aws_s3_bucket value inside your environment config = { ... } block.",
+			Check:      validations.HasConfigKey(core.Environment{}, "aws_s3_bucket"),
+		},
+		validations.Requirement{
+			Name:       "vpc CIDR not defined",
+			Resolution: "define a vpc_cidr value inside your environment config = { ... } block.",
+			Check:      validations.HasConfigKey(core.Environment{}, "vpc_cidr"),
+		},
+		validations.Requirement{
+			Name:       "no teams specified",
+			Resolution: "make sure to set your team_count inside your environment config block to at least 1.",
+			Check:      validations.FieldNotEmpty(core.Environment{}, "team_count"),
+		},
+		validations.Requirement{
+			Name:       "admin IP not defined",
+			Resolution: "define an admin_ip value inside your environment config = { ... } block.",
+			Check:      validations.HasConfigKey(core.Environment{}, "admin_ip"),
+		},
+		validations.Requirement{
+			Name:       "No networks have been included",
+			Resolution: "Use the included_network \"$network_id\" { ... } block inside of your environment config to include networks.",
+			Check:      validations.FieldNotEmpty(core.Environment{}, "IncludedNetworks"),
+		},
+		validations.Requirement{
+			Name:       "No hosts were included",
+			Resolution: "Check your included_network blocks. The field included_hosts = [ ... ] should be populated with host IDs.",
+			Check:      validations.FieldNotEmpty(core.Environment{}, "IncludedHosts"),
+		},
+		validations.Requirement{
+			Name:       "No CIDR defined for network",
+			Resolution: "Check that network declarations have a cidr = ... defined in them.",
+			Check:      validations.FieldNotEmpty(core.Network{}, "CIDR"),
+		},
+		validations.Requirement{
+			Name:       "No OS defined for a host",
+			Resolution: "Check that all host declarations have an os = ... attribute defined.",
+			Check:      validations.FieldNotEmpty(core.Host{}, "OS"),
+		},
+		validations.Requirement{
+			Name:       "No hostname defined for a host",
+			Resolution: "Check that all host declarations have a hostname = ... attribute defined.",
+			Check:      validations.FieldNotEmpty(core.Host{}, "Hostname"),
+		},
+		validations.Requirement{
+			Name:       "No Instance Size defined for a host",
+			Resolution: "Check that all host declarations have an associated instance_size = ... attribute defined.",
+			Check:      validations.FieldNotEmpty(core.Host{}, "InstanceSize"),
+		},
+		validations.Requirement{
+			Name:       "No disk defined for a host",
+			Resolution: "Ensure that every host declaration has an accompanied disk { size = ... } block defined.",
+			Check:      validations.FieldNotEmpty(core.Host{}, "Disk"),
+		},
+		validations.Requirement{
+			Name:       "No user_data_script_id defined for a host",
+			Resolution: "Ensure that every host declaration has a var defined for key user_data_script_id.",
+			Check:      validations.HasVarDefined(core.Host{}, "user_data_script_id"),
+		},
+		validations.Requirement{
+			Name:       "Host uses an address reserved by AWS",
+			Resolution: "AWS reserves the first four addresses of every subnet. Make sure every host has a last_octet of 4 or greater.",
+			Check:      lastOctetsOutsideReserved,
+		},
+		validations.Requirement{
+			Name:       "Host exposes an invalid port",
+			Resolution: "Entries in exposed_tcp_ports and exposed_udp_ports must be a port (\"80\") or port range (\"8000-8100\") between 1 and 65535.",
+			Check:      exposedPortsValid,
+		},
+		validations.Requirement{
+			Name:       "AMI could not be resolved for a host",
+			Resolution: "Set the host's ami attribute to the ID of a declared ami { ... } block with provider = \"aws\", a literal ami-* image ID, or use an os with a known default AMI.",
+			Check:      amisResolvable,
+		},
+	}
+
+	templatesToLoad = []string{
+		"infra.tf.tmpl",
+	}
+
+	additionalTemplates = []string{
+		"ami.tf.tmpl",
+		"provisioned_host.tf.tmpl",
+	}
+
+	primaryTemplate = "infra.tf.tmpl"
+
+	// defaultAMIs are used to locate an image when a host declares an OS, but no explicit AMI.
+	// The Name is used as an image name filter and the "owner" var is the owning AWS account.
+	defaultAMIs = map[string]*core.AMI{
+		"ubuntu16": {
+			ID:       "aws-ubuntu16",
+			Name:     "ubuntu/images/hvm-ssd/ubuntu-xenial-16.04-amd64-server-*",
+			Provider: "aws",
+			Username: "ubuntu",
+			Vars:     map[string]string{"owner": "099720109477"},
+		},
+		"ubuntu18": {
+			ID:       "aws-ubuntu18",
+			Name:     "ubuntu/images/hvm-ssd/ubuntu-bionic-18.04-amd64-server-*",
+			Provider: "aws",
+			Username: "ubuntu",
+			Vars:     map[string]string{"owner": "099720109477"},
+		},
+		"centos7": {
+			ID:       "aws-centos7",
+			Name:     "CentOS Linux 7 x86_64 HVM EBS *",
+			Provider: "aws",
+			Username: "centos",
+			Vars:     map[string]string{"owner": "679593333241"},
+		},
+		"debian9": {
+			ID:       "aws-debian9",
+			Name:     "debian-stretch-hvm-x86_64-gp2-*",
+			Provider: "aws",
+			Username: "admin",
+			Vars:     map[string]string{"owner": "379101102735"},
+		},
+		"w2k8": {
+			ID:       "aws-w2k8",
+			Name:     "Windows_Server-2008-R2_SP1-English-64Bit-Base-*",
+			Provider: "aws",
+			Username: "Administrator",
+			Vars:     map[string]string{"owner": "801119661308"},
+		},
+		"w2k12": {
+			ID:       "aws-w2k12",
+			Name:     "Windows_Server-2012-R2_RTM-English-64Bit-Base-*",
+			Provider: "aws",
+			Username: "Administrator",
+			Vars:     map[string]string{"owner": "801119661308"},
+		},
+		"w2k16": {
+			ID:       "aws-w2k16",
+			Name:     "Windows_Server-2016-English-Full-Base-*",
+			Provider: "aws",
+			Username: "Administrator",
+			Vars:     map[string]string{"owner": "801119661308"},
+		},
+	}
+)
+
+// TerraformAWSBuilder implements a laforge builder that packages an environment into
+// a terraform configuration targeting AWS with each team isolated into their own VPC.
+type TerraformAWSBuilder struct {
+	sync.RWMutex
+
+	// Required for the Builder interface
+	Base *core.Laforge
+
+	// A place to store the templates
+	Library *templates.Library
+
+	// AMIs maps included host IDs to the AMI they will be launched from
+	AMIs map[string]*core.AMI
+
+	// AgentCA issues the TLS credentials of each host's agent
+	AgentCA *agent.CertificateAuthority
+}
+
+// Get retrieves an element from the embedded KV store
+func (t *TerraformAWSBuilder) Get(key string) string {
+	t.Lock()
+	defer t.Unlock()
+	res, ok := t.Base.CurrentBuild.Config[key]
+	if ok {
+		return res
+	}
+	r0, e0 := t.Base.CurrentEnv.Config[key]
+	if e0 {
+		// cached directly since Set would deadlock on the lock already held here
+		t.Base.CurrentBuild.Config[key] = r0
+		return r0
+	}
+	return ""
+}
+
+// Set assigns an element to the embedded KV store
+func (t *TerraformAWSBuilder) Set(key string, val interface{}) {
+	t.Lock()
+	defer t.Unlock()
+	t.Base.CurrentBuild.Config[key] = fmt.Sprintf("%v", val)
+}
+
+// New creates an empty TerraformAWSBuilder
+func New() *TerraformAWSBuilder {
+	lib := templates.NewLibrary()
+	return &TerraformAWSBuilder{
+		Library: lib,
+		AMIs:    map[string]*core.AMI{},
+	}
+}
+
+// ID implements the Builder interface (returns the ID of the builder - usually the go package name)
+func (t *TerraformAWSBuilder) ID() string {
+	return ID
+}
+
+// Name implements the Builder interface (returns the name of the builder - usually titleized version of the type)
+func (t *TerraformAWSBuilder) Name() string {
+	return Name
+}
+
+// Description implements the Builder interface (returns the builder's description)
+func (t *TerraformAWSBuilder) Description() string {
+	return Description
+}
+
+// Author implements the Builder interface (author's name and contact info)
+func (t *TerraformAWSBuilder) Author() string {
+	return Author
+}
+
+// Version implements the Builder interface (builder version)
+func (t *TerraformAWSBuilder) Version() string {
+	return Version
+}
+
+// Validations implements the Builder interface (builder checks)
+func (t *TerraformAWSBuilder) Validations() validations.Validations {
+	return rules
+}
+
+// SetLaforge implements the Builder interface
+func (t *TerraformAWSBuilder) SetLaforge(base *core.Laforge) error {
+	t.Base = base
+	if !base.ClearToBuild {
+		return buildutil.Throw(errors.New("context is not cleared to build"), "Laforge has encountered an error and cannot continue to build. This is likely a bug in LaForge.", nil)
+	}
+	for _, x := range templatesToLoad {
+		d, err := static.ReadFile(x)
+		if err != nil {
+			return buildutil.Throw(err, "could not read template", &buildutil.V{"template_name": x})
+		}
+		_, err = t.Library.AddBook(x, d)
+		if err != nil {
+			return buildutil.Throw(err, "could not parse template", &buildutil.V{"template_name": x})
+		}
+	}
+	for _, x := range additionalTemplates {
+		d, err := static.ReadFile(x)
+		if err != nil {
+			return buildutil.Throw(err, "could not read template", &buildutil.V{"template_name": x})
+		}
+		_, err = t.Library.AddBook(x, d)
+		if err != nil {
+			return buildutil.Throw(err, "could not parse template", &buildutil.V{"template_name": x})
+		}
+	}
+	return nil
+}
+
+// CheckRequirements implements the Builder interface
+func (t *TerraformAWSBuilder) CheckRequirements() error {
+	return nil
+}
+
+// PrepareAssets implements the Builder interface
+func (t *TerraformAWSBuilder) PrepareAssets() error {
+	var privkey, pubkey string
+	pathToPubkey := filepath.Join(t.Base.CurrentBuild.Dir, "data", "ssh.pem.pub")
+	pathToPrivkey := filepath.Join(t.Base.CurrentBuild.Dir, "data", "ssh.pem")
+
+	if _, err := os.Stat(pathToPubkey); os.IsNotExist(err) {
+		privkey, pubkey, err = buildutil.GenerateSSHKeyPair(2048)
+		if err != nil {
+			return buildutil.Throw(err, "Could not generate a 2048-bit RSA SSH key.", nil)
+		}
+		err = buildutil.WriteKeyfile([]byte(privkey), pathToPrivkey)
+		if err != nil {
+			return buildutil.Throw(err, "Could not write the the SSH private key to the build directory", &buildutil.V{"path": pathToPrivkey})
+		}
+		err = buildutil.WriteKeyfile([]byte(pubkey), pathToPubkey)
+		if err != nil {
+			return buildutil.Throw(err, "Could not write the the SSH public key to the build directory", &buildutil.V{"path": pathToPubkey})
+		}
+	} else {
+		pubkeyData, pubkeyErr := ioutil.ReadFile(pathToPubkey)
+		if pubkeyErr != nil {
+			return buildutil.Throw(pubkeyErr, "could not read already established public key", nil)
+		}
+		privkeyData, privkeyErr := ioutil.ReadFile(pathToPrivkey)
+		if privkeyErr != nil {
+			return buildutil.Throw(privkeyErr, "could not read already established private key", nil)
+		}
+		privkey = string(privkeyData)
+		pubkey = string(pubkeyData)
+	}
+
+	t.Set("ssh_public_key_file", pathToPubkey)
+	t.Set("ssh_private_key_file", pathToPrivkey)
+	t.Set("rel_ssh_public_key_file", "../../data/ssh.pem.pub")
+	t.Set("rel_ssh_private_key_file", "../../data/ssh.pem")
+	t.Set("ssh_public_key", pubkey)
+	t.Set("ssh_private_key", privkey)
+
+	agentCA, err := agent.LoadOrCreateBuildCA(filepath.Join(t.Base.CurrentBuild.Dir, "data"))
+	if err != nil {
+		return buildutil.Throw(err, "Could not prepare the agent certificate authority in the build directory", nil)
+	}
+	t.AgentCA = agentCA
+
+	for hostid, host := range t.Base.CurrentEnv.IncludedHosts {
+		ami, err := LookupAMI(t.Base, host)
+		if err != nil {
+			return buildutil.Throw(err, "Validation for this passed, but the host's AMI could not be resolved. Likely a bug. Please report.", &buildutil.V{"host_id": hostid, "ami": host.AMI})
+		}
+		t.AMIs[hostid] = ami
+
+		uds, found := host.Vars["user_data_script_id"]
+		if !found {
+			return buildutil.Throw(errors.New("user_data_script_id no longer exists"), "Validation for this passed, but here we are. Likely a bug. Please report.", &buildutil.V{"host_id": hostid})
+		}
+		udsObj, found := t.Base.Scripts[uds]
+		if !found {
+			return buildutil.Throw(errors.Errorf("user_data_script_id %s not found", uds), "Host declares a user_data_script_id which was not found in the script map. Is this declared somewhere?", &buildutil.V{"host": hostid})
+		}
+		if _, ok := host.Scripts[uds]; ok {
+			cli.Logger.Infof("UDS %s is already defined for host %s (strange?)", uds, hostid)
+			continue
+		}
+		cli.Logger.Debugf("Adding user_data_script %s to host %s script pool", uds, hostid)
+		host.Scripts[uds] = udsObj
+		if _, ok := t.Library.Books[uds]; !ok {
+			for _, callfile := range udsObj.Caller {
+				pr, ok := t.Base.PathRegistry.DB[callfile]
+				if !ok {
+					continue
+				}
+				lfr, ok := pr.Mapping[udsObj.Source]
+				if !ok {
+					continue
+				}
+				data, err := ioutil.ReadFile(lfr.AbsPath)
+				if err != nil {
+					return err
+				}
+				_, err = t.Library.AddBook(udsObj.Path(), data)
+				if err != nil {
+					return err
+				}
+				break
+			}
+		}
+
+		for _, dep := range host.Dependencies {
+			depHost, ok := t.Base.CurrentEnv.IncludedHosts[dep.HostID]
+			if !ok {
+				return buildutil.Throw(errors.Errorf("host %s depends on host %s, which is not found in environment", host.ID, dep.HostID), "The host listed a dependency to another host which is not included in any network within the current environment.", &buildutil.V{"source_host": hostid, "depends_on_host": dep.HostID})
+			}
+			dep.Host = depHost
+
+			depNet, ok := t.Base.CurrentEnv.IncludedNetworks[dep.NetworkID]
+			if !ok {
+				return buildutil.Throw(errors.Errorf("host %s depends on network %s, which is not found in environment", host.ID, dep.NetworkID), "The host listed a dependency to another network which is not included within the current environment.", &buildutil.V{"source_host": hostid, "depends_on_host": dep.HostID, "depends_on_network": dep.NetworkID})
+			}
+			dep.Network = depNet
+
+			hostInNetwork := false
+			for _, x := range t.Base.CurrentEnv.HostByNetwork[dep.NetworkID] {
+				if x.ID == dep.Host.ID {
+					hostInNetwork = true
+					break
+				}
+			}
+			if !hostInNetwork {
+				return buildutil.Throw(errors.Errorf("host %s depends on host %s, which is not included in network %s", host.ID, dep.HostID, dep.NetworkID), "The host listed a dependency to another host, and while the network exists and is included, this host is not present within this network assignment.", &buildutil.V{"source_host": hostid, "depends_on_host": dep.HostID, "depends_on_network": dep.NetworkID})
+			}
+
+			if dep.Step != "" {
+				located := false
+				for stepidx, x := range dep.Host.ProvisionSteps {
+					if dep.Step == x {
+						located = true
+						dep.StepID = stepidx
+						break
+					}
+				}
+				if !located {
+					return buildutil.Throw(errors.Errorf("host %s depends on provisioning step %s, which is not found in host %s", host.ID, dep.Step, dep.Host.ID), "The host listed a dependency to a provisioning step that is not included within the supplied host's provisioning steps.", &buildutil.V{"source_host": hostid, "depends_on_host": dep.HostID, "depends_on_network": dep.NetworkID, "depends_on_step": dep.Step})
+				}
+			} else {
+				dep.StepID = dep.Host.FinalStepID()
+			}
+		}
+	}
+
+	return nil
+}
+
+// GenerateScripts implements the Builder interface
+func (t *TerraformAWSBuilder) GenerateScripts() error {
+	wg := new(sync.WaitGroup)
+	errChan := make(chan error, 1)
+	finChan := make(chan bool, 1)
+	user := t.Base.User
+	ctx, err := templates.NewContext(
+		t.Base,
+		t.Base.CurrentBuild,
+		t.Base.CurrentCompetition,
+		t.Base.CurrentCompetition.DNS,
+		t.Base.CurrentEnv,
+		user,
+	)
+	if err != nil {
+		return err
+	}
+	for _, teamObj := range t.Base.CurrentBuild.Teams {
+		wg.Add(1)
+		go func(team *core.Team) {
+			defer wg.Done()
+			for netName, hosts := range t.Base.CurrentEnv.HostByNetwork {
+				network := t.Base.CurrentEnv.IncludedNetworks[netName]
+				for _, host := range hosts {
+					for sid, script := range host.Scripts {
+						wg.Add(1)
+						go func(scriptID string, scriptObj *core.Script, hostObj *core.Host) {
+							defer wg.Done()
+							scriptCtx := ctx.Clone()
+							tid := team.ID
+							pnid := filepath.Join(tid, "networks", network.Base())
+							pnobj := t.Base.StateManager.Current.Metastore[pnid].Dependency.(*core.ProvisionedNetwork)
+							phid := filepath.Join(pnid, "hosts", hostObj.Base())
+							phobj := t.Base.StateManager.Current.Metastore[phid].Dependency.(*core.ProvisionedHost)
+							var pstep *core.ProvisioningStep
+							for _, x := range phobj.ProvisioningSteps {
+								if x.ProvisionerID == scriptID {
+									pstep = x
+									break
+								}
+							}
+							conn := phobj.Conn
+							err := scriptCtx.Attach(team, network, hostObj, scriptObj, pnobj, phobj, pstep, conn)
+							if err != nil {
+								errChan <- err
+								return
+							}
+							filename := filepath.Base(scriptObj.Source)
+							assetDir := filepath.Join(team.RelBuildPath, "networks", network.Base(), "hosts", hostObj.Base(), "assets")
+							assetPath := filepath.Join(assetDir, filename)
+							fileData, err := t.Library.Execute(scriptID, scriptCtx)
+							if err != nil {
+								errChan <- err
+								return
+							}
+							err = ioutil.WriteFile(assetPath, fileData, 0644)
+							if err != nil {
+								errChan <- err
+								return
+							}
+							return
+						}(sid, script, host)
+					}
+					wg.Add(1)
+					go func(h *core.Host) {
+						defer wg.Done()
+						tid := team.ID
+						pnid := filepath.Join(tid, "networks", network.Base())
+						pnobj := t.Base.StateManager.Current.Metastore[pnid].Dependency.(*core.ProvisionedNetwork)
+						phid := filepath.Join(pnid, "hosts", h.Base())
+						phobj := t.Base.StateManager.Current.Metastore[phid].Dependency.(*core.ProvisionedHost)
+						scriptCtx := ctx.Clone()
+						err := scriptCtx.Attach(team, network, h, pnobj, phobj)
+						if err != nil {
+							errChan <- err
+							return
+						}
+						filename := "provisioned_host.tpl"
+						assetDir := filepath.Join(team.RelBuildPath, "networks", network.Base(), "hosts", h.Base(), "assets")
+						assetPath := filepath.Join(assetDir, filename)
+						fileData, err := t.Library.Execute("provisioned_host.tf.tmpl", scriptCtx)
+						if err != nil {
+							errChan <- err
+							return
+						}
+						err = ioutil.WriteFile(assetPath, fileData, 0644)
+						if err != nil {
+							errChan <- err
+							return
+						}
+						return
+					}(host)
+				}
+			}
+
+		}(teamObj)
+	}
+
+	go func() {
+		wg.Wait()
+		close(finChan)
+	}()
+
+	select {
+	case <-finChan:
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+// StageDependencies implements the Builder interface
+func (t *TerraformAWSBuilder) StageDependencies() error {
+	if t.Base.StateManager == nil {
+		return errors.New("builder cannot stage dependencies with nil state manager")
+	}
+	build := t.Base.CurrentBuild
+	for _, team := range build.Teams {
+		teamDir := filepath.Join(build.Dir, "teams", fmt.Sprintf("%v", team.TeamNumber))
+		team.RelBuildPath = teamDir
+		os.MkdirAll(teamDir, 0755)
+		core.TouchGitKeep(teamDir)
+		teamCfg, err := core.RenderHCLv2Object(team)
+		if err != nil {
+			return err
+		}
+		teamCfgFile := filepath.Join(teamDir, "team.laforge")
+		err = ioutil.WriteFile(teamCfgFile, teamCfg, 0644)
+		if err != nil {
+			return err
+		}
+		for _, pn := range team.ProvisionedNetworks {
+			netdir := filepath.Join(teamDir, "networks", pn.Base())
+			os.MkdirAll(netdir, 0755)
+			core.TouchGitKeep(netdir)
+			data, err := core.RenderHCLv2Object(pn)
+			if err != nil {
+				return err
+			}
+			netfile := filepath.Join(netdir, "provisioned_network.laforge")
+			err = ioutil.WriteFile(netfile, data, 0644)
+			if err != nil {
+				return err
+			}
+			for _, ph := range pn.ProvisionedHosts {
+				hostdir := filepath.Join(netdir, "hosts", ph.Base())
+				os.Mkdir(hostdir, 0755)
+				core.TouchGitKeep(hostdir)
+				agentdir := filepath.Join(hostdir, "agent")
+				assetdir := filepath.Join(hostdir, "assets")
+				stepdir := filepath.Join(hostdir, "steps")
+				os.MkdirAll(agentdir, 0755)
+				os.MkdirAll(assetdir, 0755)
+				os.MkdirAll(stepdir, 0755)
+				core.TouchGitKeep(agentdir)
+				core.TouchGitKeep(assetdir)
+				core.TouchGitKeep(stepdir)
+				data, err = core.RenderHCLv2Object(ph)
+				if err != nil {
+					return err
+				}
+				hostfile := filepath.Join(hostdir, "provisioned_host.laforge")
+				err = ioutil.WriteFile(hostfile, data, 0644)
+				if err != nil {
+					return err
+				}
+				for _, ps := range ph.ProvisioningSteps {
+					stepfile := filepath.Join(stepdir, fmt.Sprintf("%s.laforge", ps.Base()))
+					data, err = core.RenderHCLv2Object(ps)
+					if err != nil {
+						return err
+					}
+					err = ioutil.WriteFile(stepfile, data, 0644)
+					if err != nil {
+						return err
+					}
+					if rfile, ok := ps.Provisioner.(*core.RemoteFile); ok {
+						rfileName, err := rfile.AssetName()
+						if err != nil {
+							return err
+						}
+
+						dstPath := filepath.Join(t.Base.CurrentBuild.Dir, "data", rfileName)
+						if _, err := os.Stat(dstPath); os.IsNotExist(err) {
+							copyErr := rfile.CopyTo(dstPath)
+							if copyErr != nil {
+								return copyErr
+							}
+						}
+					}
+					if script, ok := ps.Provisioner.(*core.Script); ok {
+						if _, ok := t.Library.Books[script.Path()]; ok {
+							continue
+						}
+						if script.Source == "" {
+							continue
+						}
+						for _, callfile := range script.Caller {
+							pr, ok := t.Base.PathRegistry.DB[callfile]
+							if !ok {
+								continue
+							}
+							lfr, ok := pr.Mapping[script.Source]
+							if !ok {
+								continue
+							}
+							data, err := ioutil.ReadFile(lfr.AbsPath)
+							if err != nil {
+								return err
+							}
+							_, err = t.Library.AddBook(script.Path(), data)
+							if err != nil {
+								return err
+							}
+							break
+						}
+					}
+				}
+			}
+		}
+
+	}
+	return nil
+}
+
+// Render implements the Builder interface
+func (t *TerraformAWSBuilder) Render() error {
+	wg := new(sync.WaitGroup)
+	errChan := make(chan error, 1)
+	finChan := make(chan bool, 1)
+	for _, team := range t.Base.CurrentBuild.Teams {
+		wg.Add(1)
+		go func(team *core.Team) {
+			defer wg.Done()
+
+			teamDir := team.RelBuildPath
+			user := t.Base.User
+			ctx, err := templates.NewContext(
+				t.Base,
+				t.Base.CurrentBuild,
+				t.Base.CurrentCompetition,
+				t.Base.CurrentCompetition.DNS,
+				t.Base.CurrentEnv,
+				user,
+				team,
+			)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			amiData, err := t.renderAMIs(ctx)
+			if err != nil {
+				errChan <- buildutil.Throw(err, "ami template failed", &buildutil.V{
+					"team": team.Path(),
+					"dir":  teamDir,
+				})
+				return
+			}
+			cfgData, err := t.Library.ExecuteGroup(primaryTemplate, templatesToLoad, ctx)
+			if err != nil {
+				errChan <- buildutil.Throw(err, "template failed", &buildutil.V{
+					"team": team.Path(),
+					"dir":  teamDir,
+				})
+				return
+			}
+			cfgData = append(cfgData, amiData...)
+			hclPretty, err := printer.Format(cfgData)
+			cfgFile := filepath.Join(teamDir, "infra.tf")
+			if err != nil {
+				ioutil.WriteFile(cfgFile, cfgData, 0644)
+				errChan <- err
+				return
+			}
+			err = ioutil.WriteFile(cfgFile, hclPretty, 0644)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			for netname, net := range t.Base.CurrentEnv.IncludedNetworks {
+				for _, host := range t.Base.CurrentEnv.HostByNetwork[netname] {
+					ts := time.Now()
+					state := &agent.State{
+						Team:         team,
+						Network:      net,
+						Steps:        []*agent.Step{},
+						RenderedAt:   ts,
+						Revision:     ts.UTC().Unix(),
+						CurrentState: "pending",
+					}
+					for pid, prov := range host.Provisioners {
+						step := &agent.Step{
+							ID:       pid,
+							StepType: prov.Kind(),
+							Metadata: map[string]interface{}{},
+						}
+						state.Steps = append(state.Steps, step)
+					}
+					jsonData, err := json.MarshalIndent(state, "", "  ")
+					if err != nil {
+						errChan <- err
+						return
+					}
+					stateFilePath := filepath.Join(teamDir, "networks", net.Base(), "hosts", host.Base(), "agent", "config.json")
+					err = ioutil.WriteFile(stateFilePath, jsonData, 0644)
+					if err != nil {
+						errChan <- err
+						return
+					}
+					phID := path.Join(team.Path(), "networks", net.Base(), "hosts", host.Base())
+					err = t.AgentCA.WriteAgentCredentials(filepath.Dir(stateFilePath), phID, host.Hostname)
+					if err != nil {
+						errChan <- err
+						return
+					}
+				}
+			}
+		}(team)
+	}
+	go func() {
+		wg.Wait()
+		close(finChan)
+	}()
+
+	select {
+	case <-finChan:
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+// renderAMIs records the image and login user of every included host into the context dictionary
+// and returns the aws_ami data sources needed to look up any AMIs that are not literal image IDs.
+func (t *TerraformAWSBuilder) renderAMIs(ctx *templates.Context) ([]byte, error) {
+	rendered := map[string]bool{}
+	data := []byte{}
+	for hostid, host := range t.Base.CurrentEnv.IncludedHosts {
+		ami, ok := t.AMIs[hostid]
+		if !ok {
+			return nil, errors.Errorf("no AMI was resolved for host %s", hostid)
+		}
+		ctx.Set(fmt.Sprintf("ami_user_%s", host.Base()), AMIUsername(ami, host))
+		if imageID := AMIImageID(ami); imageID != "" {
+			ctx.Set(fmt.Sprintf("ami_%s", host.Base()), imageID)
+			continue
+		}
+		resourceName := fmt.Sprintf("lf-%s", ami.Base())
+		ctx.Set(fmt.Sprintf("ami_%s", host.Base()), fmt.Sprintf("${data.aws_ami.%s.id}", resourceName))
+		if rendered[resourceName] {
+			continue
+		}
+		rendered[resourceName] = true
+		amiCtx := ctx.Clone()
+		err := amiCtx.Attach(ami)
+		if err != nil {
+			return nil, err
+		}
+		amiCtx.Set("ami_resource_name", resourceName)
+		amiCtx.Set("ami_owner", AMIOwner(ami))
+		amiData, err := t.Library.Execute("ami.tf.tmpl", amiCtx)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, '\n')
+		data = append(data, amiData...)
+	}
+	return data, nil
+}
+
+// LookupAMI resolves the AMI a host should be launched from. A host's ami attribute can reference
+// the ID of a declared ami block or a literal ami-* image ID. Hosts without an ami attribute fall
+// back to a default AMI for their OS.
+func LookupAMI(base *core.Laforge, host *core.Host) (*core.AMI, error) {
+	if host.AMI == "" {
+		ami, ok := defaultAMIs[strings.ToLower(host.OS)]
+		if !ok {
+			return nil, errors.Errorf("host %s has no ami defined and there is no default AMI for os %s", host.ID, host.OS)
+		}
+		return ami, nil
+	}
+	if ami, ok := base.AMIs[host.AMI]; ok {
+		if ami.Provider != "" && ami.Provider != "aws" {
+			return nil, errors.Errorf("host %s uses ami %s which targets provider %s", host.ID, ami.ID, ami.Provider)
+		}
+		return ami, nil
+	}
+	if strings.HasPrefix(host.AMI, "ami-") {
+		return &core.AMI{
+			ID:       host.AMI,
+			Name:     host.AMI,
+			Provider: "aws",
+		}, nil
+	}
+	return nil, errors.Errorf("host %s uses ami %s which is not defined", host.ID, host.AMI)
+}
+
+// AMIImageID returns the literal image ID of an AMI, or an empty string if it must be looked up by name.
+func AMIImageID(ami *core.AMI) string {
+	if id := ami.Vars["image_id"]; id != "" {
+		return id
+	}
+	if strings.HasPrefix(ami.Name, "ami-") {
+		return ami.Name
+	}
+	return ""
+}
+
+// AMIOwner returns the AWS account that owns an AMI, defaulting to the current account.
+func AMIOwner(ami *core.AMI) string {
+	if owner := ami.Vars["owner"]; owner != "" {
+		return owner
+	}
+	return "self"
+}
+
+// AMIUsername returns the user that terraform should connect as when first provisioning the host.
+func AMIUsername(ami *core.AMI, host *core.Host) string {
+	if ami.Username != "" {
+		return ami.Username
+	}
+	if host.IsWindows() {
+		return "Administrator"
+	}
+	return "root"
+}
+
+func amisResolvable(base *core.Laforge) bool {
+	for id, host := range base.CurrentEnv.IncludedHosts {
+		if _, err := LookupAMI(base, host); err != nil {
+			cli.Logger.Errorf("host %s has failed a validation: %v", id, err)
+			return false
+		}
+	}
+	return true
+}
+
+func lastOctetsOutsideReserved(base *core.Laforge) bool {
+	for id, host := range base.CurrentEnv.IncludedHosts {
+		if host.LastOctet < 4 {
+			cli.Logger.Errorf("host %s has failed a validation: last_octet %d is reserved by AWS", id, host.LastOctet)
+			return false
+		}
+	}
+	return true
+}
+
+func exposedPortsValid(base *core.Laforge) bool {
+	for id, host := range base.CurrentEnv.IncludedHosts {
+		ports := append([]string{}, host.ExposedTCPPorts...)
+		ports = append(ports, host.ExposedUDPPorts...)
+		for _, p := range ports {
+			if !validPortRange(p) {
+				cli.Logger.Errorf("host %s has failed a validation: exposed port %q is not a valid port or port range", id, p)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func validPortRange(p string) bool {
+	parts := strings.Split(p, "-")
+	if len(parts) > 2 {
+		return false
+	}
+	last := 0
+	for _, x := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(x))
+		if err != nil || n < 1 || n > 65535 || n < last {
+			return false
+		}
+		last = n
+	}
+	return true
+}
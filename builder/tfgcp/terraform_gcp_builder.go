@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"sync"
 	"time"
@@ -193,6 +194,9 @@ type TerraformGCPBuilder struct {
 
 	// A place to store the templates
 	Library *templates.Library
+
+	// AgentCA issues the TLS credentials of each host's agent
+	AgentCA *agent.CertificateAuthority
 }
 
 // Get retrieves an element from the embedded KV store
@@ -329,6 +333,12 @@ func (t *TerraformGCPBuilder) PrepareAssets() error {
 	t.Set("ssh_public_key", pubkey)
 	t.Set("ssh_private_key", privkey)
 
+	agentCA, err := agent.LoadOrCreateBuildCA(filepath.Join(t.Base.CurrentBuild.Dir, "data"))
+	if err != nil {
+		return buildutil.Throw(err, "Could not prepare the agent certificate authority in the build directory", nil)
+	}
+	t.AgentCA = agentCA
+
 	for hostid, host := range t.Base.CurrentEnv.IncludedHosts {
 		uds, found := host.Vars["user_data_script_id"]
 		if !found {
@@ -734,6 +744,12 @@ func (t *TerraformGCPBuilder) Render() error {
 						errChan <- err
 						return
 					}
+					phID := path.Join(team.Path(), "networks", net.Base(), "hosts", host.Base())
+					err = t.AgentCA.WriteAgentCredentials(filepath.Dir(stateFilePath), phID, host.Hostname)
+					if err != nil {
+						errChan <- err
+						return
+					}
 				}
 			}
 		}(team)
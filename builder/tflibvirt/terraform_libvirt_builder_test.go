@@ -0,0 +1,190 @@
+package tflibvirt_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gen0cide/laforge/builder"
+	"github.com/gen0cide/laforge/core"
+	"github.com/gen0cide/laforge/core/cli"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden with the rendered output")
+
+// volatile matches the parts of the rendered output which change on every build
+var volatile = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`\\"timestamp\\":\\"[^\\]*\\"`), `\"timestamp\":\"<timestamp>\"`},
+}
+
+// goldenFiles are the rendered files, relative to the build directory, which are compared against testdata/golden
+var goldenFiles = []string{
+	"teams/0/infra.tf",
+	"teams/1/infra.tf",
+	"teams/0/networks/corp/hosts/dc01/assets/provisioned_host.tpl",
+	"teams/0/networks/corp/hosts/web01/assets/provisioned_host.tpl",
+	"teams/0/networks/corp/hosts/web01/assets/linux_bootstrap.sh",
+	"teams/0/networks/dmz/hosts/web01/assets/linux_bootstrap.sh",
+}
+
+// copyTree copies the directory src to dst
+func copyTree(t *testing.T, src, dst string) {
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(target, data, info.Mode())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setupFixture copies the fixture into a temporary directory along with a global config and a terraform stub (to
+// satisfy the builder's validations), returning the environment directory
+func setupFixture(t *testing.T, tmp string) string {
+	copyTree(t, filepath.Join("testdata", "base"), filepath.Join(tmp, "base"))
+
+	home := filepath.Join(tmp, "home")
+	err := os.MkdirAll(filepath.Join(home, ".laforge"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	global := "user \"golden\" {\n  name  = \"Golden Tester\"\n  email = \"golden@rehearsal.local\"\n}\n"
+	err = ioutil.WriteFile(filepath.Join(home, ".laforge", "global.laforge"), []byte(global), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("HOME", home)
+
+	bin := filepath.Join(tmp, "bin")
+	err = os.MkdirAll(bin, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(bin, "terraform"), []byte("#!/bin/sh\nexit 0\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return filepath.Join(tmp, "base", "envs", "rehearsal")
+}
+
+// TestGoldenBuild renders the fixture environment through the BuildEngine waterfall and compares the output with
+// the golden files. Run with -update to regenerate them.
+func TestGoldenBuild(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the terraform stub is a shell script")
+	}
+	cli.SetLogOutput(ioutil.Discard)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	goldenDir := filepath.Join(wd, "testdata", "golden")
+
+	tmp, err := ioutil.TempDir("", "tflibvirt-golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+
+	envDir := setupFixture(t, tmp)
+	err = os.Chdir(envDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	base, err := core.Bootstrap()
+	if err != nil {
+		t.Fatalf("could not bootstrap the fixture: %v", err)
+	}
+	state := core.NewState()
+	state.Base = base
+	base.StateManager = state
+
+	bldr, err := builder.New(base, true, false)
+	if err != nil {
+		t.Fatalf("could not create the build engine: %v", err)
+	}
+	err = bldr.Do()
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	buildDir := filepath.Join(envDir, "tflibvirt")
+	for _, name := range goldenFiles {
+		data, err := ioutil.ReadFile(filepath.Join(buildDir, name))
+		if err != nil {
+			t.Errorf("%s was not rendered: %v", name, err)
+			continue
+		}
+		for _, v := range volatile {
+			data = v.re.ReplaceAll(data, []byte(v.repl))
+		}
+		if strings.HasSuffix(name, ".tf") {
+			checkUniqueBlocks(t, name, data)
+		}
+
+		golden := filepath.Join(goldenDir, filepath.FromSlash(name))
+		if *update {
+			err = os.MkdirAll(filepath.Dir(golden), 0755)
+			if err == nil {
+				err = ioutil.WriteFile(golden, data, 0644)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		expected, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Errorf("could not read the golden file for %s: %v", name, err)
+			continue
+		}
+		if string(expected) != string(data) {
+			t.Errorf("%s does not match it's golden file (run go test -update to regenerate it)\n--- expected\n%s\n--- rendered\n%s", name, expected, data)
+		}
+	}
+}
+
+// tfBlockRegexp matches the header of a labelled terraform block, such as resource "type" "name" {
+var tfBlockRegexp = regexp.MustCompile(`(?m)^\s*(resource|data|output|variable|module)((?:\s+"[^"]*")+)\s*\{`)
+
+// checkUniqueBlocks fails the test if a rendered terraform file declares the same block twice, which terraform rejects
+func checkUniqueBlocks(t *testing.T, name string, data []byte) {
+	seen := map[string]bool{}
+	for _, m := range tfBlockRegexp.FindAllSubmatch(data, -1) {
+		key := string(m[1]) + " " + strings.Join(strings.Fields(string(m[2])), " ")
+		if seen[key] {
+			t.Errorf("%s declares %s more than once", name, key)
+		}
+		seen[key] = true
+	}
+}
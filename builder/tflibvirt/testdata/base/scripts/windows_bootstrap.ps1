@@ -0,0 +1 @@
+Write-Output "bootstrapping {{ .Host.Hostname }}"
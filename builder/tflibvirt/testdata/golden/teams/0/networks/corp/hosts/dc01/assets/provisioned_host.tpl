@@ -0,0 +1,18 @@
+connection "rehearsal/tflibvirt/teams/0/networks/corp/hosts/dc01/conn" {
+  active         = "${host_active}"
+  remote_addr    = "${remote_addr}"
+  local_addr     = "${local_addr}"
+  resource_name  = "${resource_name}"
+
+
+  
+  winrm {
+    remote_addr = "${remote_addr}"
+    port = 5985
+    https = false
+    skip_verify = false
+    user = "Administrator"
+    password = "${password}"
+  }
+  
+}
\ No newline at end of file
@@ -0,0 +1,16 @@
+connection "rehearsal/tflibvirt/teams/0/networks/corp/hosts/web01/conn" {
+  active         = "${host_active}"
+  remote_addr    = "${remote_addr}"
+  local_addr     = "${local_addr}"
+  resource_name  = "${resource_name}"
+
+
+  
+  ssh {
+    remote_addr = "${remote_addr}"
+    port = 22
+    user = "root"
+    identity_file = "${identity_file}"
+  }
+  
+}
\ No newline at end of file
@@ -0,0 +1,638 @@
+// Package vagrant implements a Laforge Builder module for generating a Vagrantfile per team. It allows developers
+// to bring up a slice of an environment, such as a single network, on their own workstation while building challenges.
+package vagrant
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gen0cide/laforge/builder/vagrant/static"
+	"github.com/gen0cide/laforge/core/cli"
+
+	"github.com/gen0cide/laforge/builder/buildutil/templates"
+	validations "github.com/gen0cide/laforge/builder/buildutil/valdations"
+
+	"github.com/pkg/errors"
+
+	"github.com/gen0cide/laforge/agent"
+	"github.com/gen0cide/laforge/builder/buildutil"
+	"github.com/gen0cide/laforge/core"
+)
+
+// Definition of builder meta-data.
+const (
+	ID          = `vagrant`
+	Name        = `Vagrant Builder`
+	Description = `generates a Vagrantfile per team so that environments can be brought up on a local workstation`
+	Author      = `Alex Levinson <github.com/gen0cide>`
+	Version     = `0.0.1`
+)
+
+var (
+	rules = validations.Validations{
+		validations.Requirement{
+			Name:       "Environment maintainer not defined",
+			Resolution: "add a maintainer block to your environment configuration",
+			Check:      validations.FieldNotEmpty(core.Environment{}, "Maintainer"),
+		},
+		validations.Requirement{
+			Name:       "vagrant executable not located in path",
+			Resolution: "download and ensure that vagrant is installed to a valid location in your PATH",
+			Check:      validations.ExistsInPath("vagrant"),
+		},
+		validations.Requirement{
+			Name:       "no teams specified",
+			Resolution: "make sure to set your team_count inside your environment config block to at least 1.",
+			Check:      validations.FieldNotEmpty(core.Environment{}, "team_count"),
+		},
+		validations.Requirement{
+			Name:       "No networks have been included",
+			Resolution: "Use the included_network \"$network_id\" { ... } block inside of your environment config to include networks.",
+			Check:      validations.FieldNotEmpty(core.Environment{}, "IncludedNetworks"),
+		},
+		validations.Requirement{
+			Name:       "No hosts were included",
+			Resolution: "Check your included_network blocks. The field included_hosts = [ ... ] should be populated with host IDs.",
+			Check:      validations.FieldNotEmpty(core.Environment{}, "IncludedHosts"),
+		},
+		validations.Requirement{
+			Name:       "No CIDR defined for network",
+			Resolution: "Check that network declarations have a cidr = ... defined in them.",
+			Check:      validations.FieldNotEmpty(core.Network{}, "CIDR"),
+		},
+		validations.Requirement{
+			Name:       "No OS defined for a host",
+			Resolution: "Check that all host declarations have an os = ... attribute defined.",
+			Check:      validations.FieldNotEmpty(core.Host{}, "OS"),
+		},
+		validations.Requirement{
+			Name:       "No hostname defined for a host",
+			Resolution: "Check that all host declarations have a hostname = ... attribute defined.",
+			Check:      validations.FieldNotEmpty(core.Host{}, "Hostname"),
+		},
+		validations.Requirement{
+			Name:       "No Instance Size defined for a host",
+			Resolution: "Check that all host declarations have an associated instance_size = ... attribute defined.",
+			Check:      validations.FieldNotEmpty(core.Host{}, "InstanceSize"),
+		},
+		validations.Requirement{
+			Name:       "Host last_octet collides with the host-only network gateway",
+			Resolution: "Vagrant assigns the first address of every private network to the workstation. Make sure every host uses a last_octet greater than 1.",
+			Check:      lastOctetsAvoidGateway,
+		},
+		validations.Requirement{
+			Name:       "No vagrant box available for a host",
+			Resolution: "Set a vagrant_box var on the host, reference an ami with provider = \"vagrant\", or define a vagrant_box_$os value inside your environment config = { ... } block.",
+			Check:      boxesResolvable,
+		},
+	}
+
+	templatesToLoad = []string{
+		"Vagrantfile.tmpl",
+	}
+
+	primaryTemplate = "Vagrantfile.tmpl"
+
+	// defaultBoxes maps well known host operating systems to public boxes on Vagrant Cloud.
+	defaultBoxes = map[string]string{
+		"ubuntu":  "ubuntu/bionic64",
+		"debian":  "debian/stretch64",
+		"centos":  "centos/7",
+		"fedora":  "generic/fedora30",
+		"kali":    "kalilinux/rolling",
+		"w2k16":   "gusztavvargadr/windows-server",
+		"windows": "gusztavvargadr/windows-server",
+	}
+
+	// stagingDir is where remote files are uploaded on linux guests before being moved into place,
+	// since the file provisioner runs as the unprivileged vagrant user.
+	stagingDir = "/tmp/laforge"
+)
+
+// VagrantBuilder implements a laforge builder that packages an environment into
+// one Vagrantfile per team, with every provisioned network represented as a
+// private network and every host's provisioning steps as ordered provisioners.
+type VagrantBuilder struct {
+	sync.RWMutex
+
+	// Required for the Builder interface
+	Base *core.Laforge
+
+	// A place to store the templates
+	Library *templates.Library
+
+	// Boxes maps included host IDs to the vagrant box they are brought up from
+	Boxes map[string]string
+
+	// AgentCA issues the TLS credentials of each host's agent
+	AgentCA *agent.CertificateAuthority
+}
+
+// Get retrieves an element from the embedded KV store
+func (t *VagrantBuilder) Get(key string) string {
+	t.Lock()
+	defer t.Unlock()
+	res, ok := t.Base.CurrentBuild.Config[key]
+	if ok {
+		return res
+	}
+	r0, e0 := t.Base.CurrentEnv.Config[key]
+	if e0 {
+		t.Base.CurrentBuild.Config[key] = r0
+		return r0
+	}
+	return ""
+}
+
+// Set assigns an element to the embedded KV store
+func (t *VagrantBuilder) Set(key string, val interface{}) {
+	t.Lock()
+	defer t.Unlock()
+	t.Base.CurrentBuild.Config[key] = fmt.Sprintf("%v", val)
+}
+
+// New creates an empty VagrantBuilder
+func New() *VagrantBuilder {
+	lib := templates.NewLibrary()
+	return &VagrantBuilder{
+		Library: lib,
+		Boxes:   map[string]string{},
+	}
+}
+
+// ID implements the Builder interface (returns the ID of the builder - usually the go package name)
+func (t *VagrantBuilder) ID() string {
+	return ID
+}
+
+// Name implements the Builder interface (returns the name of the builder - usually titleized version of the type)
+func (t *VagrantBuilder) Name() string {
+	return Name
+}
+
+// Description implements the Builder interface (returns the builder's description)
+func (t *VagrantBuilder) Description() string {
+	return Description
+}
+
+// Author implements the Builder interface (author's name and contact info)
+func (t *VagrantBuilder) Author() string {
+	return Author
+}
+
+// Version implements the Builder interface (builder version)
+func (t *VagrantBuilder) Version() string {
+	return Version
+}
+
+// Validations implements the Builder interface (builder checks)
+func (t *VagrantBuilder) Validations() validations.Validations {
+	return rules
+}
+
+// SetLaforge implements the Builder interface
+func (t *VagrantBuilder) SetLaforge(base *core.Laforge) error {
+	t.Base = base
+	if !base.ClearToBuild {
+		return buildutil.Throw(errors.New("context is not cleared to build"), "Laforge has encountered an error and cannot continue to build. This is likely a bug in LaForge.", nil)
+	}
+	for _, x := range templatesToLoad {
+		d, err := static.ReadFile(x)
+		if err != nil {
+			return buildutil.Throw(err, "could not read template", &buildutil.V{"template_name": x})
+		}
+		_, err = t.Library.AddBook(x, d)
+		if err != nil {
+			return buildutil.Throw(err, "could not parse template", &buildutil.V{"template_name": x})
+		}
+	}
+	return nil
+}
+
+// CheckRequirements implements the Builder interface
+func (t *VagrantBuilder) CheckRequirements() error {
+	if len(t.Base.CurrentBuild.Teams) > 1 {
+		cli.Logger.Warnf("Building %d Vagrantfiles. Every team reuses the same network CIDRs, so only one team should be brought up per workstation.", len(t.Base.CurrentBuild.Teams))
+	}
+	return nil
+}
+
+// PrepareAssets implements the Builder interface
+func (t *VagrantBuilder) PrepareAssets() error {
+	agentCA, err := agent.LoadOrCreateBuildCA(filepath.Join(t.Base.CurrentBuild.Dir, "data"))
+	if err != nil {
+		return buildutil.Throw(err, "Could not prepare the agent certificate authority in the build directory", nil)
+	}
+	t.AgentCA = agentCA
+
+	for hostid, host := range t.Base.CurrentEnv.IncludedHosts {
+		box, err := LookupBox(t.Base, host)
+		if err != nil {
+			return buildutil.Throw(err, "Validation for this passed, but the host's box could not be resolved. Likely a bug. Please report.", &buildutil.V{"host_id": hostid, "os": host.OS})
+		}
+		t.Boxes[hostid] = box
+	}
+	return nil
+}
+
+// GenerateScripts implements the Builder interface
+func (t *VagrantBuilder) GenerateScripts() error {
+	wg := new(sync.WaitGroup)
+	errChan := make(chan error, 1)
+	finChan := make(chan bool, 1)
+	user := t.Base.User
+	ctx, err := templates.NewContext(
+		t.Base,
+		t.Base.CurrentBuild,
+		t.Base.CurrentCompetition,
+		t.Base.CurrentCompetition.DNS,
+		t.Base.CurrentEnv,
+		user,
+	)
+	if err != nil {
+		return err
+	}
+	for _, teamObj := range t.Base.CurrentBuild.Teams {
+		for _, pnetObj := range teamObj.ProvisionedNetworks {
+			for _, phostObj := range pnetObj.ProvisionedHosts {
+				for _, stepObj := range phostObj.StepsByOffset {
+					script, ok := stepObj.Provisioner.(*core.Script)
+					if !ok || script.Source == "" {
+						continue
+					}
+					wg.Add(1)
+					go func(team *core.Team, pn *core.ProvisionedNetwork, ph *core.ProvisionedHost, ps *core.ProvisioningStep, scriptObj *core.Script) {
+						defer wg.Done()
+						scriptCtx := ctx.Clone()
+						err := scriptCtx.Attach(team, pn.Network, ph.Host, scriptObj, pn, ph, ps, ph.Conn)
+						if err != nil {
+							errChan <- err
+							return
+						}
+						assetPath := filepath.Join(team.RelBuildPath, "networks", pn.Network.Base(), "hosts", ph.Host.Base(), "assets", filepath.Base(scriptObj.Source))
+						fileData, err := t.Library.Execute(scriptObj.Path(), scriptCtx)
+						if err != nil {
+							errChan <- buildutil.Throw(err, "script template failed", &buildutil.V{
+								"script": scriptObj.Path(),
+								"host":   ph.Path(),
+							})
+							return
+						}
+						err = ioutil.WriteFile(assetPath, fileData, 0755)
+						if err != nil {
+							errChan <- err
+							return
+						}
+					}(teamObj, pnetObj, phostObj, stepObj, script)
+				}
+			}
+		}
+	}
+
+	go func() {
+		wg.Wait()
+		close(finChan)
+	}()
+
+	select {
+	case <-finChan:
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+// StageDependencies implements the Builder interface
+func (t *VagrantBuilder) StageDependencies() error {
+	if t.Base.StateManager == nil {
+		return errors.New("builder cannot stage dependencies with nil state manager")
+	}
+	build := t.Base.CurrentBuild
+	for _, team := range build.Teams {
+		teamDir := filepath.Join(build.Dir, "teams", fmt.Sprintf("%v", team.TeamNumber))
+		team.RelBuildPath = teamDir
+		os.MkdirAll(teamDir, 0755)
+		core.TouchGitKeep(teamDir)
+		teamCfg, err := core.RenderHCLv2Object(team)
+		if err != nil {
+			return err
+		}
+		teamCfgFile := filepath.Join(teamDir, "team.laforge")
+		err = ioutil.WriteFile(teamCfgFile, teamCfg, 0644)
+		if err != nil {
+			return err
+		}
+		for _, pn := range team.ProvisionedNetworks {
+			netdir := filepath.Join(teamDir, "networks", pn.Base())
+			os.MkdirAll(netdir, 0755)
+			core.TouchGitKeep(netdir)
+			data, err := core.RenderHCLv2Object(pn)
+			if err != nil {
+				return err
+			}
+			netfile := filepath.Join(netdir, "provisioned_network.laforge")
+			err = ioutil.WriteFile(netfile, data, 0644)
+			if err != nil {
+				return err
+			}
+			for _, ph := range pn.ProvisionedHosts {
+				hostdir := filepath.Join(netdir, "hosts", ph.Base())
+				agentdir := filepath.Join(hostdir, "agent")
+				assetdir := filepath.Join(hostdir, "assets")
+				stepdir := filepath.Join(hostdir, "steps")
+				os.MkdirAll(agentdir, 0755)
+				os.MkdirAll(assetdir, 0755)
+				os.MkdirAll(stepdir, 0755)
+				core.TouchGitKeep(agentdir)
+				core.TouchGitKeep(assetdir)
+				core.TouchGitKeep(stepdir)
+				phID := path.Join(team.Path(), "networks", pn.Network.Base(), "hosts", ph.Host.Base())
+				err = t.AgentCA.WriteAgentCredentials(agentdir, phID, ph.Host.Hostname)
+				if err != nil {
+					return err
+				}
+				data, err = core.RenderHCLv2Object(ph)
+				if err != nil {
+					return err
+				}
+				hostfile := filepath.Join(hostdir, "provisioned_host.laforge")
+				err = ioutil.WriteFile(hostfile, data, 0644)
+				if err != nil {
+					return err
+				}
+				for _, ps := range ph.ProvisioningSteps {
+					stepfile := filepath.Join(stepdir, fmt.Sprintf("%s.laforge", ps.Base()))
+					data, err = core.RenderHCLv2Object(ps)
+					if err != nil {
+						return err
+					}
+					err = ioutil.WriteFile(stepfile, data, 0644)
+					if err != nil {
+						return err
+					}
+					if rfile, ok := ps.Provisioner.(*core.RemoteFile); ok {
+						rfileName, err := rfile.AssetName()
+						if err != nil {
+							return err
+						}
+
+						dstPath := filepath.Join(assetdir, rfileName)
+						if _, err := os.Stat(dstPath); os.IsNotExist(err) {
+							copyErr := rfile.CopyTo(dstPath)
+							if copyErr != nil {
+								return copyErr
+							}
+						}
+					}
+					if script, ok := ps.Provisioner.(*core.Script); ok {
+						if _, ok := t.Library.Books[script.Path()]; ok {
+							continue
+						}
+						if script.Source == "" {
+							continue
+						}
+						for _, callfile := range script.Caller {
+							pr, ok := t.Base.PathRegistry.DB[callfile]
+							if !ok {
+								continue
+							}
+							lfr, ok := pr.Mapping[script.Source]
+							if !ok {
+								continue
+							}
+							data, err := ioutil.ReadFile(lfr.AbsPath)
+							if err != nil {
+								return err
+							}
+							_, err = t.Library.AddBook(script.Path(), data)
+							if err != nil {
+								return err
+							}
+							break
+						}
+					}
+				}
+			}
+		}
+
+	}
+	return nil
+}
+
+// Render implements the Builder interface
+func (t *VagrantBuilder) Render() error {
+	wg := new(sync.WaitGroup)
+	errChan := make(chan error, 1)
+	finChan := make(chan bool, 1)
+	for _, team := range t.Base.CurrentBuild.Teams {
+		wg.Add(1)
+		go func(team *core.Team) {
+			defer wg.Done()
+
+			teamDir := team.RelBuildPath
+			user := t.Base.User
+			ctx, err := templates.NewContext(
+				t.Base,
+				t.Base.CurrentBuild,
+				t.Base.CurrentCompetition,
+				t.Base.CurrentCompetition.DNS,
+				t.Base.CurrentEnv,
+				user,
+				team,
+			)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			err = t.populateContext(ctx, team)
+			if err != nil {
+				errChan <- buildutil.Throw(err, "could not prepare template context", &buildutil.V{
+					"team": team.Path(),
+					"dir":  teamDir,
+				})
+				return
+			}
+			cfgData, err := t.Library.ExecuteGroup(primaryTemplate, templatesToLoad, ctx)
+			if err != nil {
+				errChan <- buildutil.Throw(err, "template failed", &buildutil.V{
+					"team": team.Path(),
+					"dir":  teamDir,
+				})
+				return
+			}
+			err = ioutil.WriteFile(filepath.Join(teamDir, "Vagrantfile"), cfgData, 0644)
+			if err != nil {
+				errChan <- err
+				return
+			}
+		}(team)
+	}
+	go func() {
+		wg.Wait()
+		close(finChan)
+	}()
+
+	select {
+	case <-finChan:
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+// populateContext records the boxes, netmasks, and ruby literals for every provisioning
+// step used by the team's Vagrantfile into the context dictionary.
+func (t *VagrantBuilder) populateContext(ctx *templates.Context, team *core.Team) error {
+	ctx.Set("project", strings.ToLower(fmt.Sprintf("%s-t%d", t.Base.CurrentEnv.Base(), team.TeamNumber)))
+	for _, pn := range team.ProvisionedNetworks {
+		_, ipnet, err := net.ParseCIDR(pn.Network.CIDR)
+		if err != nil {
+			return errors.Wrapf(err, "network %s has an invalid cidr", pn.Network.Path())
+		}
+		ctx.Set(fmt.Sprintf("netmask_%s", pn.Network.Base()), net.IP(ipnet.Mask).String())
+
+		for _, ph := range pn.ProvisionedHosts {
+			host := ph.Host
+			box, ok := t.Boxes[host.Path()]
+			if !ok {
+				return errors.Errorf("no vagrant box was resolved for host %s", host.Path())
+			}
+			ctx.Set(fmt.Sprintf("box_%s", host.Base()), box)
+
+			for _, ps := range ph.StepsByOffset {
+				key := fmt.Sprintf("step_%s_%d", host.Base(), ps.StepNumber)
+				switch p := ps.Provisioner.(type) {
+				case *core.Script:
+					if p.Disabled || p.Source == "" {
+						ctx.Set(key+"_skip", "true")
+						continue
+					}
+					ctx.Set(key+"_source", filepath.Base(p.Source))
+					ctx.Set(key+"_args", RubyArray(p.Args))
+				case *core.Command:
+					if p.Disabled {
+						ctx.Set(key+"_skip", "true")
+						continue
+					}
+					ctx.Set(key+"_inline", CommandLine(p, host.IsWindows()))
+				case *core.RemoteFile:
+					if p.Disabled {
+						ctx.Set(key+"_skip", "true")
+						continue
+					}
+					asset, err := p.AssetName()
+					if err != nil {
+						return err
+					}
+					ctx.Set(key+"_source", asset)
+					if host.IsWindows() {
+						ctx.Set(key+"_destination", p.Destination)
+						continue
+					}
+					staged := fmt.Sprintf("%s/%s", stagingDir, asset)
+					ctx.Set(key+"_destination", staged)
+					dest := ShellQuote(p.Destination)
+					install := fmt.Sprintf("mkdir -p \"$(dirname %s)\" && mv -f %s %s", dest, ShellQuote(staged), dest)
+					if p.Perms != "" {
+						install = fmt.Sprintf("%s && chmod %s %s", install, ShellQuote(p.Perms), dest)
+					}
+					ctx.Set(key+"_inline", install)
+				default:
+					ctx.Set(key+"_skip", "true")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ShellQuote quotes a value as a single quoted POSIX shell word.
+func ShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// PowerShellQuote quotes a value as a single quoted PowerShell string, in which nothing is expanded.
+func PowerShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// CommandLine renders a command provisioner as an inline script for the host's shell, quoting the program and each
+// of it's arguments as a single word. Windows hosts run inline scripts with PowerShell, so the program is invoked
+// with the call operator.
+func CommandLine(c *core.Command, windows bool) string {
+	words := append([]string{c.Program}, c.Args...)
+	quote := ShellQuote
+	if windows {
+		quote = PowerShellQuote
+	}
+	for i, w := range words {
+		words[i] = quote(w)
+	}
+	if windows {
+		return "& " + strings.Join(words, " ")
+	}
+	return strings.Join(words, " ")
+}
+
+// RubyArray renders a list of values as a ruby array of string literals.
+func RubyArray(vals []string) string {
+	quoted := make([]string, len(vals))
+	for i, v := range vals {
+		quoted[i] = core.QuotedRubyString(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// LookupBox resolves the vagrant box a host is brought up from. A host can declare a vagrant_box var or reference
+// an ami with the vagrant provider, otherwise the environment's vagrant_box_$os config value is used, falling back
+// to a well known public box.
+func LookupBox(base *core.Laforge, host *core.Host) (string, error) {
+	if box := host.Vars["vagrant_box"]; box != "" {
+		return box, nil
+	}
+	if host.AMI != "" {
+		if ami, ok := base.AMIs[host.AMI]; ok {
+			if box := ami.Vars["vagrant_box"]; box != "" {
+				return box, nil
+			}
+			if ami.Provider == "vagrant" {
+				return ami.Name, nil
+			}
+		}
+	}
+	osName := strings.ToLower(host.OS)
+	key := fmt.Sprintf("vagrant_box_%s", osName)
+	if box := base.CurrentEnv.Config[key]; box != "" {
+		return box, nil
+	}
+	if box, ok := defaultBoxes[osName]; ok {
+		return box, nil
+	}
+	return "", errors.Errorf("host %s has no vagrant_box var and the environment does not define %s", host.ID, key)
+}
+
+func lastOctetsAvoidGateway(base *core.Laforge) bool {
+	for id, host := range base.CurrentEnv.IncludedHosts {
+		if host.LastOctet < 2 {
+			cli.Logger.Errorf("host %s has failed a validation: last_octet %d is reserved by the host-only network", id, host.LastOctet)
+			return false
+		}
+	}
+	return true
+}
+
+func boxesResolvable(base *core.Laforge) bool {
+	for id, host := range base.CurrentEnv.IncludedHosts {
+		if _, err := LookupBox(base, host); err != nil {
+			cli.Logger.Errorf("host %s has failed a validation: %v", id, err)
+			return false
+		}
+	}
+	return true
+}
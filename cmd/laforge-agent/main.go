@@ -68,6 +68,12 @@ func main() {
 			EnvVar:      "LAFORGE_AGENT_EXE_PATH",
 			Destination: &agent.ExePath,
 		},
+		cli.StringFlag{
+			Name:        "controller, c",
+			Usage:       "Runs in pull mode, registering with the laforge controller at the provided URL.",
+			EnvVar:      "LAFORGE_AGENT_CONTROLLER",
+			Destination: &agent.ControllerURL,
+		},
 	}
 	app.Version = laforge.Version
 	app.Authors = []cli.Author{
@@ -236,6 +242,14 @@ func runagent(c *cli.Context) error {
 
 func serveagent(c *cli.Context) error {
 	lfcli.Logger.Warnf("Serving In Foreground Laforge Agent...")
+	err := agent.StartLogger()
+	if err != nil {
+		return err
+	}
+	if agent.ControllerURL != "" {
+		agent.Agent.Pull()
+		return nil
+	}
 	agent.Agent.Serve()
 	return nil
 }
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gen0cide/laforge/agent"
+	"github.com/gen0cide/laforge/core"
+	lfcli "github.com/gen0cide/laforge/core/cli"
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli"
+)
+
+var (
+	agentAllTeams = false
+	agentCleanup  = ""
+	agentLanguage = ""
+	agentCommand  = cli.Command{
+		Name:      "agent",
+		Usage:     "talks to the agents of provisioned hosts over their TLS API",
+		UsageText: "laforge agent COMMAND HOST",
+		Description: "HOST may be a hostname, a host ID, or a glob matching provisioned host paths. Requests are\n" +
+			"   authenticated with the client certificate in the build's data directory.",
+		Subcommands: []cli.Command{
+			{
+				Name:      "status",
+				Usage:     "shows the status of the agents on the matching hosts",
+				UsageText: "laforge agent status HOST",
+				Action:    performagentstatus,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:        "all-teams, a",
+						Usage:       "Query the matching host of every team, not just the current team",
+						Destination: &agentAllTeams,
+					},
+				},
+			},
+			{
+				Name:      "destruct",
+				Usage:     "removes the agents from the matching hosts, verifying and saving their signed receipts",
+				UsageText: "laforge agent destruct [--cleanup FILE] HOST",
+				Description: "Receipts are verified against the build's agent CA and written to\n" +
+					"   <build>/data/receipts/ under the provisioned host's ID.",
+				Action: performagentdestruct,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:        "all-teams, a",
+						Usage:       "Destruct the agent of the matching host of every team, not just the current team",
+						Destination: &agentAllTeams,
+					},
+					cli.StringFlag{
+						Name:        "cleanup, c",
+						Usage:       "Script the agent runs before it removes itself",
+						Destination: &agentCleanup,
+					},
+					cli.StringFlag{
+						Name:        "language, l",
+						Usage:       "Language of the cleanup script (shell, powershell or cmd)",
+						Destination: &agentLanguage,
+					},
+				},
+			},
+		},
+	}
+)
+
+// agentTarget is an active connection to a provisioned host matched on the command line
+type agentTarget struct {
+	Conn    *core.Connection
+	Client  *agent.Client
+	DataDir string
+}
+
+// agentTargets returns a client for the agent of every provisioned host matching target with an active connection
+func agentTargets(target string) ([]*agentTarget, error) {
+	if target == "" {
+		return nil, errors.New("a HOST argument is required")
+	}
+	state, err := core.BootstrapWithState(true)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, errors.New("cannot proceed with a nil state")
+	}
+
+	baseConfig, err := core.LocateBaseConfig()
+	if err != nil {
+		return nil, err
+	}
+	baseDir := filepath.Dir(baseConfig)
+	datadir := filepath.Join(baseDir, "envs", state.Base.CurrentEnv.Base(), state.Base.CurrentBuild.Base(), "data")
+
+	currentTeam := ""
+	if state.Base.CurrentTeam != nil && !agentAllTeams {
+		currentTeam = state.Base.CurrentTeam.Path()
+	}
+
+	targets := []*agentTarget{}
+	for _, obj := range state.Current.Metastore {
+		if obj.ObjectType != core.LFTypeConnection {
+			continue
+		}
+		connObj, ok := obj.Dependency.(*core.Connection)
+		if !ok || connObj.ProvisionedHost == nil {
+			continue
+		}
+		if currentTeam != "" && connObj.Team != nil && connObj.Team.Path() != currentTeam {
+			continue
+		}
+		if !downloadHostMatches(target, connObj.ProvisionedHost) {
+			continue
+		}
+
+		conn := &core.Connection{}
+		err := core.LoadHCLFromFile(fmt.Sprintf("%s.laforge", filepath.Join(baseDir, connObj.Path())), conn)
+		if err != nil {
+			return nil, fmt.Errorf("could not load connection %s: %v", connObj.Path(), err)
+		}
+		if !conn.Active || conn.RemoteAddr == "" {
+			cliLogger.Warnf("Skipping %s: host is not active", connObj.ParentLaforgeID())
+			continue
+		}
+		client, err := agent.NewClient(datadir, conn.RemoteAddr, connObj.ProvisionedHost.Path())
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, &agentTarget{Conn: connObj, Client: client, DataDir: datadir})
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no active provisioned hosts matched %s", target)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Conn.Path() < targets[j].Conn.Path() })
+	return targets, nil
+}
+
+func performagentstatus(c *cli.Context) error {
+	targets, err := agentTargets(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Provisioned Host", "Agent", "Status", "Steps"})
+	for _, t := range targets {
+		status, err := t.Client.Status()
+		if err != nil {
+			table.Append([]string{t.Conn.ParentLaforgeID(), t.Client.URL, "ERROR", err.Error()})
+			continue
+		}
+		table.Append([]string{t.Conn.ParentLaforgeID(), t.Client.URL, string(status.Code), fmt.Sprintf("%d/%d", status.CompletedSteps, status.TotalSteps)})
+	}
+	table.Render()
+	return nil
+}
+
+func performagentdestruct(c *cli.Context) error {
+	req := &agent.SelfDestructRequest{Language: agentLanguage}
+	if agentCleanup != "" {
+		data, err := ioutil.ReadFile(agentCleanup)
+		if err != nil {
+			return err
+		}
+		req.CleanupScript = string(data)
+	}
+
+	targets, err := agentTargets(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	lfcli.SetLogLevel("info")
+	errored := false
+	for _, t := range targets {
+		id := t.Conn.ProvisionedHost.Path()
+		receipt, sr, err := t.Client.SelfDestruct(req)
+		if err == nil && receipt.ID != id {
+			err = fmt.Errorf("receipt was signed by the agent of %s", receipt.ID)
+		}
+		if sr != nil {
+			werr := writeReceipt(t.DataDir, t.Conn, sr)
+			if werr != nil {
+				cliLogger.Errorf("Could not save the receipt of %s: %v", id, werr)
+			}
+		}
+		if err != nil {
+			cliLogger.Errorf("Self destruct of %s failed: %v", id, err)
+			errored = true
+			continue
+		}
+		cliLogger.Infof("Agent removed from %s (%d paths removed, %d failures)", id, len(receipt.Removed), len(receipt.Failed))
+		for p, msg := range receipt.Failed {
+			cliLogger.Warnf("  %s: %s", p, msg)
+		}
+	}
+
+	if errored {
+		return errors.New("one or more agents could not be verified as removed")
+	}
+	return nil
+}
+
+// writeReceipt saves the signed receipt of a self destruct into the build's data directory
+func writeReceipt(datadir string, conn *core.Connection, sr *agent.SignedReceipt) error {
+	dir := filepath.Join(datadir, "receipts")
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(sr, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s.json", strings.Replace(strings.Trim(conn.ProvisionedHost.Path(), "/"), "/", "_", -1))
+	return ioutil.WriteFile(filepath.Join(dir, name), data, 0644)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/gen0cide/laforge/controller"
+	"github.com/gen0cide/laforge/core"
+	lfcli "github.com/gen0cide/laforge/core/cli"
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli"
+)
+
+var (
+	controllerListenAddr = controller.DefaultListenAddr
+	controllerApply      = false
+	controllerWait       = 60
+	controllerCommand    = cli.Command{
+		Name:      "controller",
+		Usage:     "starts a controller that pull mode agents register with and receive their provisioning steps from",
+		UsageText: "laforge controller [--apply]",
+		Description: "Agents started with \"laforge-agent --controller https://HOST:PORT\" dial out to the controller instead of\n" +
+			"   being provisioned over SSH or WinRM. With --apply, the infrastructure is applied once agents have had time to\n" +
+			"   connect, and steps on hosts with a connected agent are performed by the agent.",
+		Action: performcontroller,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:        "listen, l",
+				Usage:       "Address the controller listens on",
+				Value:       controller.DefaultListenAddr,
+				Destination: &controllerListenAddr,
+			},
+			cli.BoolFlag{
+				Name:        "apply, a",
+				Usage:       "Apply the infrastructure through the controller, then exit",
+				Destination: &controllerApply,
+			},
+			cli.IntFlag{
+				Name:        "wait, w",
+				Usage:       "Seconds to wait for agents to connect before applying",
+				Value:       60,
+				Destination: &controllerWait,
+			},
+		},
+	}
+)
+
+func performcontroller(c *cli.Context) error {
+	state, err := core.BootstrapWithState(true)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return errors.New("cannot proceed with a nil state")
+	}
+
+	ctl, err := controller.New(state.Base)
+	if err != nil {
+		return err
+	}
+
+	lfcli.SetLogLevel("info")
+	cliLogger.Infof("Controller for %d provisioned hosts listening on %s", len(ctl.Hosts), controllerListenAddr)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- ctl.ListenAndServeTLS(controllerListenAddr)
+	}()
+
+	if !controllerApply {
+		return <-errChan
+	}
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-time.After(time.Duration(controllerWait) * time.Second):
+	}
+
+	connected := ctl.Connected()
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Provisioned Host", "Hostname", "Remote Address", "Last Seen"})
+	for _, s := range connected {
+		table.Append([]string{s.ID, s.Hostname, s.RemoteAddr, s.LastSeen.Format(time.RFC3339)})
+	}
+	table.Render()
+	cliLogger.Infof("%d of %d agents connected, applying...", len(connected), len(ctl.Hosts))
+
+	return applyState(state, ctl)
+}
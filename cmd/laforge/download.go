@@ -1,18 +1,168 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/gen0cide/laforge/core"
+	lfcli "github.com/gen0cide/laforge/core/cli"
 	"github.com/urfave/cli"
 )
 
 var (
-	downloadCommand = cli.Command{
+	downloadRecursive = false
+	downloadUseSCP    = false
+	downloadAllTeams  = false
+	downloadCommand   = cli.Command{
 		Name:      "download",
 		Usage:     "downloads a file from a provisioned host",
 		UsageText: "laforge download HOST SOURCEFILE DESTFILE",
-		Action:    performdownload,
+		Description: "HOST may be a hostname, a host ID, or a glob matching provisioned host paths. SOURCEFILE may be a glob. " +
+			"When more than one host matches (or --all-teams is set) files are written into DESTFILE/<team>/<network>/<host>/.",
+		Action: performdownload,
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:        "recursive, r",
+				Usage:       "Download directories recursively",
+				Destination: &downloadRecursive,
+			},
+			cli.BoolFlag{
+				Name:        "scp",
+				Usage:       "Use SCP instead of SFTP for SSH hosts",
+				Destination: &downloadUseSCP,
+			},
+			cli.BoolFlag{
+				Name:        "all-teams, a",
+				Usage:       "Download from the matching host of every team, not just the current team",
+				Destination: &downloadAllTeams,
+			},
+		},
 	}
 )
 
 func performdownload(c *cli.Context) error {
-	return commandNotImplemented(c)
+	target := c.Args().Get(0)
+	src := c.Args().Get(1)
+	dst := c.Args().Get(2)
+	if target == "" || src == "" || dst == "" {
+		return errors.New("usage: laforge download HOST SOURCEFILE DESTFILE")
+	}
+
+	state, err := core.BootstrapWithState(true)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return errors.New("cannot proceed with a nil state")
+	}
+
+	baseConfig, err := core.LocateBaseConfig()
+	if err != nil {
+		return err
+	}
+	baseDir := filepath.Dir(baseConfig)
+
+	currentTeam := ""
+	if state.Base.CurrentTeam != nil && !downloadAllTeams {
+		currentTeam = state.Base.CurrentTeam.Path()
+	}
+
+	conns := []*core.Connection{}
+	for _, obj := range state.Current.Metastore {
+		if obj.ObjectType != core.LFTypeConnection {
+			continue
+		}
+		connObj, ok := obj.Dependency.(*core.Connection)
+		if !ok || connObj.ProvisionedHost == nil {
+			continue
+		}
+		if currentTeam != "" && connObj.Team != nil && connObj.Team.Path() != currentTeam {
+			continue
+		}
+		if downloadHostMatches(target, connObj.ProvisionedHost) {
+			conns = append(conns, connObj)
+		}
+	}
+
+	if len(conns) == 0 {
+		return fmt.Errorf("no provisioned hosts matched %s", target)
+	}
+	sort.Slice(conns, func(i, j int) bool { return conns[i].Path() < conns[j].Path() })
+
+	fanout := downloadAllTeams || len(conns) > 1
+	lfcli.SetLogLevel("info")
+
+	errored := false
+	mu := new(sync.Mutex)
+	wg := new(sync.WaitGroup)
+	for _, y := range conns {
+		wg.Add(1)
+		go func(x *core.Connection) {
+			defer wg.Done()
+			err := downloadFromHost(state, baseDir, x, src, dst, fanout)
+			if err != nil {
+				cliLogger.Errorf("Download from %s failed: %v", x.ParentLaforgeID(), err)
+				mu.Lock()
+				errored = true
+				mu.Unlock()
+			}
+		}(y)
+	}
+	wg.Wait()
+
+	if errored {
+		return errors.New("one or more downloads failed")
+	}
+	return nil
+}
+
+func downloadHostMatches(target string, ph *core.ProvisionedHost) bool {
+	if ph.Path() == target || ph.Base() == target || (ph.Host != nil && ph.Host.Hostname == target) {
+		return true
+	}
+	matched, err := path.Match(target, ph.Path())
+	return err == nil && matched
+}
+
+func downloadFromHost(state *core.State, baseDir string, x *core.Connection, src, dst string, fanout bool) error {
+	conn := &core.Connection{}
+	err := core.LoadHCLFromFile(fmt.Sprintf("%s.laforge", filepath.Join(baseDir, x.Path())), conn)
+	if err != nil {
+		return fmt.Errorf("could not load connection %s: %v", x.Path(), err)
+	}
+
+	if !conn.Active {
+		return fmt.Errorf("host %s is not active", x.ParentLaforgeID())
+	}
+
+	newConn, err := core.SmartMerge(x, conn, false)
+	if err != nil {
+		return fmt.Errorf("could not merge connections for %s: %v", x.ParentLaforgeID(), err)
+	}
+	connObj := newConn.(*core.Connection)
+
+	if connObj.IsSSH() {
+		connObj.SSHAuthConfig.IdentityFile = filepath.Join(baseDir, "envs", state.Base.CurrentEnv.Base(), state.Base.CurrentBuild.Base(), "data", "ssh.pem")
+	}
+
+	if fanout {
+		// a host in several networks has a provisioned host in each, so the network keeps their downloads apart
+		dst = filepath.Join(dst, strconv.Itoa(x.Team.TeamNumber), x.ProvisionedHost.ProvisionedNetwork.Base(), x.ProvisionedHost.Base())
+		err = os.MkdirAll(dst, 0755)
+		if err != nil {
+			return err
+		}
+	}
+
+	cliLogger.Infof("Downloading %s:%s -> %s", x.ParentLaforgeID(), src, dst)
+	if downloadUseSCP && connObj.IsSSH() {
+		return connObj.DownloadSCP(src, dst, downloadRecursive)
+	}
+	return connObj.Download(src, dst, downloadRecursive)
 }
@@ -31,7 +31,7 @@ func performdump(c *cli.Context) error {
 	}
 
 	if c.Args().Get(0) == "" {
-		pp.Println(base)
+		dumpPrintln(base)
 		return nil
 	}
 
@@ -39,171 +39,187 @@ func performdump(c *cli.Context) error {
 	case "build":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.Builds)
+			dumpPrintln(base.Builds)
 			os.Exit(0)
 		}
 		rec, found := base.Builds[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "build")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	case "competition":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.Competitions)
+			dumpPrintln(base.Competitions)
 			os.Exit(0)
 		}
 		rec, found := base.Competitions[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "competition")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	case "environment":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.Environments)
+			dumpPrintln(base.Environments)
 			os.Exit(0)
 		}
 		rec, found := base.Environments[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "environment")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	case "dns_record":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.DNSRecords)
+			dumpPrintln(base.DNSRecords)
 			os.Exit(0)
 		}
 		rec, found := base.DNSRecords[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "dns_record")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
+	case "active_directory":
+		param := c.Args().Get(1)
+		if len(param) == 0 {
+			dumpPrintln(base.ActiveDirectories)
+			os.Exit(0)
+		}
+		rec, found := base.ActiveDirectories[param]
+		if !found {
+			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "active_directory")
+		}
+		dumpPrintln(rec)
 	case "command":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.Commands)
+			dumpPrintln(base.Commands)
 			os.Exit(0)
 		}
 		rec, found := base.DNSRecords[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "command")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	case "host":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.Hosts)
+			dumpPrintln(base.Hosts)
 			os.Exit(0)
 		}
 		rec, found := base.Hosts[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "host")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	case "identity":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.Identities)
+			dumpPrintln(base.Identities)
 			os.Exit(0)
 		}
 		rec, found := base.Identities[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "identity")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	case "network":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.Networks)
+			dumpPrintln(base.Networks)
 			os.Exit(0)
 		}
 		rec, found := base.Networks[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "network")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	case "remote_file":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.RemoteFiles)
+			dumpPrintln(base.RemoteFiles)
 			os.Exit(0)
 		}
 		rec, found := base.RemoteFiles[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "remote_file")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	case "script":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.Scripts)
+			dumpPrintln(base.Scripts)
 			os.Exit(0)
 		}
 		rec, found := base.Scripts[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "script")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	case "team":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.Teams)
+			dumpPrintln(base.Teams)
 			os.Exit(0)
 		}
 		rec, found := base.Teams[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "team")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	case "provisioned_host":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.ProvisionedHosts)
+			dumpPrintln(base.ProvisionedHosts)
 			os.Exit(0)
 		}
 		rec, found := base.ProvisionedHosts[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "provisioned_host")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	case "provisioned_network":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.ProvisionedNetworks)
+			dumpPrintln(base.ProvisionedNetworks)
 			os.Exit(0)
 		}
 		rec, found := base.ProvisionedNetworks[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "provisioned_network")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	case "connection":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.Connections)
+			dumpPrintln(base.Connections)
 			os.Exit(0)
 		}
 		rec, found := base.Connections[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "connection")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	case "provisioning_step":
 		param := c.Args().Get(1)
 		if len(param) == 0 {
-			pp.Println(base.ProvisioningSteps)
+			dumpPrintln(base.ProvisioningSteps)
 			os.Exit(0)
 		}
 		rec, found := base.ProvisioningSteps[param]
 		if !found {
 			return fmt.Errorf("object with id %s and type %s could not be found in tree", param, "provisioning_step")
 		}
-		pp.Println(rec)
+		dumpPrintln(rec)
 	default:
 		return errors.New("argument is not a known datatype")
 	}
 
 	return nil
 }
+
+// dumpPrintln pretty prints an object with any secrets redacted
+func dumpPrintln(v interface{}) {
+	fmt.Print(core.Redact(pp.Sprintln(v)))
+}
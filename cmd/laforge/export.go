@@ -1,13 +1,27 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gen0cide/laforge/core"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli"
 )
 
 var (
-	exportAsJSON  = false
-	exportAsCSV   = false
-	exportCommand = cli.Command{
+	exportAsJSON    = false
+	exportAsCSV     = false
+	exportAsHosts   = false
+	exportAsAnsible = false
+	exportReveal    = false
+	exportCommand   = cli.Command{
 		Name:      "export",
 		Usage:     "Allows for various data to be exported from different contexts.",
 		UsageText: "laforge export",
@@ -22,26 +36,197 @@ var (
 				Usage:       "Attempt to export the information in CSV format.",
 				Destination: &exportAsCSV,
 			},
+			cli.BoolFlag{
+				Name:        "hosts",
+				Usage:       "Attempt to export the information as an /etc/hosts file.",
+				Destination: &exportAsHosts,
+			},
+			cli.BoolFlag{
+				Name:        "ansible",
+				Usage:       "Attempt to export the information as an Ansible inventory.",
+				Destination: &exportAsAnsible,
+			},
+			cli.BoolFlag{
+				Name:        "reveal-secrets",
+				Usage:       "Include passwords and other secrets in the output instead of redacting them (needed for a usable Ansible inventory).",
+				Destination: &exportReveal,
+			},
 		},
 		Subcommands: []cli.Command{
 			{
 				Name:   "findings",
-				Usage:  "Show all findings included in the current environment.",
-				Action: exportEnvFindings,
+				Usage:  "Show all findings included in the current environment, along with per host, team, and category score totals (markdown by default).",
+				Action: redactedExport(exportEnvFindings),
 			},
 			{
 				Name:   "netinfo",
-				Usage:  "Export all network information for provisioned hosts in the current environment.",
-				Action: exportEnvNetInfo,
+				Usage:  "Export all network information for provisioned hosts in the current environment, read from each team's terraform state.",
+				Action: redactedExport(exportEnvNetInfo),
+			},
+			{
+				Name:      "credentials",
+				Usage:     "Export the credential sheet of every identity and provisioned host password, for all teams or a single team number (markdown by default, never redacted).",
+				UsageText: "laforge export credentials [TEAM_NUMBER]",
+				Action:    exportEnvCredentials,
 			},
 		},
 	}
 )
 
-func exportEnvFindings(c *cli.Context) error {
-	return commandNotImplemented(c)
+// redactedExport buffers the output of an export so that any secrets can be redacted before it is written to stdout,
+// unless --reveal-secrets was set
+func redactedExport(export func(c *cli.Context, w io.Writer) error) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		if exportReveal {
+			return export(c, os.Stdout)
+		}
+		buf := new(bytes.Buffer)
+		err := export(c, buf)
+		fmt.Print(core.Redact(buf.String()))
+		return err
+	}
+}
+
+func exportEnvFindings(c *cli.Context, w io.Writer) error {
+	base, err := core.Bootstrap()
+	if err != nil {
+		if _, ok := err.(hcl.Diagnostics); ok {
+			return errors.New("aborted due to parsing error")
+		}
+		return err
+	}
+
+	err = base.AssertMinContext(core.BuildContext)
+	if err != nil {
+		cliLogger.Errorf("Must be in a build context to export findings: %v", err)
+		return errors.New("cannot proceed")
+	}
+
+	_, err = core.NewSnapshotFromEnv(base.CurrentEnv, false)
+	if err != nil {
+		return err
+	}
+
+	report := core.NewFindingsReport(base.CurrentEnv.Build.Teams)
+	switch {
+	case exportAsJSON:
+		data, err := report.ToJSON()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	case exportAsCSV:
+		return report.WriteCSV(w)
+	default:
+		return report.WriteMarkdown(w)
+	}
+}
+
+func exportEnvNetInfo(c *cli.Context, w io.Writer) error {
+	base, err := core.Bootstrap()
+	if err != nil {
+		if _, ok := err.(hcl.Diagnostics); ok {
+			return errors.New("aborted due to parsing error")
+		}
+		return err
+	}
+
+	err = base.AssertMinContext(core.BuildContext)
+	if err != nil {
+		cliLogger.Errorf("Must be in a build context to export network information: %v", err)
+		return errors.New("cannot proceed")
+	}
+
+	_, err = core.NewSnapshotFromEnv(base.CurrentEnv, false)
+	if err != nil {
+		return err
+	}
+
+	states := map[string]*core.RemoteState{}
+	for _, team := range base.CurrentEnv.Build.Teams {
+		rs, err := core.GetRemoteState(team, filepath.Join(base.BaseDir, team.Path()))
+		if err != nil {
+			cliLogger.Warnf("Could not read terraform state for team %d (falling back to subnet IPs): %v", team.TeamNumber, err)
+			continue
+		}
+		states[team.Path()] = rs
+	}
+
+	report := core.NewNetInfoReport(base.CurrentEnv.Build.Teams, states)
+	switch {
+	case exportAsJSON:
+		data, err := report.ToJSON()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	case exportAsCSV:
+		return report.WriteCSV(w)
+	case exportAsHosts:
+		return report.WriteEtcHosts(w)
+	case exportAsAnsible:
+		keyfile := filepath.Join(base.BaseDir, base.CurrentEnv.Build.Path(), "data", "ssh.pem")
+		return report.WriteAnsibleInventory(w, keyfile)
+	default:
+		table := tablewriter.NewWriter(w)
+		table.SetHeader([]string{"Team", "Network", "Provisioned Host", "Hostname", "FQDN", "OS", "Private IP", "Public IP", "TCP Ports", "UDP Ports"})
+		for _, x := range report.Hosts {
+			table.Append(x.TableInfo())
+		}
+		table.Render()
+		return nil
+	}
 }
 
-func exportEnvNetInfo(c *cli.Context) error {
-	return commandNotImplemented(c)
+func exportEnvCredentials(c *cli.Context) error {
+	base, err := core.Bootstrap()
+	if err != nil {
+		if _, ok := err.(hcl.Diagnostics); ok {
+			return errors.New("aborted due to parsing error")
+		}
+		return err
+	}
+
+	err = base.AssertMinContext(core.BuildContext)
+	if err != nil {
+		cliLogger.Errorf("Must be in a build context to export credentials: %v", err)
+		return errors.New("cannot proceed")
+	}
+
+	_, err = core.NewSnapshotFromEnv(base.CurrentEnv, false)
+	if err != nil {
+		return err
+	}
+
+	teams := base.CurrentEnv.Build.Teams
+	if c.NArg() > 0 {
+		num, err := strconv.Atoi(c.Args().First())
+		if err != nil {
+			return fmt.Errorf("invalid team number %s", c.Args().First())
+		}
+		teams = map[string]*core.Team{}
+		for id, team := range base.CurrentEnv.Build.Teams {
+			if team.TeamNumber == num {
+				teams[id] = team
+			}
+		}
+		if len(teams) == 0 {
+			return fmt.Errorf("team %d does not exist in this build", num)
+		}
+	}
+
+	sheets, err := core.CredentialSheets(teams, base.Identities)
+	if err != nil {
+		return err
+	}
+	switch {
+	case exportAsJSON:
+		return core.WriteCredentialSheetsJSON(os.Stdout, sheets)
+	case exportAsCSV:
+		return core.WriteCredentialSheetsCSV(os.Stdout, sheets)
+	default:
+		return core.WriteCredentialSheetsMarkdown(os.Stdout, sheets)
+	}
 }
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gen0cide/laforge/core"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/urfave/cli"
+)
+
+var (
+	identitiesSeed      = ""
+	identitiesCount     = 25
+	identitiesDomain    = ""
+	identitiesOutput    = ""
+	identitiesFormat    = ""
+	identitiesOverwrite = false
+	identitiesCommand   = cli.Command{
+		Name:      "identities",
+		Usage:     "generate and import identities, writing them to .laforge files which can be included like any other configuration",
+		UsageText: "laforge identities",
+		Subcommands: []cli.Command{
+			{
+				Name:      "generate",
+				Usage:     "generate realistic identities (names, emails in the competition's root domain, departments and titles) from a seed",
+				UsageText: "laforge identities generate --seed SEED [--count N] [--output FILE]",
+				Action:    performidentitiesgenerate,
+				Flags: append([]cli.Flag{
+					cli.StringFlag{
+						Name:        "seed, s",
+						Usage:       "seed which determines the generated identities (the same seed always generates the same people)",
+						Destination: &identitiesSeed,
+					},
+					cli.IntFlag{
+						Name:        "count, n",
+						Usage:       "number of identities to generate",
+						Value:       25,
+						Destination: &identitiesCount,
+					},
+				}, identitiesOutputFlags...),
+			},
+			{
+				Name:      "import",
+				Usage:     "import identities from a CSV (with a header row) or LDIF export",
+				UsageText: "laforge identities import [--format csv|ldif] [--output FILE] FILE",
+				Action:    performidentitiesimport,
+				Flags: append([]cli.Flag{
+					cli.StringFlag{
+						Name:        "format, F",
+						Usage:       "format of the imported file, csv or ldif (detected from the file extension by default)",
+						Destination: &identitiesFormat,
+					},
+				}, identitiesOutputFlags...),
+			},
+		},
+	}
+
+	identitiesOutputFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:        "domain",
+			Usage:       "email domain of the identities (defaults to the competition's dns root_domain)",
+			Destination: &identitiesDomain,
+		},
+		cli.StringFlag{
+			Name:        "output, o",
+			Usage:       "file to write the identities to, or - for stdout (defaults to a file in the base's identities directory)",
+			Destination: &identitiesOutput,
+		},
+		cli.BoolFlag{
+			Name:        "force, f",
+			Usage:       "overwrite the output file if it already exists",
+			Destination: &identitiesOverwrite,
+		},
+	}
+)
+
+func performidentitiesgenerate(c *cli.Context) error {
+	if identitiesSeed == "" {
+		return errors.New("a seed must be provided with --seed")
+	}
+	if identitiesCount < 1 {
+		return errors.New("count must be at least 1")
+	}
+	return writeidentities("generated", func(ii *core.IdentityImporter) ([]*core.Identity, error) {
+		return ii.Generate(identitiesCount)
+	})
+}
+
+func performidentitiesimport(c *cli.Context) error {
+	filename := c.Args().First()
+	if filename == "" {
+		return errors.New("a file to import must be provided")
+	}
+	format := strings.ToLower(identitiesFormat)
+	if format == "" {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+	}
+	if format != "csv" && format != "ldif" {
+		return fmt.Errorf("unsupported import format %q (must be csv or ldif)", format)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return writeidentities(name, func(ii *core.IdentityImporter) ([]*core.Identity, error) {
+		if format == "ldif" {
+			return ii.ImportLDIF(f)
+		}
+		return ii.ImportCSV(f)
+	})
+}
+
+// writeidentities creates identities with the provided function, avoiding collisions with the base's existing
+// identities, and writes them to the output file
+func writeidentities(name string, create func(ii *core.IdentityImporter) ([]*core.Identity, error)) error {
+	base, err := core.Bootstrap()
+	if err != nil {
+		if _, ok := err.(hcl.Diagnostics); ok {
+			return errors.New("aborted due to parsing error")
+		}
+		return err
+	}
+
+	output := identitiesOutput
+	if output == "" {
+		output = filepath.Join(base.BaseRoot, "identities", fmt.Sprintf("%s.laforge", name))
+	}
+	if output != "-" && core.PathExists(output) && !identitiesOverwrite {
+		return fmt.Errorf("%s already exists (use --force to overwrite it)", output)
+	}
+
+	domain := identitiesDomain
+	if domain == "" {
+		domain = competitionDomain(base)
+	}
+
+	ii := core.NewIdentityImporter(domain, identitiesSeed)
+	for _, i := range base.Identities {
+		if output != "-" && i.DefinedIn(output) {
+			continue
+		}
+		ii.Reserve(i)
+	}
+	ids, err := create(ii)
+	if err != nil {
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+	err = core.WriteIdentitiesHCL(buf, ids)
+	if err != nil {
+		return err
+	}
+	if output == "-" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(output), 0755)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(output, buf.Bytes(), 0644)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d identities to %s\n", len(ids), output)
+	if rel, err := filepath.Rel(base.BaseRoot, output); err == nil && !strings.HasPrefix(rel, "..") {
+		fmt.Printf("Include them from your base.laforge if it does not already:\n\ninclude {\n  path = %q\n}\n", rel)
+	}
+	return nil
+}
+
+// competitionDomain returns the root domain of the current competition, or of the only competition in the base
+func competitionDomain(base *core.Laforge) string {
+	comp := base.CurrentCompetition
+	if comp == nil && len(base.Competitions) == 1 {
+		for _, x := range base.Competitions {
+			comp = x
+		}
+	}
+	if comp == nil || comp.DNS == nil {
+		return ""
+	}
+	return comp.DNS.RootDomain
+}
@@ -9,20 +9,27 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gen0cide/laforge/builder/buildutil/templates"
 	"github.com/masterzen/winrm"
 
 	"github.com/emicklei/dot"
 	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
 
 	"github.com/gen0cide/laforge/core"
 	lfcli "github.com/gen0cide/laforge/core/cli"
+	"github.com/gen0cide/laforge/explorer"
+	"github.com/hashicorp/terraform/tfdiags"
 	"github.com/urfave/cli"
 )
 
 var (
 	shouldgraph  = false
+	applyResume  = false
+	applyDash    = false
+	applyEvents  = cli.StringSlice{}
 	infraCommand = cli.Command{
 		Name:      "infra",
 		Usage:     "Manage infrastructure deployment that has been generated with Laforge.",
@@ -47,14 +54,30 @@ var (
 				SkipFlagParsing: true,
 			},
 			{
-				Name:            "apply",
-				Usage:           "Provision the infrastructure to bring state in line with build blueprint.",
-				Action:          performapply,
-				SkipFlagParsing: true,
+				Name:   "apply",
+				Usage:  "Provision the infrastructure to bring state in line with build blueprint.",
+				Action: performapply,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:        "resume",
+						Usage:       "resume an interrupted apply, skipping the tasks it completed",
+						Destination: &applyResume,
+					},
+					cli.BoolFlag{
+						Name:        "dashboard",
+						Usage:       "show the progress of the apply on a live dashboard of every team and host",
+						Destination: &applyDash,
+					},
+					cli.StringSliceFlag{
+						Name:  "events",
+						Usage: "stream JSON events of the apply to a file, unix:///path/to/socket or webhook URL (repeatable)",
+						Value: &applyEvents,
+					},
+				},
 			},
 			{
 				Name:            "taint",
-				Usage:           "Mark a host for re-provisioning in the laforge infrastructure state.",
+				Usage:           "Mark a host for re-provisioning in the laforge infrastructure state (- reads IDs from stdin).",
 				Action:          performtaint,
 				SkipFlagParsing: true,
 			},
@@ -72,7 +95,7 @@ var (
 			},
 			{
 				Name:            "exec",
-				Usage:           "Run commands on a wildcard matched subset of hosts (usually for debugging).",
+				Usage:           "Run commands on a wildcard matched subset of hosts (- reads IDs from stdin, usually for debugging).",
 				Action:          performinfraexec,
 				SkipFlagParsing: true,
 			},
@@ -95,7 +118,15 @@ func performtaint(c *cli.Context) error {
 		return errors.New("cannot proceed with a nil state")
 	}
 
-	for _, x := range c.Args() {
+	targets := []string(c.Args())
+	if len(targets) == 1 && targets[0] == "-" {
+		targets, err = readPathList(os.Stdin)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, x := range targets {
 		obj, exists := state.Current.Metastore[x]
 		if !exists {
 			cliLogger.Warnf("Node %s did not exist in the persisted snapshot.")
@@ -169,8 +200,15 @@ func performplan(c *cli.Context) error {
 		return err
 	}
 
+	limits, err := core.LimitsFromConfig(state.Base.CurrentEnv.Config)
+	if err != nil {
+		return err
+	}
+
 	lfcli.SetLogLevel("info")
 
+	cliLogger.Infof("Concurrency Limits: %s", limits)
+
 	for tid, cmds := range tfcmds {
 		cliLogger.Infof("Terraform Commands For Team: %s", tid)
 		for _, c := range cmds {
@@ -201,6 +239,10 @@ func performplan(c *cli.Context) error {
 			default:
 				tcol = "[UNKNOWN]"
 			}
+			if core.IsNetworkHeavy(plan.Graph.Metastore[item]) {
+				fmt.Printf("%s  %d) %s %s\n", tcol, idx, item, color.HiYellowString("(network heavy)"))
+				continue
+			}
 			fmt.Printf("%s  %d) %s\n", tcol, idx, item)
 		}
 		depthoffset++
@@ -293,12 +335,41 @@ func performapply(c *cli.Context) error {
 		return errors.New("cannot proceed with a nil state")
 	}
 
+	return applyState(state, nil)
+}
+
+// applyState calculates and executes the plan of a state, performing steps through executor (if provided) on the
+// hosts it handles.
+func applyState(state *core.State, executor core.Executor) error {
 	plan, err := state.CalculateDelta()
 	if err != nil {
 		return err
 	}
 
 	plan.Base = state.Base
+	plan.Executor = executor
+
+	limits, err := core.LimitsFromConfig(state.Base.CurrentEnv.Config)
+	if err != nil {
+		return err
+	}
+	plan.Limiter = core.NewLimiter(limits)
+
+	journal, err := core.LoadJournal(state.DB)
+	if err != nil && err != core.ErrNoJournal {
+		return err
+	}
+	if applyResume {
+		if journal == nil || journal.Run.Finished() {
+			return errors.New("there is no interrupted apply to resume")
+		}
+		if journal.Run.Checksum != state.Current.Hash() {
+			return errors.New("the configuration has changed since the interrupted apply, run apply without --resume")
+		}
+		printJournal(journal)
+	} else if journal != nil && !journal.Run.Finished() {
+		cliLogger.Warnf("The previous apply (started %s) did not finish, use --resume to continue it instead", journal.Run.StartedAt.Local().Format(time.RFC1123))
+	}
 
 	err = plan.Preflight()
 	if err != nil {
@@ -310,10 +381,43 @@ func performapply(c *cli.Context) error {
 		return err
 	}
 
-	diags := plan.Execute()
+	if applyResume {
+		skipped := plan.Resume(journal)
+		cliLogger.Infof("Resuming apply, skipping %d completed tasks", skipped)
+		err = journal.Resume()
+	} else {
+		plan.Journal, err = core.NewJournal(state.DB, plan, state.Current.Hash())
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(applyEvents) > 0 {
+		plan.Events, err = core.NewEventStream(applyEvents)
+		if err != nil {
+			return err
+		}
+		defer plan.Events.Close()
+	}
+
+	var diags tfdiags.Diagnostics
+	if applyDash {
+		diags = explorer.RunApplyDashboard(plan)
+	} else {
+		diags = plan.Execute()
+	}
 	if diags.HasErrors() {
 		return diags.Err()
 	}
+	if plan.FailedNodes.Len() > 0 {
+		// the journal is left unfinished so the apply can be resumed
+		return fmt.Errorf("%d tasks failed, run laforge infra apply --resume to retry them", plan.FailedNodes.Len())
+	}
+
+	err = plan.Journal.Finish()
+	if err != nil {
+		return err
+	}
 
 	defer state.DB.Close()
 
@@ -325,6 +429,35 @@ func performapply(c *cli.Context) error {
 	return nil
 }
 
+// printJournal reports where the apply of a journal stopped
+func printJournal(journal *core.Journal) {
+	done := journal.EntriesByStatus(core.JobStatusSuccessful)
+	inflight := journal.EntriesByStatus(core.JobStatusInProgress)
+	failed := journal.EntriesByStatus(core.JobStatusFailed)
+	pending := journal.EntriesByStatus(core.JobStatusEnqueued)
+
+	cliLogger.Infof("Apply started %s: %d completed, %d in progress, %d failed, %d not started",
+		journal.Run.StartedAt.Local().Format(time.RFC1123), len(done), len(inflight), len(failed), len(pending))
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Task", "Status", "Since", "Error"})
+	for _, group := range [][]*core.JournalEntry{inflight, failed} {
+		for _, entry := range group {
+			last := entry.Last()
+			since, msg := "", ""
+			if last != nil {
+				since = last.Time.Local().Format(time.RFC1123)
+				msg = last.Error
+			}
+			table.Append([]string{entry.ID, entry.Status.String(), since, msg})
+		}
+	}
+	if len(pending) > 0 {
+		table.Append([]string{pending[0].ID, "NEXT", "", ""})
+	}
+	table.Render()
+}
+
 func performtf(c *cli.Context) error {
 	return commandNotImplemented(c)
 }
@@ -385,6 +518,15 @@ func performinfraexec(c *cli.Context) error {
 		return errors.New("cannot proceed without a path matcher")
 	}
 
+	// a matcher of - reads a list of IDs (such as laforge query --list output) from stdin
+	matchers := []string{match}
+	if match == "-" {
+		matchers, err = readPathList(os.Stdin)
+		if err != nil {
+			return err
+		}
+	}
+
 	plan := core.NewEmptyPlan()
 	plan.Graph = state.Current
 	plan.Base = state.Base
@@ -419,8 +561,15 @@ func performinfraexec(c *cli.Context) error {
 
 		parentID := connObj.ParentLaforgeID()
 
-		if matched, err := path.Match(match, parentID); err == nil && matched {
-			conns = append(conns, connObj)
+		for _, m := range matchers {
+			if m == connObj.Path() {
+				conns = append(conns, connObj)
+				break
+			}
+			if matched, err := path.Match(m, parentID); err == nil && matched {
+				conns = append(conns, connObj)
+				break
+			}
 		}
 	}
 
@@ -62,6 +62,7 @@ func main() {
 	app.Copyright = `(c) 2018 Alex Levinson`
 	app.Commands = []cli.Command{
 		configureCommand,
+		controllerCommand,
 		initCommand,
 		statusCommand,
 		dumpCommand,
@@ -78,6 +79,9 @@ func main() {
 		infraCommand,
 		fmtCommand,
 		graphCommand,
+		secretCommand,
+		identitiesCommand,
+		agentCommand,
 	}
 
 	app.Before = func(c *cli.Context) error {
@@ -1,22 +1,60 @@
 package main
 
 import (
+	"bufio"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/gen0cide/laforge/core"
+	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli"
 )
 
 var (
+	queryAsJSON  = false
+	queryAsList  = false
 	queryCommand = cli.Command{
 		Name:      "query",
 		Usage:     "gathers information about elements within the configuration state",
 		UsageText: "laforge query QUERY",
-		Action:    performquery,
+		Description: "QUERY is a whitespace separated list of terms which must all match (prefix a term with ! to negate it):\n\n" +
+			"   type=host,script          object is one of the listed types\n" +
+			"   path=GLOB (or just GLOB)  object ID matches the glob (globs without a / match the last element of the ID)\n" +
+			"   host=NAME                 object belongs to the host with the provided ID base or hostname\n" +
+			"   tag.KEY[=VALUE]           object (or it's host) has the tag\n" +
+			"   var.KEY[=VALUE]           object (or it's host) has the var\n" +
+			"   changed                   object is new or has changed since the last persisted snapshot\n" +
+			"   downstream=GLOB           object is downstream of an object matching GLOB on the graph\n" +
+			"   upstream=GLOB             object is upstream of an object matching GLOB on the graph\n" +
+			"   downstream-changed=GLOB   object is downstream of a changed object matching GLOB\n" +
+			"   upstream-changed=GLOB     object is upstream of a changed object matching GLOB\n\n" +
+			"The --list output can be piped into \"laforge infra taint -\" and \"laforge infra exec - COMMAND\".",
+		Action: performquery,
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:        "json, j",
+				Usage:       "Output the results as JSON",
+				Destination: &queryAsJSON,
+			},
+			cli.BoolFlag{
+				Name:        "list, l",
+				Usage:       "Output only the IDs of the results, one per line",
+				Destination: &queryAsList,
+			},
+		},
 	}
 )
 
 func performquery(c *cli.Context) error {
+	query, err := core.ParseQuery(strings.Join(c.Args(), " "))
+	if err != nil {
+		return err
+	}
+
 	state, err := core.BootstrapWithState(true)
 	if err != nil {
 		return err
@@ -24,22 +62,46 @@ func performquery(c *cli.Context) error {
 	if state == nil {
 		return errors.New("cannot proceed with a nil state")
 	}
+	defer state.DB.Close()
 
-	plan, err := state.CalculateDelta()
-	if err != nil {
-		return err
-	}
-	tfcmds, err := core.CalculateTerraformNeeds(plan)
+	results, err := query.Execute(state.Current, state.Persisted)
 	if err != nil {
 		return err
 	}
 
-	_ = tfcmds
-
-	snap := state.Current
-	defer state.DB.Close()
-
-	_ = snap
+	switch {
+	case queryAsJSON:
+		data, err := core.QueryResultsToJSON(results)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case queryAsList:
+		for _, x := range results {
+			fmt.Println(x.ID)
+		}
+	default:
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Type", "Changed", "Checksum"})
+		for _, x := range results {
+			table.Append([]string{x.ID, string(x.ObjectType), strconv.FormatBool(x.Changed), strconv.FormatUint(x.Checksum, 10)})
+		}
+		table.Render()
+	}
 
 	return nil
 }
+
+// readPathList reads a newline delimited list of object IDs (such as the output of laforge query --list)
+func readPathList(r io.Reader) ([]string, error) {
+	paths := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	return paths, scanner.Err()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/gen0cide/laforge/core"
+	"github.com/urfave/cli"
+)
+
+var (
+	secretoverwrite = false
+	secretCommand   = cli.Command{
+		Name:      "secret",
+		Usage:     "manage the keyring used to encrypt secrets in laforge configuration files",
+		UsageText: "laforge secret",
+		Subcommands: []cli.Command{
+			{
+				Name:   "keygen",
+				Usage:  "generate the key used to encrypt keyring secrets (stored in the global config directory)",
+				Action: performsecretkeygen,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:        "force, f",
+						Usage:       "replaces an existing key (every value encrypted with it becomes unreadable)",
+						Destination: &secretoverwrite,
+					},
+				},
+			},
+			{
+				Name:      "encrypt",
+				Usage:     "encrypt a value with the keyring, printing the secret reference to use in place of it (- reads from stdin)",
+				UsageText: "laforge secret encrypt VALUE",
+				Action:    performsecretencrypt,
+			},
+		},
+	}
+)
+
+func performsecretkeygen(c *cli.Context) error {
+	keyfile, err := core.CreateKeyring(secretoverwrite)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Secrets key written to %s\n", keyfile)
+	return nil
+}
+
+func performsecretencrypt(c *cli.Context) error {
+	val := c.Args().First()
+	if val == "" {
+		return errors.New("a value to encrypt must be provided")
+	}
+	if val == "-" {
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		val = strings.TrimRight(string(data), "\r\n")
+	}
+	key, err := core.LoadKeyring()
+	if err != nil {
+		return err
+	}
+	ref, err := core.EncryptSecret(key, val)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ref)
+	return nil
+}
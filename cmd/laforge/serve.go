@@ -1,18 +1,108 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gen0cide/laforge/core"
+	lfcli "github.com/gen0cide/laforge/core/cli"
+	"github.com/gen0cide/laforge/fileserver"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli"
 )
 
 var (
-	serveCommand = cli.Command{
+	serveListenAddr = fileserver.DefaultListenAddr
+	serveFetchLog   = ""
+	serveTokens     = false
+	serveCommand    = cli.Command{
 		Name:      "serve",
 		Usage:     "starts an HTTP server that can be used to serve files for local provisioning",
 		UsageText: "laforge serve",
 		Action:    performserve,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:        "listen, l",
+				Usage:       "Address the server listens on",
+				Value:       fileserver.DefaultListenAddr,
+				Destination: &serveListenAddr,
+			},
+			cli.StringFlag{
+				Name:        "fetch-log",
+				Usage:       "File every fetch is recorded to as a JSON line (defaults to data/fetches.log in the build)",
+				Destination: &serveFetchLog,
+			},
+			cli.BoolFlag{
+				Name:        "tokens, t",
+				Usage:       "Print the download URL and token of every provisioned host and exit",
+				Destination: &serveTokens,
+			},
+		},
 	}
 )
 
 func performserve(c *cli.Context) error {
-	return commandNotImplemented(c)
+	base, err := core.Bootstrap()
+	if err != nil {
+		if _, ok := err.(hcl.Diagnostics); ok {
+			return errors.New("aborted due to parsing error")
+		}
+		return err
+	}
+
+	err = base.AssertMinContext(core.BuildContext)
+	if err != nil {
+		cliLogger.Errorf("Must be in a build context to serve provisioning files: %v", err)
+		return errors.New("cannot proceed")
+	}
+
+	_, err = core.NewSnapshotFromEnv(base.CurrentEnv, false)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := fileserver.LoadTokenSet(base)
+	if err != nil {
+		return err
+	}
+
+	server, err := fileserver.NewServer(base, tokens)
+	if err != nil {
+		return err
+	}
+
+	if serveTokens {
+		keys := []string{}
+		for k := range server.Hosts {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Provisioned Host", "Manifest URL", "Token"})
+		for _, k := range keys {
+			table.Append([]string{server.Hosts[k].Path(), fmt.Sprintf("/provision/%s", k), tokens.Token(server.Hosts[k])})
+		}
+		table.Render()
+		return nil
+	}
+
+	if serveFetchLog == "" {
+		serveFetchLog = filepath.Join(base.BaseDir, base.CurrentEnv.Build.Path(), "data", "fetches.log")
+	}
+	//nolint:gosec
+	logfile, err := os.OpenFile(serveFetchLog, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck
+	defer logfile.Close()
+	server.FetchLog = logfile
+
+	lfcli.SetLogLevel("info")
+	cliLogger.Infof("Serving %d provisioned hosts on %s (fetch log: %s)", len(server.Hosts), serveListenAddr, serveFetchLog)
+	return server.ListenAndServe(serveListenAddr)
 }
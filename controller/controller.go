@@ -0,0 +1,560 @@
+// Package controller implements the server side of pull mode provisioning. Agents on hosts which can not be
+// reached over SSH or WinRM dial out to a controller, register, and long-poll it for new revisions of their state.
+// The controller implements core.Executor so a plan can perform provisioning steps through connected agents.
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gen0cide/laforge/agent"
+	"github.com/gen0cide/laforge/core"
+	"github.com/gen0cide/laforge/core/cli"
+	"github.com/gen0cide/laforge/fileserver"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultListenAddr is the address the controller listens on by default
+	DefaultListenAddr = `0.0.0.0:9972`
+
+	// DefaultPollTimeout is how long a poll is held open before the agent is told there is nothing new
+	DefaultPollTimeout = 30 * time.Second
+)
+
+// Controller tracks the agents of a build that have connected in pull mode and the steps dispatched to them
+type Controller struct {
+	sync.Mutex
+
+	// Base is the laforge base of the build being controlled
+	Base *core.Laforge
+
+	// CA is the build's agent certificate authority
+	CA *agent.CertificateAuthority
+
+	// Hosts indexes the provisioned hosts of the build by their ID
+	Hosts map[string]*core.ProvisionedHost
+
+	// Sessions indexes the registered agents by provisioned host ID
+	Sessions map[string]*Session
+
+	// PollTimeout is how long agent polls are held open
+	PollTimeout time.Duration
+}
+
+// Session is a registered agent
+type Session struct {
+	sync.Mutex
+	ID              string                `json:"id"`
+	Hostname        string                `json:"hostname"`
+	OS              string                `json:"os"`
+	RemoteAddr      string                `json:"remote_addr"`
+	RegisteredAt    time.Time             `json:"registered_at"`
+	LastSeen        time.Time             `json:"last_seen"`
+	Revision        int64                 `json:"revision"`
+	State           *agent.State          `json:"-"`
+	Reported        map[int]*agent.Step   `json:"-"`
+	ProvisionedHost *core.ProvisionedHost `json:"-"`
+	updated         chan struct{}
+	reported        chan struct{}
+}
+
+// New creates a controller for the base's current build. The build's teams must already have been created (see
+// core.NewSnapshotFromEnv).
+func New(base *core.Laforge) (*Controller, error) {
+	if base.CurrentEnv == nil || base.CurrentEnv.Build == nil {
+		return nil, errors.New("laforge base does not have a current build")
+	}
+	ca, err := agent.LoadOrCreateBuildCA(filepath.Join(base.BaseDir, base.CurrentEnv.Build.Path(), "data"))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load the build's agent certificate authority")
+	}
+	c := &Controller{
+		Base:        base,
+		CA:          ca,
+		Hosts:       map[string]*core.ProvisionedHost{},
+		Sessions:    map[string]*Session{},
+		PollTimeout: DefaultPollTimeout,
+	}
+	for _, team := range base.CurrentEnv.Build.Teams {
+		for _, pn := range team.ProvisionedNetworks {
+			for _, ph := range pn.ProvisionedHosts {
+				c.Hosts[ph.Path()] = ph
+			}
+		}
+	}
+	if len(c.Hosts) == 0 {
+		return nil, errors.New("the current build does not contain any provisioned hosts")
+	}
+	return c, nil
+}
+
+// Handler returns the HTTP handler of the controller
+func (c *Controller) Handler() http.Handler {
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	agents := r.Group("/agent", c.requireAgent)
+	agents.POST("/register", c.ReqRegister)
+	agents.GET("/poll", c.ReqPoll)
+	agents.POST("/steps", c.ReqReportSteps)
+	agents.POST("/logs/:step/:stream", c.ReqUploadLog)
+	agents.GET("/assets/:step", c.ReqGetAsset)
+
+	r.GET("/api/agents", c.requireOperator, c.ReqGetAgents)
+
+	return r
+}
+
+// ListenAndServeTLS serves the controller on addr until an error occurs
+func (c *Controller) ListenAndServeTLS(addr string) error {
+	tlsConfig, err := c.CA.ControllerTLSConfig()
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Addr:      addr,
+		Handler:   c.Handler(),
+		TLSConfig: tlsConfig,
+	}
+	return srv.ListenAndServeTLS("", "")
+}
+
+// Session returns the session of the agent of a provisioned host (if it has registered)
+func (c *Controller) Session(id string) *Session {
+	c.Lock()
+	defer c.Unlock()
+	return c.Sessions[id]
+}
+
+// Connected returns copies of the sessions of agents which have been seen recently, ordered by ID
+func (c *Controller) Connected() []*Session {
+	c.Lock()
+	ret := []*Session{}
+	for _, s := range c.Sessions {
+		if c.alive(s) {
+			ret = append(ret, s.Copy())
+		}
+	}
+	c.Unlock()
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID < ret[j].ID })
+	return ret
+}
+
+// Copy returns a copy of the session's exported fields, which can be read without holding the session's lock
+func (s *Session) Copy() *Session {
+	s.Lock()
+	defer s.Unlock()
+	return &Session{
+		ID:           s.ID,
+		Hostname:     s.Hostname,
+		OS:           s.OS,
+		RemoteAddr:   s.RemoteAddr,
+		RegisteredAt: s.RegisteredAt,
+		LastSeen:     s.LastSeen,
+		Revision:     s.Revision,
+	}
+}
+
+// alive returns true if an agent has polled within the last two poll timeouts
+func (c *Controller) alive(s *Session) bool {
+	s.Lock()
+	defer s.Unlock()
+	return time.Since(s.LastSeen) < 2*c.PollTimeout
+}
+
+// Handles implements the core.Executor interface
+func (c *Controller) Handles(ph *core.ProvisionedHost) bool {
+	s := c.Session(ph.Path())
+	return s != nil && c.alive(s)
+}
+
+// Execute implements the core.Executor interface. The step is added to the agent's state and the call blocks until
+// the agent reports it as finished or errored, or stops polling without having rebooted the host for the step.
+func (c *Controller) Execute(job core.Doer, pstep *core.ProvisioningStep, e chan error) {
+	s := c.Session(pstep.ProvisionedHost.Path())
+	if s == nil {
+		e <- errors.Errorf("no agent is registered for %s", pstep.ProvisionedHost.Path())
+		return
+	}
+
+	step, err := NewAgentStep(pstep, job.GetMetadata())
+	if err != nil {
+		e <- err
+		return
+	}
+
+	s.Lock()
+	s.dispatch(step)
+	revision := s.Revision
+	s.Unlock()
+	cli.Logger.Infof("Dispatched step %d of %s to it's agent (revision %d)", step.ID, s.ID, revision)
+
+	ticker := time.NewTicker(c.PollTimeout)
+	defer ticker.Stop()
+	rebooting := false
+	for {
+		s.Lock()
+		report := s.Reported[step.ID]
+		reported := s.reported
+		s.Unlock()
+
+		if report != nil && report.Revision == step.Revision {
+			switch report.Status {
+			case "finished":
+				e <- nil
+				return
+			case "awaiting_reboot":
+				if !rebooting {
+					cli.Logger.Infof("Agent of %s is rebooting the host for step %d", s.ID, step.ID)
+					rebooting = true
+				}
+			case "errored":
+				if pstep.Script != nil && pstep.Script.IgnoreErrors {
+					cli.Logger.Warnf("Ignoring failure of step %d on %s: %s", step.ID, s.ID, report.ExitMessage)
+					e <- nil
+					return
+				}
+				e <- errors.Errorf("step %d failed on the agent of %s: %s", step.ID, s.ID, report.ExitMessage)
+				return
+			}
+		}
+
+		select {
+		case <-reported:
+		case <-ticker.C:
+			// an agent stops polling while it's host reboots, so only the task's timeout applies until it is back
+			if !rebooting && !c.alive(s) {
+				e <- errors.Errorf("the agent of %s stopped polling the controller", s.ID)
+				return
+			}
+		}
+	}
+}
+
+// dispatch adds or replaces a step in the session's state and wakes any waiting poll. The session must be locked.
+func (s *Session) dispatch(step *agent.Step) {
+	replaced := false
+	for i, x := range s.State.Steps {
+		if x.ID == step.ID {
+			s.State.Steps[i] = step
+			replaced = true
+		}
+	}
+	if !replaced {
+		s.State.Steps = append(s.State.Steps, step)
+		sort.Slice(s.State.Steps, func(i, j int) bool { return s.State.Steps[i].ID < s.State.Steps[j].ID })
+	}
+	delete(s.Reported, step.ID)
+
+	s.Revision++
+	if now := time.Now().UTC().Unix(); now > s.Revision {
+		s.Revision = now
+	}
+	s.State.Revision = s.Revision
+	close(s.updated)
+	s.updated = make(chan struct{})
+}
+
+// NewAgentStep converts a provisioning step into a step that can be performed by an agent. Every dispatch of a
+// step receives a unique revision so the agent performs it even if it has run an identical step before.
+func NewAgentStep(pstep *core.ProvisioningStep, meta *core.Metadata) (*agent.Step, error) {
+	var checksum uint64
+	if meta != nil {
+		checksum = meta.Checksum
+	}
+	step := &agent.Step{
+		ID:          pstep.StepNumber,
+		Revision:    fmt.Sprintf("%x-%d", checksum, time.Now().UnixNano()),
+		Name:        path.Base(pstep.ProvisionerID),
+		Description: pstep.Path(),
+		StepType:    pstep.ProvisionerType,
+		Metadata:    map[string]interface{}{},
+	}
+	asset := fmt.Sprintf("/agent/assets/%d", pstep.StepNumber)
+	switch p := pstep.Provisioner.(type) {
+	case *core.Script:
+		step.Source = path.Join("assets", fmt.Sprintf("%d-%x-%s", pstep.StepNumber, checksum, p.SourceBase()))
+		step.Metadata["url"] = asset
+		step.Metadata["language"] = p.Language
+		step.SetRebootPolicy(p.Reboot, p.RebootExitCode)
+	case *core.RemoteFile:
+		step.Source = path.Join("assets", fmt.Sprintf("%d-%x-%s", pstep.StepNumber, checksum, filepath.Base(p.Source)))
+		step.Destination = p.Destination
+		step.Metadata["url"] = asset
+	case *core.Command:
+		step.Metadata["command"] = p.CommandString()
+		step.SetRebootPolicy(p.Reboot, p.RebootExitCode)
+	default:
+		return nil, errors.Errorf("%s steps can not be performed by an agent", pstep.ProvisionerType)
+	}
+	return step, nil
+}
+
+// requireAgent authenticates an agent by the provisioned host ID in it's certificate
+func (c *Controller) requireAgent(ctx *gin.Context) {
+	id := agent.AgentIdentity(ctx.Request)
+	ph, ok := c.Hosts[id]
+	if !ok {
+		cli.Logger.Warnf("Rejected unauthorized request for %s from %s", ctx.Request.URL.Path, ctx.ClientIP())
+		ctx.AbortWithStatusJSON(401, map[string]string{
+			"status":  "error",
+			"message": "unauthorized",
+		})
+		return
+	}
+	ctx.Set("provisioned_host", ph)
+	ctx.Next()
+}
+
+// requireOperator authenticates the operator by the build's client certificate
+func (c *Controller) requireOperator(ctx *gin.Context) {
+	if agent.ClientName(ctx.Request) != agent.ClientCommonName {
+		cli.Logger.Warnf("Rejected unauthorized request for %s from %s", ctx.Request.URL.Path, ctx.ClientIP())
+		ctx.AbortWithStatusJSON(401, map[string]string{
+			"status":  "error",
+			"message": "unauthorized",
+		})
+		return
+	}
+	ctx.Next()
+}
+
+// session returns the session of the authenticated agent, responding with a 404 if it has not registered
+func (c *Controller) session(ctx *gin.Context) (*Session, bool) {
+	s := c.Session(agent.AgentIdentity(ctx.Request))
+	if s == nil {
+		ctx.JSON(404, map[string]string{
+			"status":  "error",
+			"message": agent.ErrNotRegistered.Error(),
+		})
+		return nil, false
+	}
+	return s, true
+}
+
+// ReqRegister registers an agent, creating an empty state for it if this is the first time it has connected
+func (c *Controller) ReqRegister(ctx *gin.Context) {
+	ph := ctx.MustGet("provisioned_host").(*core.ProvisionedHost)
+	req := &agent.RegisterRequest{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		ctx.JSON(400, map[string]string{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Lock()
+	s, ok := c.Sessions[ph.Path()]
+	if !ok {
+		s = &Session{
+			ID:              ph.Path(),
+			Reported:        map[int]*agent.Step{},
+			ProvisionedHost: ph,
+			State: &agent.State{
+				Host:         ph.Host,
+				Network:      ph.Network,
+				RenderedAt:   time.Now().UTC(),
+				CurrentState: "pending",
+				Steps:        []*agent.Step{},
+			},
+			updated:  make(chan struct{}),
+			reported: make(chan struct{}),
+		}
+		c.Sessions[ph.Path()] = s
+	}
+	c.Unlock()
+
+	s.Lock()
+	s.Hostname = req.Hostname
+	s.OS = req.OS
+	s.RemoteAddr = ctx.ClientIP()
+	s.RegisteredAt = time.Now().UTC()
+	s.LastSeen = s.RegisteredAt
+	s.Unlock()
+
+	cli.Logger.Infof("Agent for %s registered from %s (%s)", ph.Path(), ctx.ClientIP(), req.Hostname)
+	ctx.JSON(200, &agent.RegisterResponse{
+		ID:          ph.Path(),
+		PollTimeout: int(c.PollTimeout / time.Second),
+	})
+}
+
+// ReqPoll returns the agent's state once it's revision is newer than the revision query parameter, or 204 once
+// the poll timeout has passed.
+func (c *Controller) ReqPoll(ctx *gin.Context) {
+	s, ok := c.session(ctx)
+	if !ok {
+		return
+	}
+	known, _ := strconv.ParseInt(ctx.Query("revision"), 10, 64)
+
+	timeout := time.NewTimer(c.PollTimeout)
+	defer timeout.Stop()
+	for {
+		s.Lock()
+		s.LastSeen = time.Now().UTC()
+		if s.Revision > known && len(s.State.Steps) > 0 {
+			// the response is encoded under the lock but written after it, so a slow agent does not hold up the
+			// dispatches and reports of the others
+			data, err := json.Marshal(&agent.PollResponse{
+				Revision: s.Revision,
+				State:    s.State,
+			})
+			s.Unlock()
+			if err != nil {
+				ctx.JSON(500, map[string]string{
+					"status":  "error",
+					"message": err.Error(),
+				})
+				return
+			}
+			ctx.Data(200, "application/json; charset=utf-8", data)
+			return
+		}
+		updated := s.updated
+		s.Unlock()
+
+		select {
+		case <-updated:
+		case <-timeout.C:
+			s.Lock()
+			s.LastSeen = time.Now().UTC()
+			s.Unlock()
+			ctx.Status(204)
+			return
+		case <-ctx.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// ReqReportSteps records the status of steps reported by an agent
+func (c *Controller) ReqReportSteps(ctx *gin.Context) {
+	s, ok := c.session(ctx)
+	if !ok {
+		return
+	}
+	steps := []*agent.Step{}
+	err := ctx.ShouldBindJSON(&steps)
+	if err != nil {
+		ctx.JSON(400, map[string]string{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	s.Lock()
+	for _, step := range steps {
+		s.Reported[step.ID] = step
+		cli.Logger.Debugf("Agent for %s reported step %d as %s", s.ID, step.ID, step.Status)
+	}
+	s.LastSeen = time.Now().UTC()
+	close(s.reported)
+	s.reported = make(chan struct{})
+	s.Unlock()
+
+	ctx.JSON(200, map[string]string{
+		"status": "ok",
+	})
+}
+
+// ReqUploadLog stores the stdout or stderr of a step in the provisioned host's log directory
+func (c *Controller) ReqUploadLog(ctx *gin.Context) {
+	s, ok := c.session(ctx)
+	if !ok {
+		return
+	}
+	stream := ctx.Param("stream")
+	num, err := strconv.Atoi(ctx.Param("step"))
+	if err != nil || (stream != "stdout" && stream != "stderr") {
+		ctx.JSON(400, map[string]string{
+			"status":  "error",
+			"message": "invalid log",
+		})
+		return
+	}
+
+	data, err := ioutil.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(400, map[string]string{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	logdir := filepath.Join(c.Base.BaseDir, s.ID, "logs")
+	//nolint:gosec,errcheck
+	os.MkdirAll(logdir, 0755)
+	logfile := filepath.Join(logdir, fmt.Sprintf("agent-%d.%s.log", num, stream))
+	err = ioutil.WriteFile(logfile, data, 0644)
+	if err != nil {
+		cli.Logger.Errorf("Could not write %s: %v", logfile, err)
+		ctx.JSON(500, map[string]string{
+			"status":  "error",
+			"message": "could not store log",
+		})
+		return
+	}
+
+	ctx.JSON(200, map[string]string{
+		"status": "ok",
+	})
+}
+
+// ReqGetAsset returns the content of a script or remote file step
+func (c *Controller) ReqGetAsset(ctx *gin.Context) {
+	ph := ctx.MustGet("provisioned_host").(*core.ProvisionedHost)
+	num, err := strconv.Atoi(ctx.Param("step"))
+	if err != nil || num < 0 || num >= len(ph.StepsByOffset) {
+		ctx.JSON(404, map[string]string{
+			"status":  "error",
+			"message": "no such step",
+		})
+		return
+	}
+
+	assetPath, err := fileserver.AssetPath(c.Base, ph, ph.StepsByOffset[num])
+	if err != nil {
+		ctx.JSON(404, map[string]string{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	if _, err := os.Stat(assetPath); err != nil {
+		cli.Logger.Errorf("Asset for step %d of %s is missing (has the build been rendered?): %v", num, ph.Path(), err)
+		ctx.JSON(500, map[string]string{
+			"status":  "error",
+			"message": "step asset is missing",
+		})
+		return
+	}
+	ctx.File(assetPath)
+}
+
+// ReqGetAgents returns the registered agents
+func (c *Controller) ReqGetAgents(ctx *gin.Context) {
+	c.Lock()
+	sessions := []*Session{}
+	for _, s := range c.Sessions {
+		sessions = append(sessions, s.Copy())
+	}
+	c.Unlock()
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].ID < sessions[j].ID })
+	ctx.JSON(200, sessions)
+}
@@ -0,0 +1,246 @@
+// +build !windows
+
+package controller
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gen0cide/laforge/agent"
+	"github.com/gen0cide/laforge/core"
+	"github.com/gen0cide/laforge/core/cli"
+)
+
+const testHostID = "/envs/test/builds/local/teams/0/networks/corp/hosts/web01"
+
+// setupLoopback starts a controller for a single provisioned host over TLS on the loopback interface, and a pull
+// client holding the credentials of that host's agent
+func setupLoopback(t *testing.T) (*Controller, *agent.PullClient, func()) {
+	tmp, err := ioutil.TempDir("", "laforge-controller")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli.SetLogOutput(ioutil.Discard)
+	gin.SetMode(gin.TestMode)
+
+	agent.AgentHomeDir = filepath.Join(tmp, "agent")
+	err = os.MkdirAll(agent.StepLogDir(), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agent.Logger = logrus.New()
+	agent.Logger.SetOutput(ioutil.Discard)
+	agent.AsyncWorker = &agent.Worker{}
+	agent.AsyncWorker.Spawn()
+
+	ca, err := agent.NewCertificateAuthority()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ca.WriteAgentCredentials(agent.AgentHomeDir, testHostID, "web01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ph := &core.ProvisionedHost{
+		ID:   testHostID,
+		Host: &core.Host{OS: "ubuntu"},
+	}
+	c := &Controller{
+		Base:        &core.Laforge{BaseDir: tmp},
+		CA:          ca,
+		Hosts:       map[string]*core.ProvisionedHost{testHostID: ph},
+		Sessions:    map[string]*Session{},
+		PollTimeout: time.Second,
+	}
+
+	srv := httptest.NewUnstartedServer(c.Handler())
+	srv.TLS, err = ca.ControllerTLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.StartTLS()
+
+	client, err := agent.NewPullClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, client, func() {
+		srv.Close()
+		os.RemoveAll(tmp)
+	}
+}
+
+// TestPullLoopback walks an agent through registering, long-polling for a step, performing it, and reporting it's
+// status and logs back to the controller
+func TestPullLoopback(t *testing.T) {
+	c, client, cleanup := setupLoopback(t)
+	defer cleanup()
+
+	_, err := client.Poll()
+	if err != agent.ErrNotRegistered {
+		t.Fatalf("expected polling before registering to fail with ErrNotRegistered, got %v", err)
+	}
+
+	err = client.Register()
+	if err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if client.ID != testHostID {
+		t.Fatalf("agent registered as %s", client.ID)
+	}
+	s := c.Session(testHostID)
+	if s == nil {
+		t.Fatal("controller has no session for the registered agent")
+	}
+	if len(c.Connected()) != 1 {
+		t.Fatal("registered agent is not connected")
+	}
+
+	resp, err := client.Poll()
+	if err != nil || resp != nil {
+		t.Fatalf("expected an empty poll to time out, got %v (%v)", resp, err)
+	}
+
+	polled := make(chan *agent.PollResponse, 1)
+	go func() {
+		resp, err := client.Poll()
+		if err != nil {
+			t.Error(err)
+		}
+		polled <- resp
+	}()
+	select {
+	case <-polled:
+		t.Fatal("poll returned before a step was dispatched")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	pstep := &core.ProvisioningStep{
+		ID:              testHostID + "/steps/1-hello",
+		StepNumber:      1,
+		ProvisionerID:   "/commands/hello",
+		ProvisionerType: "command",
+		Provisioner:     &core.Command{Program: "echo", Args: []string{"hello", "from", "the", "agent"}},
+		ProvisionedHost: c.Hosts[testHostID],
+	}
+	step, err := NewAgentStep(pstep, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Lock()
+	s.dispatch(step)
+	s.Unlock()
+
+	select {
+	case resp = <-polled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiting poll was not woken by the dispatched step")
+	}
+	if resp == nil || len(resp.State.Steps) != 1 {
+		t.Fatalf("expected the poll to return the dispatched step, got %v", resp)
+	}
+
+	err = client.Apply(resp)
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	client.Revision = resp.Revision
+	deadline := time.Now().Add(30 * time.Second)
+	for !agent.AsyncWorker.Available() {
+		if time.Now().After(deadline) {
+			t.Fatal("agent did not finish performing the step")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	err = client.Report()
+	if err != nil {
+		t.Fatalf("report failed: %v", err)
+	}
+	s.Lock()
+	report := s.Reported[step.ID]
+	s.Unlock()
+	if report == nil || report.Status != "finished" || report.Revision != step.Revision {
+		t.Fatalf("expected the step to be reported as finished, got %+v", report)
+	}
+
+	logfile := filepath.Join(c.Base.BaseDir, testHostID, "logs", "agent-1.stdout.log")
+	data, err := ioutil.ReadFile(logfile)
+	if err != nil {
+		t.Fatalf("stdout of the step was not uploaded: %v", err)
+	}
+	if !strings.Contains(string(data), "hello from the agent") {
+		t.Errorf("unexpected stdout of the step: %q", data)
+	}
+
+	resp, err = client.Poll()
+	if err != nil || resp != nil {
+		t.Errorf("expected no new revision after the step was applied, got %v (%v)", resp, err)
+	}
+}
+
+// TestPullRun drives an agent's pull loop, which registers, polls and reports from separate goroutines, until a
+// dispatched step is reported back as finished
+func TestPullRun(t *testing.T) {
+	c, client, cleanup := setupLoopback(t)
+	defer cleanup()
+	agent.PullReportInterval = 50 * time.Millisecond
+
+	stopped := make(chan struct{})
+	go func() {
+		client.Run(&agent.Engine{})
+		close(stopped)
+	}()
+	defer func() {
+		client.Stop()
+		<-stopped
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for c.Session(testHostID) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("agent did not register")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	s := c.Session(testHostID)
+
+	pstep := &core.ProvisioningStep{
+		ID:              testHostID + "/steps/1-hello",
+		StepNumber:      1,
+		ProvisionerID:   "/commands/hello",
+		ProvisionerType: "command",
+		Provisioner:     &core.Command{Program: "echo", Args: []string{"hello"}},
+		ProvisionedHost: c.Hosts[testHostID],
+	}
+	step, err := NewAgentStep(pstep, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Lock()
+	s.dispatch(step)
+	s.Unlock()
+
+	deadline = time.Now().Add(30 * time.Second)
+	for {
+		s.Lock()
+		report := s.Reported[step.ID]
+		s.Unlock()
+		if report != nil && report.Revision == step.Revision && report.Status == "finished" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("step was not reported as finished, last report %+v", report)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
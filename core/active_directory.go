@@ -0,0 +1,484 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/cespare/xxhash"
+	"github.com/pkg/errors"
+)
+
+const (
+	directoriesDir = `directories`
+
+	// DefaultActiveDirectoryOU is the organizational unit every OU, user and group is created under when an active
+	// directory does not set a base_ou
+	DefaultActiveDirectoryOU = `Laforge`
+
+	// IdentityVarOU is the identity var holding the slash separated OU path (within the base OU) an identity's user is
+	// created in. The identity's department is used when it is not set.
+	IdentityVarOU = `ou`
+
+	// IdentityVarGroups is the identity var holding the comma separated groups an identity's user is a member of
+	IdentityVarGroups = `groups`
+
+	// ADMinPasswordLength is the minimum password length of a domain's default password policy
+	ADMinPasswordLength = 7
+)
+
+// ActiveDirectory is a configurable type for a provisioning step which creates a selection of identities as users
+// within the Active Directory domain of a domain controller host
+type ActiveDirectory struct {
+	ID                        string            `hcl:"id,label" json:"id,omitempty"`
+	Description               string            `hcl:"description,optional" json:"description,omitempty"`
+	Domain                    string            `hcl:"domain,optional" json:"domain,omitempty"`
+	DomainController          string            `hcl:"domain_controller,attr" json:"domain_controller,omitempty"`
+	BaseOU                    string            `hcl:"base_ou,optional" json:"base_ou,omitempty"`
+	Identities                []string          `hcl:"identities,optional" json:"identities,omitempty"`
+	IdentityTags              map[string]string `hcl:"identity_tags,optional" json:"identity_tags,omitempty"`
+	IdentityVars              map[string]string `hcl:"identity_vars,optional" json:"identity_vars,omitempty"`
+	Groups                    []string          `hcl:"groups,optional" json:"groups,omitempty"`
+	DisablePasswordComplexity bool              `hcl:"disable_password_complexity,optional" json:"disable_password_complexity,omitempty"`
+	Timeout                   int               `hcl:"timeout,optional" json:"timeout,omitempty"`
+	Disabled                  bool              `hcl:"disabled,optional" json:"disabled,omitempty"`
+	Vars                      map[string]string `hcl:"vars,optional" json:"vars,omitempty"`
+	Tags                      map[string]string `hcl:"tags,optional" json:"tags,omitempty"`
+	OnConflict                *OnConflict       `hcl:"on_conflict,block" json:"on_conflict,omitempty"`
+	Members                   []*Identity       `json:"-"`
+	Caller                    Caller            `json:"-"`
+}
+
+// Hash implements the Hasher interface
+func (a *ActiveDirectory) Hash() uint64 {
+	members := ChecksumList{}
+	for _, x := range a.Members {
+		members = append(members, x.Hash())
+	}
+	return xxhash.Sum64String(
+		fmt.Sprintf(
+			"domain=%v dc=%v baseou=%v groups=%v complexity=%v disabled=%v vars=%v members=%v",
+			a.Domain,
+			a.DomainController,
+			a.BaseOU,
+			a.Groups,
+			a.DisablePasswordComplexity,
+			a.Disabled,
+			a.Vars,
+			members.Hash(),
+		),
+	)
+}
+
+// Path implements the Pather interface
+func (a *ActiveDirectory) Path() string {
+	return a.ID
+}
+
+// Base implements the Pather interface
+func (a *ActiveDirectory) Base() string {
+	return path.Base(a.ID)
+}
+
+// ValidatePath implements the Pather interface
+func (a *ActiveDirectory) ValidatePath() error {
+	if err := ValidateGenericPath(a.Path()); err != nil {
+		return err
+	}
+	if topdir := strings.Split(a.Path(), `/`); topdir[1] != directoriesDir {
+		return fmt.Errorf("path %s is not rooted in /%s", a.Path(), topdir[1])
+	}
+	return nil
+}
+
+// GetCaller implements the Mergeable interface
+func (a *ActiveDirectory) GetCaller() Caller {
+	return a.Caller
+}
+
+// LaforgeID implements the Mergeable interface
+func (a *ActiveDirectory) LaforgeID() string {
+	return a.ID
+}
+
+// ParentLaforgeID implements the Dependency interface
+func (a *ActiveDirectory) ParentLaforgeID() string {
+	return a.Path()
+}
+
+// Gather implements the Dependency interface
+func (a *ActiveDirectory) Gather(g *Snapshot) error {
+	return nil
+}
+
+// Fullpath implements the Pather interface
+func (a *ActiveDirectory) Fullpath() string {
+	return a.LaforgeID()
+}
+
+// GetOnConflict implements the Mergeable interface
+func (a *ActiveDirectory) GetOnConflict() OnConflict {
+	if a.OnConflict == nil {
+		return OnConflict{
+			Do: "default",
+		}
+	}
+	return *a.OnConflict
+}
+
+// SetCaller implements the Mergeable interface
+func (a *ActiveDirectory) SetCaller(c Caller) {
+	a.Caller = c
+}
+
+// SetOnConflict implements the Mergeable interface
+func (a *ActiveDirectory) SetOnConflict(o OnConflict) {
+	a.OnConflict = &o
+}
+
+// Kind implements the Provisioner interface
+func (a *ActiveDirectory) Kind() string {
+	return ObjectTypeActiveDirectory.String()
+}
+
+// Swap implements the Mergeable interface
+func (a *ActiveDirectory) Swap(m Mergeable) error {
+	rawVal, ok := m.(*ActiveDirectory)
+	if !ok {
+		return errors.Wrapf(ErrSwapTypeMismatch, "expected %T, got %T", a, m)
+	}
+	*a = *rawVal
+	return nil
+}
+
+// Index resolves the identities which are members of the directory. Identities listed by ID are always members, as are
+// any whose tags and vars match all of the directory's identity_tags and identity_vars. When none of these are set,
+// every identity is a member.
+func (a *ActiveDirectory) Index(base *Laforge) error {
+	a.Members = []*Identity{}
+	selected := map[string]bool{}
+	for _, id := range a.Identities {
+		if _, found := base.Identities[id]; !found {
+			return fmt.Errorf("identity %s of active directory %s could not be located\n%s", id, a.ID, a.Caller.Error())
+		}
+		selected[id] = true
+	}
+	matchAll := len(a.Identities) == 0 && len(a.IdentityTags) == 0 && len(a.IdentityVars) == 0
+	filtered := len(a.IdentityTags) > 0 || len(a.IdentityVars) > 0
+	for id, i := range base.Identities {
+		if matchAll || (filtered && matchesAll(i.Tags, a.IdentityTags) && matchesAll(i.Vars, a.IdentityVars)) {
+			selected[id] = true
+		}
+	}
+
+	ids := []string{}
+	for id := range selected {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		a.Members = append(a.Members, base.Identities[id])
+	}
+	return nil
+}
+
+func matchesAll(have, want map[string]string) bool {
+	for k, v := range want {
+		if have[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// DomainName returns the DNS name of the directory's domain, defaulting to the competition's root domain
+func (a *ActiveDirectory) DomainName(c *Competition) string {
+	if a.Domain != "" {
+		return a.Domain
+	}
+	if c != nil && c.DNS != nil {
+		return c.DNS.RootDomain
+	}
+	return ""
+}
+
+// RenderScript generates the PowerShell which creates the directory's OUs, groups and users for a team. Every object
+// is looked up before it is created and existing users are updated in place, so the script can be run again safely.
+func (a *ActiveDirectory) RenderScript(t *Team) ([]byte, error) {
+	domain := a.DomainName(t.Competition)
+	if domain == "" {
+		return nil, fmt.Errorf("active directory %s has no domain and the competition has no root domain", a.ID)
+	}
+	ctx := &adScriptContext{
+		Domain:                    domain,
+		BaseOU:                    a.BaseOU,
+		DisablePasswordComplexity: a.DisablePasswordComplexity,
+		OUs:                       []string{},
+		Groups:                    []string{},
+		Users:                     []*adUser{},
+	}
+	if ctx.BaseOU == "" {
+		ctx.BaseOU = DefaultActiveDirectoryOU
+	}
+
+	ous := map[string]bool{}
+	groups := map[string]bool{}
+	for _, g := range a.Groups {
+		groups[strings.TrimSpace(g)] = true
+	}
+	missing := []string{}
+	weak := []string{}
+	for _, i := range a.Members {
+		u := &adUser{
+			Username:    i.Vars[IdentityVarUsername],
+			GivenName:   i.Firstname,
+			Surname:     i.Lastname,
+			DisplayName: strings.TrimSpace(fmt.Sprintf("%s %s", i.Firstname, i.Lastname)),
+			Email:       i.Email,
+			Password:    t.IdentityPassword(i),
+			Title:       i.Vars[IdentityVarTitle],
+			Department:  i.Vars[IdentityVarDepartment],
+			Description: i.Description,
+			OU:          strings.Trim(i.Vars[IdentityVarOU], "/"),
+			Groups:      []string{},
+		}
+		if u.Username == "" {
+			u.Username = i.Base()
+		}
+		if u.Password == "" {
+			missing = append(missing, i.ID)
+			continue
+		}
+		if !a.DisablePasswordComplexity && !adPasswordIsComplex(u.Password, u.Username) {
+			weak = append(weak, i.ID)
+			continue
+		}
+		if u.OU == "" {
+			u.OU = u.Department
+		}
+		if u.OU != "" {
+			elems := strings.Split(u.OU, "/")
+			for idx := range elems {
+				ous[strings.Join(elems[:idx+1], "/")] = true
+			}
+		}
+		for _, g := range strings.Split(i.Vars[IdentityVarGroups], ",") {
+			g = strings.TrimSpace(g)
+			if g == "" {
+				continue
+			}
+			groups[g] = true
+			u.Groups = append(u.Groups, g)
+		}
+		ctx.Users = append(ctx.Users, u)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("identities of active directory %s have no password (set one, or a credential_seed for the build): %s", a.ID, strings.Join(missing, ", "))
+	}
+	if len(weak) > 0 {
+		return nil, fmt.Errorf("passwords of active directory %s identities do not meet the domain's complexity requirements (use a strong password_policy, or set disable_password_complexity): %s", a.ID, strings.Join(weak, ", "))
+	}
+
+	for ou := range ous {
+		ctx.OUs = append(ctx.OUs, ou)
+	}
+	sort.Slice(ctx.OUs, func(i, j int) bool {
+		di, dj := strings.Count(ctx.OUs[i], "/"), strings.Count(ctx.OUs[j], "/")
+		if di != dj {
+			return di < dj
+		}
+		return ctx.OUs[i] < ctx.OUs[j]
+	})
+	for g := range groups {
+		if g != "" {
+			ctx.Groups = append(ctx.Groups, g)
+		}
+	}
+	sort.Strings(ctx.Groups)
+
+	buf := new(bytes.Buffer)
+	err := adScriptTemplate.Execute(buf, ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not render active directory %s", a.ID)
+	}
+	return buf.Bytes(), nil
+}
+
+// adPasswordIsComplex reports whether a password would be accepted by a domain which enforces the default password
+// complexity rules. The password must be long enough, contain characters from three of upper case letters, lower case
+// letters, digits and other characters, and must not contain the account name.
+func adPasswordIsComplex(password string, username string) bool {
+	if len(password) < ADMinPasswordLength {
+		return false
+	}
+	if len(username) > 2 && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return false
+	}
+	var upper, lower, digit, other int
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+	return upper+lower+digit+other >= 3
+}
+
+type adScriptContext struct {
+	Domain                    string
+	BaseOU                    string
+	DisablePasswordComplexity bool
+	OUs                       []string
+	Groups                    []string
+	Users                     []*adUser
+}
+
+type adUser struct {
+	Username    string
+	GivenName   string
+	Surname     string
+	DisplayName string
+	Email       string
+	Password    string
+	Title       string
+	Department  string
+	Description string
+	OU          string
+	Groups      []string
+}
+
+// psQuote quotes a string as a PowerShell single quoted string, in which nothing is expanded
+func psQuote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// psArray renders strings as a PowerShell array of single quoted strings
+func psArray(s []string) string {
+	quoted := make([]string, len(s))
+	for i, x := range s {
+		quoted[i] = psQuote(x)
+	}
+	return "@(" + strings.Join(quoted, ", ") + ")"
+}
+
+var adScriptTemplate = template.Must(template.New("active_directory.ps1").Funcs(template.FuncMap{
+	"ps":      psQuote,
+	"psarray": psArray,
+}).Parse(`# Generated by laforge - creates the OUs, groups and users of an active directory. Safe to run repeatedly.
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'
+Import-Module ActiveDirectory
+
+$Domain = {{ ps .Domain }}
+$BaseOU = {{ ps .BaseOU }}
+$OUs = {{ psarray .OUs }}
+$Groups = {{ psarray .Groups }}
+$Users = @(
+{{- range .Users }}
+  @{ Username = {{ ps .Username }}; GivenName = {{ ps .GivenName }}; Surname = {{ ps .Surname }}; DisplayName = {{ ps .DisplayName }}; Email = {{ ps .Email }}; Password = {{ ps .Password }}; Title = {{ ps .Title }}; Department = {{ ps .Department }}; Description = {{ ps .Description }}; OU = {{ ps .OU }}; Groups = {{ psarray .Groups }} }
+{{- end }}
+)
+
+function ConvertTo-RDNValue([string]$Value) {
+  return ($Value -replace '([,\\#+<>;"=])', '\$1')
+}
+
+function ConvertTo-LDAPValue([string]$Value) {
+  return $Value.Replace('\', '\5c').Replace('*', '\2a').Replace('(', '\28').Replace(')', '\29')
+}
+
+function Test-ADPath([string]$DN) {
+  try {
+    Get-ADObject -Identity $DN | Out-Null
+    return $true
+  } catch [Microsoft.ActiveDirectory.Management.ADIdentityNotFoundException] {
+    return $false
+  }
+}
+
+function Confirm-OU([string]$Name, [string]$ParentDN) {
+  $dn = "OU=$(ConvertTo-RDNValue $Name),$ParentDN"
+  if (-not (Test-ADPath $dn)) {
+    New-ADOrganizationalUnit -Name $Name -Path $ParentDN -ProtectedFromAccidentalDeletion $false
+    Write-Host "[laforge] created OU $dn"
+  }
+  return $dn
+}
+
+function Get-OUPath([string]$Path) {
+  $dn = $BaseDN
+  if ($Path) {
+    foreach ($elem in $Path.Split('/')) {
+      $dn = "OU=$(ConvertTo-RDNValue $elem),$dn"
+    }
+  }
+  return $dn
+}
+
+$DomainDN = (Get-ADDomain -Identity $Domain).DistinguishedName
+{{- if .DisablePasswordComplexity }}
+Set-ADDefaultDomainPasswordPolicy -Identity $Domain -ComplexityEnabled $false -MinPasswordLength 0 -PasswordHistoryCount 0
+{{- end }}
+
+$BaseDN = Confirm-OU $BaseOU $DomainDN
+$GroupsDN = Confirm-OU 'Groups' $BaseDN
+foreach ($ou in $OUs) {
+  $parent = $ou.Substring(0, [Math]::Max(0, $ou.LastIndexOf('/')))
+  $name = $ou.Substring($ou.LastIndexOf('/') + 1)
+  Confirm-OU $name (Get-OUPath $parent) | Out-Null
+}
+
+$GroupDNs = @{}
+foreach ($name in $Groups) {
+  $group = Get-ADGroup -LDAPFilter "(|(sAMAccountName=$(ConvertTo-LDAPValue $name))(name=$(ConvertTo-LDAPValue $name)))"
+  if (-not $group) {
+    $group = New-ADGroup -Name $name -SamAccountName $name -GroupScope Global -GroupCategory Security -Path $GroupsDN -PassThru
+    Write-Host "[laforge] created group $name"
+  }
+  $GroupDNs[$name] = @($group)[0].DistinguishedName
+}
+
+foreach ($u in $Users) {
+  $path = Get-OUPath $u.OU
+  $password = ConvertTo-SecureString $u.Password -AsPlainText -Force
+  $props = @{ GivenName = $u.GivenName; Surname = $u.Surname; DisplayName = $u.DisplayName; EmailAddress = $u.Email; UserPrincipalName = "$($u.Username)@$Domain" }
+  foreach ($key in 'Title', 'Department', 'Description') {
+    if ($u[$key]) { $props[$key] = $u[$key] }
+  }
+  $user = Get-ADUser -LDAPFilter "(sAMAccountName=$(ConvertTo-LDAPValue $u.Username))" -Properties MemberOf
+  if ($user) {
+    Set-ADUser -Identity $user @props
+    Set-ADAccountPassword -Identity $user -Reset -NewPassword $password
+    Enable-ADAccount -Identity $user
+    $parentDN = $user.DistinguishedName.Substring($user.DistinguishedName.IndexOf(',') + 1)
+    if ($parentDN -ne $path) {
+      $user = Move-ADObject -Identity $user -TargetPath $path -PassThru | Get-ADUser -Properties MemberOf
+    }
+    Write-Host "[laforge] updated user $($u.Username)"
+  } else {
+    $cn = $u.DisplayName
+    if (-not $cn -or (Test-ADPath "CN=$(ConvertTo-RDNValue $cn),$path")) {
+      $cn = ("$cn ($($u.Username))").Trim()
+    }
+    New-ADUser -Name $cn -SamAccountName $u.Username -Path $path -AccountPassword $password -Enabled $true -ChangePasswordAtLogon $false -PasswordNeverExpires $true @props
+    $user = Get-ADUser -Identity $u.Username -Properties MemberOf
+    Write-Host "[laforge] created user $($u.Username)"
+  }
+  foreach ($name in $u.Groups) {
+    if ($user.MemberOf -notcontains $GroupDNs[$name]) {
+      Add-ADGroupMember -Identity $GroupDNs[$name] -Members $user
+      Write-Host "[laforge] added $($u.Username) to $name"
+    }
+  }
+}
+`))
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/gen0cide/laforge/core/cli"
+	"github.com/pkg/errors"
+)
+
+// ActiveDirectoryJob generates the PowerShell for an active directory and executes it on the domain controller
+// easyjson:json
+type ActiveDirectoryJob struct {
+	GenericJob
+	Target          *ProvisioningStep `json:"-"`
+	ActiveDirectory *ActiveDirectory  `json:"-"`
+}
+
+// CreateActiveDirectoryJob creates a new active directory job for a Doer object with the Planner
+func CreateActiveDirectoryJob(id string, offset int, m *Metadata, pstep *ProvisioningStep) (*ActiveDirectoryJob, error) {
+	aj := &ActiveDirectoryJob{
+		Target: pstep,
+	}
+	aj.Metadata = m
+	aj.MetadataID = m.GetID()
+	aj.Offset = offset
+	aj.JobID = id
+	aj.ActiveDirectory = aj.Target.ActiveDirectory
+	if aj.Target.ActiveDirectory.Timeout != 0 {
+		aj.Timeout = aj.Target.ActiveDirectory.Timeout
+	}
+	aj.JobType = "active_directory_job"
+	aj.CreatedAt = time.Now()
+	return aj, nil
+}
+
+// CanProceed implements the Doer interface
+func (j *ActiveDirectoryJob) CanProceed(e chan error) {
+	if j.ActiveDirectory == nil || j.Target == nil {
+		e <- errors.New("cannot proceed with active directory job with nil targets")
+		return
+	}
+	if !j.Target.Host.IsWindows() {
+		e <- fmt.Errorf("active directory %s can only be provisioned on a windows host", j.ActiveDirectory.ID)
+		return
+	}
+	if j.Target.ProvisionedHost.Conn.Active {
+		e <- nil
+		return
+	}
+
+	pathToConnFile := filepath.Join(j.Base.BaseDir, j.Target.ParentLaforgeID(), "conn.laforge")
+
+	logdir := filepath.Join(j.Base.BaseDir, j.Target.ParentLaforgeID(), "logs")
+	if _, err := os.Stat(logdir); err != nil {
+		if os.IsNotExist(err) {
+			//nolint:gosec,errcheck
+			os.MkdirAll(logdir, 0755)
+		} else {
+			cli.Logger.Errorf("Error creating log directory %s: %v", logdir, err)
+			e <- err
+			return
+		}
+	}
+
+	if _, err := os.Stat(pathToConnFile); err != nil {
+		if os.IsNotExist(err) {
+			e <- NewTimeoutExtension(fmt.Errorf("cannot proceed with a host that has no connection definition: %s", pathToConnFile))
+			return
+		}
+		e <- nil
+		return
+	}
+
+	conn := &Connection{}
+	err := LoadHCLFromFile(pathToConnFile, conn)
+	if err != nil {
+		cli.Logger.Errorf("Error loading job %s resource: %v", j.JobID, err)
+		e <- err
+		return
+	}
+
+	if !conn.Active {
+		e <- NewTimeoutExtension(errors.New("cannot proceed with a host with an inactive connection"))
+		return
+	}
+
+	newConn, err := SmartMerge(j.Target.ProvisionedHost.Conn, conn, false)
+	if err != nil {
+		e <- fmt.Errorf("fatal error attempting to patch connection into state tree for %s: %v", j.JobID, err)
+		return
+	}
+
+	j.Target.ProvisionedHost.Conn = newConn.(*Connection)
+
+	if !j.Target.ProvisionedHost.Conn.Test() {
+		e <- NewTimeoutExtensionWithDelay(errors.New("Unable to successfuly make a test connection to host, retrying after a delay"), 20)
+		return
+	}
+
+	e <- nil
+}
+
+// EnsureDependencies implements the Doer interface. The script is rendered to catch configuration errors early, but
+// is only written to disk by Do.
+func (j *ActiveDirectoryJob) EnsureDependencies(e chan error) {
+	if j.Target.ProvisionedHost.Conn == nil {
+		e <- fmt.Errorf("active directory %s has a nil connection for the parent host", j.JobID)
+		return
+	}
+	if !j.Target.ProvisionedHost.Conn.IsWinRM() {
+		e <- fmt.Errorf("active directory %s requires a WinRM connection to %s", j.JobID, j.Target.ParentLaforgeID())
+		return
+	}
+
+	_, err := j.ActiveDirectory.RenderScript(j.Target.Team)
+	if err != nil {
+		e <- err
+		return
+	}
+	e <- nil
+}
+
+// Do implements the Doer interface. The rendered script contains every member's password, so it is written to a
+// temporary file which is removed once it has been sent, whether or not the job succeeds.
+func (j *ActiveDirectoryJob) Do(e chan error) {
+	cli.Logger.Warnf("Performing Active Directory Job:\n  %s %s: %s (%d identities)\n  %s   %s: %s", color.HiBlueString(">>"), color.HiCyanString(ObjectTypeActiveDirectory.String()), color.HiGreenString("%s", j.ActiveDirectory.ID), len(j.ActiveDirectory.Members), color.HiBlueString(">>"), color.HiCyanString("HOST"), color.HiGreenString("%s", j.Target.ProvisionedHost.Conn.RemoteAddr))
+	script, err := j.ActiveDirectory.RenderScript(j.Target.Team)
+	if err != nil {
+		e <- err
+		return
+	}
+	tmp, err := ioutil.TempFile("", "laforge-ad-")
+	if err != nil {
+		e <- err
+		return
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(script)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		e <- err
+		return
+	}
+
+	actualfilename := fmt.Sprintf("%d-%s.ps1", j.Target.StepNumber, j.ActiveDirectory.Base())
+	logdir := filepath.Join(j.Base.BaseDir, j.Target.ParentLaforgeID(), "logs")
+	err = j.Target.ProvisionedHost.Conn.UploadExecuteAndDelete(j, tmp.Name(), actualfilename, logdir)
+	if err != nil {
+		cli.Logger.Errorf("Error executing %s: %v", j.JobID, err)
+		e <- err
+		return
+	}
+	e <- nil
+}
+
+// CleanUp implements the Doer interface
+func (j *ActiveDirectoryJob) CleanUp(e chan error) {
+	e <- nil
+}
+
+// Finish implements the Doer interface
+func (j *ActiveDirectoryJob) Finish(e chan error) {
+	cli.Logger.Infof("Finished %s", j.JobID)
+	e <- nil
+}
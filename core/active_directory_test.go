@@ -0,0 +1,27 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestADPasswordIsComplex(t *testing.T) {
+	tests := []struct {
+		password string
+		username string
+		want     bool
+	}{
+		{"summer2019", "jsmith", false},
+		{"Summer2019", "jsmith", true},
+		{"summer2019!", "jsmith", true},
+		{"Sum3r!", "jsmith", false},
+		{"JSmith2019!", "jsmith", false},
+		{"Password", "jsmith", false},
+		{"aB3!aB3!", "ab", true},
+	}
+	for _, tt := range tests {
+		got := adPasswordIsComplex(tt.password, tt.username)
+		if got != tt.want {
+			t.Errorf("adPasswordIsComplex(%q, %q) = %v, want %v", tt.password, tt.username, got, tt.want)
+		}
+	}
+}
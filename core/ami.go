@@ -1,5 +1,13 @@
 package core
 
+import (
+	"fmt"
+	"path"
+
+	"github.com/cespare/xxhash"
+	"github.com/pkg/errors"
+)
+
 // AMI represents a configurable object for defining custom AMIs in cloud infrastructure
 //easyjson:json
 type AMI struct {
@@ -11,4 +19,69 @@ type AMI struct {
 	Vars        map[string]string `hcl:"vars,optional" json:"vars,omitempty"`
 	Tags        map[string]string `hcl:"tags,optional" json:"tags,omitempty"`
 	Maintainer  *User             `hcl:"maintainer,block" json:"maintainer,omitempty"`
+	OnConflict  *OnConflict       `hcl:"on_conflict,block" json:"on_conflict,omitempty"`
+	Caller      Caller            `json:"-"`
+}
+
+// Hash implements the Hasher interface
+func (a *AMI) Hash() uint64 {
+	return xxhash.Sum64String(
+		fmt.Sprintf(
+			"name=%v provider=%v username=%v vars=%v",
+			a.Name,
+			a.Provider,
+			a.Username,
+			a.Vars,
+		),
+	)
+}
+
+// Path implements the Pather interface
+func (a *AMI) Path() string {
+	return a.ID
+}
+
+// Base implements the Pather interface
+func (a *AMI) Base() string {
+	return path.Base(a.ID)
+}
+
+// GetCaller implements the Mergeable interface
+func (a *AMI) GetCaller() Caller {
+	return a.Caller
+}
+
+// LaforgeID implements the Mergeable interface
+func (a *AMI) LaforgeID() string {
+	return a.ID
+}
+
+// GetOnConflict implements the Mergeable interface
+func (a *AMI) GetOnConflict() OnConflict {
+	if a.OnConflict == nil {
+		return OnConflict{
+			Do: "default",
+		}
+	}
+	return *a.OnConflict
+}
+
+// SetCaller implements the Mergeable interface
+func (a *AMI) SetCaller(c Caller) {
+	a.Caller = c
+}
+
+// SetOnConflict implements the Mergeable interface
+func (a *AMI) SetOnConflict(o OnConflict) {
+	a.OnConflict = &o
+}
+
+// Swap implements the Mergeable interface
+func (a *AMI) Swap(m Mergeable) error {
+	rawVal, ok := m.(*AMI)
+	if !ok {
+		return errors.Wrapf(ErrSwapTypeMismatch, "expected %T, got %T", a, m)
+	}
+	*a = *rawVal
+	return nil
 }
@@ -80,6 +80,7 @@ type Laforge struct {
 	DefinedCommands            []*Command                     `hcl:"command,block" json:"defined_commands,omitempty"`
 	DefinedRemoteFiles         []*RemoteFile                  `hcl:"remote_file,block" json:"defined_files,omitempty"`
 	DefinedDNSRecords          []*DNSRecord                   `hcl:"dns_record,block" json:"defined_dns_records,omitempty"`
+	DefinedActiveDirectories   []*ActiveDirectory             `hcl:"active_directory,block" json:"active_directories,omitempty"`
 	DefinedEnvironments        []*Environment                 `hcl:"environment,block" json:"environments,omitempty"`
 	DefinedBuilds              []*Build                       `hcl:"build,block" json:"builds,omitempty"`
 	DefinedTeams               []*Team                        `hcl:"team,block" json:"teams,omitempty"`
@@ -87,6 +88,7 @@ type Laforge struct {
 	DefinedProvisionedHosts    []*ProvisionedHost             `hcl:"provisioned_host,block" json:"provisioned_hosts,omitempty"`
 	DefinedProvisioningSteps   []*ProvisioningStep            `hcl:"provisioning_step,block" json:"provisioning_steps,omitempty"`
 	DefinedConnections         []*Connection                  `hcl:"connection,block" json:"connections,omitempty"`
+	DefinedAMIs                []*AMI                         `hcl:"ami,block" json:"amis,omitempty"`
 	Hosts                      map[string]*Host               `json:"-"`
 	Networks                   map[string]*Network            `json:"-"`
 	Identities                 map[string]*Identity           `json:"-"`
@@ -94,6 +96,7 @@ type Laforge struct {
 	Commands                   map[string]*Command            `json:"-"`
 	RemoteFiles                map[string]*RemoteFile         `json:"-"`
 	DNSRecords                 map[string]*DNSRecord          `json:"-"`
+	ActiveDirectories          map[string]*ActiveDirectory    `json:"-"`
 	Competitions               map[string]*Competition        `json:"-"`
 	Environments               map[string]*Environment        `json:"-"`
 	Builds                     map[string]*Build              `json:"-"`
@@ -102,6 +105,7 @@ type Laforge struct {
 	ProvisionedHosts           map[string]*ProvisionedHost    `json:"-"`
 	ProvisioningSteps          map[string]*ProvisioningStep   `json:"-"`
 	Connections                map[string]*Connection         `json:"-"`
+	AMIs                       map[string]*AMI                `json:"-"`
 	Caller                     Caller                         `json:"-"`
 	ValidTeam                  bool                           `json:"-"`
 	ValidBuild                 bool                           `json:"-"`
@@ -256,6 +260,7 @@ func (l *Laforge) CreateIndex() {
 	l.Commands = map[string]*Command{}
 	l.RemoteFiles = map[string]*RemoteFile{}
 	l.DNSRecords = map[string]*DNSRecord{}
+	l.ActiveDirectories = map[string]*ActiveDirectory{}
 	l.Teams = map[string]*Team{}
 	l.Builds = map[string]*Build{}
 	l.Competitions = map[string]*Competition{}
@@ -264,6 +269,7 @@ func (l *Laforge) CreateIndex() {
 	l.ProvisionedHosts = map[string]*ProvisionedHost{}
 	l.ProvisioningSteps = map[string]*ProvisioningStep{}
 	l.Connections = map[string]*Connection{}
+	l.AMIs = map[string]*AMI{}
 	for _, x := range l.DefinedHosts {
 		l.Hosts[x.ID] = x
 		x.Caller = l.Caller
@@ -324,6 +330,10 @@ func (l *Laforge) CreateIndex() {
 		l.DNSRecords[x.ID] = x
 		x.Caller = l.Caller
 	}
+	for _, x := range l.DefinedActiveDirectories {
+		l.ActiveDirectories[x.ID] = x
+		x.Caller = l.Caller
+	}
 	for _, x := range l.DefinedBuilds {
 		l.Builds[x.LaforgeID()] = x
 		x.Caller = l.Caller
@@ -336,6 +346,10 @@ func (l *Laforge) CreateIndex() {
 		l.Competitions[x.LaforgeID()] = x
 		x.Caller = l.Caller
 	}
+	for _, x := range l.DefinedAMIs {
+		l.AMIs[x.ID] = x
+		x.Caller = l.Caller
+	}
 }
 
 // Update performs a patching operation on source (l) with diff (diff), using the diff's merge conflict settings as appropriate.
@@ -476,6 +490,21 @@ func Mask(base, layer *Laforge) (*Laforge, error) {
 			return nil, errors.WithStack(errors.Wrapf(ErrSwapTypeMismatch, "expected %T, got %T", orig, res))
 		}
 	}
+	for name, obj := range layer.ActiveDirectories {
+		orig, found := base.ActiveDirectories[name]
+		if !found {
+			base.ActiveDirectories[name] = obj
+			continue
+		}
+		res, err := SmartMerge(orig, obj, false)
+		if err != nil {
+			return nil, err
+		}
+		orig, ok := res.(*ActiveDirectory)
+		if !ok {
+			return nil, errors.WithStack(errors.Wrapf(ErrSwapTypeMismatch, "expected %T, got %T", orig, res))
+		}
+	}
 
 	for id, obj := range layer.Competitions {
 		orig, found := base.Competitions[id]
@@ -597,6 +626,21 @@ func Mask(base, layer *Laforge) (*Laforge, error) {
 			return nil, errors.WithStack(errors.Wrapf(ErrSwapTypeMismatch, "expected %T, got %T", orig, res))
 		}
 	}
+	for name, obj := range layer.AMIs {
+		orig, found := base.AMIs[name]
+		if !found {
+			base.AMIs[name] = obj
+			continue
+		}
+		res, err := SmartMerge(orig, obj, false)
+		if err != nil {
+			return nil, err
+		}
+		orig, ok := res.(*AMI)
+		if !ok {
+			return nil, errors.WithStack(errors.Wrapf(ErrSwapTypeMismatch, "expected %T, got %T", orig, res))
+		}
+	}
 	return base.Update(layer)
 }
 
@@ -780,6 +824,13 @@ func (l *Laforge) IndexProvisioningStepDependencies() error {
 			}
 			ps.Script = prov
 			ps.Provisioner = prov
+		case ObjectTypeActiveDirectory.String():
+			prov, found := l.ActiveDirectories[ps.ProvisionerID]
+			if !found {
+				return fmt.Errorf("active directory %s for provisioning step %s could not be located", ps.ProvisionerID, ps.Path())
+			}
+			ps.ActiveDirectory = prov
+			ps.Provisioner = prov
 		default:
 			return fmt.Errorf("unknown provisioner type %s for provisioning step %s", ps.ProvisionerType, ps.Path())
 		}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 
@@ -81,8 +82,10 @@ type internalLogger struct {
 }
 
 type logWriter struct {
+	sync.Mutex
 	Name string
 	Prog string
+	Out  io.Writer
 }
 
 func init() {
@@ -117,10 +120,22 @@ func SetLogLevel(level string) {
 
 // SetLogName allows you to override the log name parameter (part after LAFORGE in log output)
 func SetLogName(name string) {
+	intLogger.writer.Lock()
+	defer intLogger.writer.Unlock()
 	intLogger.writer.Name = name
 }
 
+// SetLogOutput redirects the Laforge global logger to w, or back to the terminal if w is nil. It is safe to call while
+// other goroutines are logging.
+func SetLogOutput(w io.Writer) {
+	intLogger.writer.Lock()
+	defer intLogger.writer.Unlock()
+	intLogger.writer.Out = w
+}
+
 func (w *logWriter) Write(p []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
 	output := fmt.Sprintf(
 		"%s%s%s%s%s %s",
 		Boldwhite("["),
@@ -130,6 +145,10 @@ func (w *logWriter) Write(p []byte) (int, error) {
 		Boldwhite("]"),
 		string(p),
 	)
-	written, err := io.Copy(color.Output, strings.NewReader(output))
+	out := w.Out
+	if out == nil {
+		out = color.Output
+	}
+	written, err := io.Copy(out, strings.NewReader(output))
 	return int(written), err
 }
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+func TestSetLogOutputWhileLogging(t *testing.T) {
+	defer SetLogOutput(nil)
+	SetLogOutput(ioutil.Discard)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				Logger.Errorf("message %d", n)
+			}
+		}()
+	}
+	for n := 0; n < 100; n++ {
+		SetLogOutput(ioutil.Discard)
+	}
+	wg.Wait()
+}
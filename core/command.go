@@ -14,21 +14,25 @@ import (
 //easyjson:json
 //nolint:maligned
 type Command struct {
-	ID           string            `hcl:"id,label" json:"id,omitempty"`
-	Name         string            `hcl:"name,attr" json:"name,omitempty"`
-	Description  string            `hcl:"description,attr" json:"description,omitempty"`
-	Program      string            `hcl:"program,attr" json:"program,omitempty"`
-	Args         []string          `hcl:"args,attr" json:"args,omitempty"`
-	IgnoreErrors bool              `hcl:"ignore_errors,attr" json:"ignore_errors,omitempty"`
-	Cooldown     int               `hcl:"cooldown,attr" json:"cooldown,omitempty"`
-	Timeout      int               `hcl:"timeout,attr" json:"timeout,omitempty"`
-	Disabled     bool              `hcl:"disabled,attr" json:"disabled,omitempty"`
-	Vars         map[string]string `hcl:"vars,attr" json:"vars,omitempty"`
-	Tags         map[string]string `hcl:"tags,attr" json:"tags,omitempty"`
-	IO           *IO               `hcl:"io,block" json:"io,omitempty"`
-	OnConflict   *OnConflict       `hcl:"on_conflict,block" json:"on_conflict,omitempty"`
-	Maintainer   *User             `hcl:"maintainer,block" json:"maintainer,omitempty"`
-	Caller       Caller            `json:"-"`
+	ID             string            `hcl:"id,label" json:"id,omitempty"`
+	Name           string            `hcl:"name,attr" json:"name,omitempty"`
+	Description    string            `hcl:"description,attr" json:"description,omitempty"`
+	Program        string            `hcl:"program,attr" json:"program,omitempty"`
+	Args           []string          `hcl:"args,attr" json:"args,omitempty"`
+	IgnoreErrors   bool              `hcl:"ignore_errors,attr" json:"ignore_errors,omitempty"`
+	Reboot         bool              `hcl:"reboot,optional" json:"reboot,omitempty"`
+	RebootExitCode int               `hcl:"reboot_exit_code,optional" json:"reboot_exit_code,omitempty"`
+	Retries        int               `hcl:"retries,optional" json:"retries,omitempty"`
+	RetryBackoff   int               `hcl:"retry_backoff,optional" json:"retry_backoff,omitempty"`
+	Cooldown       int               `hcl:"cooldown,attr" json:"cooldown,omitempty"`
+	Timeout        int               `hcl:"timeout,attr" json:"timeout,omitempty"`
+	Disabled       bool              `hcl:"disabled,attr" json:"disabled,omitempty"`
+	Vars           map[string]string `hcl:"vars,attr" json:"vars,omitempty"`
+	Tags           map[string]string `hcl:"tags,attr" json:"tags,omitempty"`
+	IO             *IO               `hcl:"io,block" json:"io,omitempty"`
+	OnConflict     *OnConflict       `hcl:"on_conflict,block" json:"on_conflict,omitempty"`
+	Maintainer     *User             `hcl:"maintainer,block" json:"maintainer,omitempty"`
+	Caller         Caller            `json:"-"`
 }
 
 // Hash implements the Hasher interface
@@ -50,6 +50,11 @@ func (j *CommandJob) CanProceed(e chan error) {
 		e <- errors.New("Command job had no targets")
 		return
 	}
+	// Rebooting is left to agents, which wait for the host to come back
+	if j.Command.Reboot || j.Command.RebootExitCode != 0 {
+		e <- fmt.Errorf("command %s on %s: %v", j.Command.ID, j.Target.ParentLaforgeID(), ErrRebootRequiresAgent)
+		return
+	}
 	// We need to make sure we have an active connection
 	if j.Target.ProvisionedHost.Conn.Active {
 		e <- nil
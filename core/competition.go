@@ -10,14 +10,15 @@ import (
 // Competition is a configurable type that holds competition wide settings
 //easyjson:json
 type Competition struct {
-	ID           string            `hcl:"id,label" json:"id,omitempty"`
-	BaseDir      string            `hcl:"base_dir,optional" json:"base_dir,omitempty"`
-	RootPassword string            `hcl:"root_password,attr" json:"root_password,omitempty"`
-	DNS          *DNS              `hcl:"dns,block" json:"dns,omitempty"`
-	Remote       *Remote           `hcl:"remote,block" json:"remote,omitempty"`
-	Config       map[string]string `hcl:"config,optional" json:"config,omitempty"`
-	OnConflict   *OnConflict       `hcl:"on_conflict,block" json:"on_conflict,omitempty"`
-	Caller       Caller            `json:"-"`
+	ID               string            `hcl:"id,label" json:"id,omitempty"`
+	BaseDir          string            `hcl:"base_dir,optional" json:"base_dir,omitempty"`
+	RootPassword     string            `hcl:"root_password,attr" json:"root_password,omitempty"`
+	DNS              *DNS              `hcl:"dns,block" json:"dns,omitempty"`
+	Remote           *Remote           `hcl:"remote,block" json:"remote,omitempty"`
+	Config           map[string]string `hcl:"config,optional" json:"config,omitempty"`
+	PasswordPolicies []*PasswordPolicy `hcl:"password_policy,block" json:"password_policies,omitempty"`
+	OnConflict       *OnConflict       `hcl:"on_conflict,block" json:"on_conflict,omitempty"`
+	Caller           Caller            `json:"-"`
 }
 
 // Hash implements the Hasher interface
@@ -96,16 +97,3 @@ func (c *Competition) Swap(m Mergeable) error {
 	*c = *rawVal
 	return nil
 }
-
-// PasswordForHost is a template helper function to allow a overridden password to be retrieved
-func (c *Competition) PasswordForHost(h *Host) string {
-	if h == nil {
-		return c.RootPassword
-	}
-
-	if h.OverridePassword == "" {
-		return c.RootPassword
-	}
-
-	return h.OverridePassword
-}
@@ -164,6 +164,14 @@ func (c *Connection) Upload(src, dst string) error {
 	return c.UploadSFTP(src, dst)
 }
 
+// Download downloads a src file/dir/glob from the provisioned host into the local dst file/dir
+func (c *Connection) Download(src, dst string, recursive bool) error {
+	if c.IsWinRM() {
+		return c.DownloadWinRM(src, dst, recursive)
+	}
+	return c.DownloadSFTP(src, dst, recursive)
+}
+
 // Test will test our connection across the network to make sure it's working
 func (c *Connection) Test() bool {
 	// If it's a windows system, let's test WinRM
@@ -697,6 +705,16 @@ func (c *Connection) UploadWinRM(src, dst string) error {
 	return client.Copy(src, dst)
 }
 
+// DownloadWinRM uses WinRM to download src on the provisioned host to dst
+func (c *Connection) DownloadWinRM(src, dst string, recursive bool) error {
+	client := &WinRMClient{}
+	err := client.SetConfig(c.WinRMAuthConfig)
+	if err != nil {
+		return err
+	}
+	return client.Download(src, dst, recursive)
+}
+
 // Gather implements the dependency interface
 func (c *Connection) Gather(s *Snapshot) error {
 	return nil
@@ -874,3 +892,39 @@ func (c *Connection) UploadSCP(src, dst string) error {
 	}
 	return nil
 }
+
+// DownloadSFTP uses the really nice golang SFTP client to download remote files
+func (c *Connection) DownloadSFTP(src, dst string, recursive bool) error {
+	client, err := NewSSHClient(c.SSHAuthConfig, "")
+	if err != nil {
+		return err
+	}
+
+	err = client.Connect()
+	if err != nil {
+		return err
+	}
+
+	//nolint:errcheck
+	defer client.Disconnect()
+
+	return client.DownloadV2(src, dst, recursive)
+}
+
+// DownloadSCP uses scp to download src on the provisioned host to dst
+func (c *Connection) DownloadSCP(src, dst string, recursive bool) error {
+	client, err := NewSSHClient(c.SSHAuthConfig, "")
+	if err != nil {
+		return err
+	}
+
+	err = client.Connect()
+	if err != nil {
+		return err
+	}
+
+	//nolint:errcheck
+	defer client.Disconnect()
+
+	return client.DownloadSCP(src, dst, recursive)
+}
@@ -0,0 +1,298 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/gen0cide/laforge/core/cli"
+	"github.com/gen0cide/laforge/generators/creds"
+)
+
+const (
+	// ConfigKeyCredentialSeed is the build config key holding the seed passwords are generated from. Passwords are only
+	// generated for builds which set it, and stay the same for as long as it does not change.
+	ConfigKeyCredentialSeed = `credential_seed`
+
+	// VarKeyPasswordPolicy is the identity or host var naming the password policy of it's generated password. A host
+	// only has a password generated when it sets this var.
+	VarKeyPasswordPolicy = `password_policy`
+
+	// DefaultIdentityPasswordPolicy is the password policy of identities which do not name one
+	DefaultIdentityPasswordPolicy = `weak`
+
+	// CredentialTypeIdentity marks a credential as belonging to an identity
+	CredentialTypeIdentity = `identity`
+
+	// CredentialTypeHost marks a credential as belonging to a provisioned host
+	CredentialTypeHost = `host`
+)
+
+// PasswordPolicy is a named set of rules for generating passwords, declared in a competition alongside the built in
+// weak and strong policies
+type PasswordPolicy struct {
+	ID             string `hcl:"id,label" json:"id,omitempty"`
+	Weak           bool   `hcl:"weak,optional" json:"weak,omitempty"`
+	Steps          int    `hcl:"steps,optional" json:"steps,omitempty"`
+	Length         int    `hcl:"length,optional" json:"length,omitempty"`
+	Charset        string `hcl:"charset,optional" json:"charset,omitempty"`
+	RequireUpper   bool   `hcl:"require_upper,optional" json:"require_upper,omitempty"`
+	RequireLower   bool   `hcl:"require_lower,optional" json:"require_lower,omitempty"`
+	RequireDigit   bool   `hcl:"require_digit,optional" json:"require_digit,omitempty"`
+	RequireSpecial bool   `hcl:"require_special,optional" json:"require_special,omitempty"`
+}
+
+// Policy converts the policy to it's generator form
+func (p *PasswordPolicy) Policy() *creds.Policy {
+	return &creds.Policy{
+		Weak:           p.Weak,
+		Steps:          p.Steps,
+		Length:         p.Length,
+		Charset:        p.Charset,
+		RequireUpper:   p.RequireUpper,
+		RequireLower:   p.RequireLower,
+		RequireDigit:   p.RequireDigit,
+		RequireSpecial: p.RequireSpecial,
+	}
+}
+
+// LookupPasswordPolicy returns the competition's password policy of the provided name, falling back to the built in
+// policies
+func (c *Competition) LookupPasswordPolicy(name string) (*creds.Policy, error) {
+	for _, p := range c.PasswordPolicies {
+		if p.ID == name {
+			return p.Policy(), nil
+		}
+	}
+	if p, ok := creds.Policies[name]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("password policy %s is not defined", name)
+}
+
+// Credential is the password of an identity or provisioned host within a team
+type Credential struct {
+	Type      string `json:"type"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Policy    string `json:"policy,omitempty"`
+	Password  string `json:"password"`
+	Generated bool   `json:"generated"`
+}
+
+// CredentialSheet lists the passwords of every identity and provisioned host of a team
+type CredentialSheet struct {
+	TeamID      string        `json:"team_id"`
+	TeamNumber  int           `json:"team_number"`
+	Credentials []*Credential `json:"credentials"`
+}
+
+// credentialSeed returns the seed the team's passwords are generated from, if it's build has one
+func (t *Team) credentialSeed() (string, bool) {
+	if t.Build == nil {
+		return "", false
+	}
+	seed, ok := t.Build.Config[ConfigKeyCredentialSeed]
+	return seed, ok && seed != ""
+}
+
+// GeneratePassword creates the team's password for an object under the named policy. The password is derived from the
+// build's credential seed, the team number and the object's ID, so it is the same every time the build is rendered.
+// Generated passwords are redacted like any other secret.
+func (t *Team) GeneratePassword(objectType string, id string, policy string) (string, error) {
+	seed, ok := t.credentialSeed()
+	if !ok {
+		return "", fmt.Errorf("build does not set the %s config key", ConfigKeyCredentialSeed)
+	}
+	if t.Competition == nil {
+		return "", errors.New("team has no competition to look up password policies in")
+	}
+	p, err := t.Competition.LookupPasswordPolicy(policy)
+	if err != nil {
+		return "", err
+	}
+	g := creds.NewGenerator(creds.SeedFrom(seed, strconv.Itoa(t.TeamNumber), objectType, id))
+	pass, err := g.Password(p)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not generate %s password for %s", policy, id)
+	}
+	Secrets.Add(pass)
+	return pass, nil
+}
+
+// IdentityPassword returns the identity's password within the team. Identities which do not set a password have one
+// generated under their password_policy var (default weak) when the build sets a credential seed.
+func (t *Team) IdentityPassword(i *Identity) string {
+	pass, _, _ := t.identityCredential(i)
+	return pass
+}
+
+func (t *Team) identityCredential(i *Identity) (string, string, bool) {
+	if i.Password != "" {
+		return i.Password, "", false
+	}
+	if _, ok := t.credentialSeed(); !ok {
+		return "", "", false
+	}
+	policy := i.Vars[VarKeyPasswordPolicy]
+	if policy == "" {
+		policy = DefaultIdentityPasswordPolicy
+	}
+	pass, err := t.GeneratePassword(CredentialTypeIdentity, i.ID, policy)
+	if err != nil {
+		cli.Logger.Errorf("Could not generate a password for identity %s in team %d: %v", i.ID, t.TeamNumber, err)
+		return "", policy, false
+	}
+	return pass, policy, true
+}
+
+// HostPassword returns the generated password of a host within the team. A password is only generated for hosts
+// which do not set an override_password, and name a policy with their password_policy var. An error is returned when
+// the named policy can not be used, rather than leaving the host with the competition's root password.
+func (t *Team) HostPassword(h *Host) (string, bool, error) {
+	if h == nil || h.OverridePassword != "" {
+		return "", false, nil
+	}
+	policy := h.Vars[VarKeyPasswordPolicy]
+	if policy == "" {
+		return "", false, nil
+	}
+	if _, ok := t.credentialSeed(); !ok {
+		return "", false, nil
+	}
+	pass, err := t.GeneratePassword(CredentialTypeHost, h.ID, policy)
+	if err != nil {
+		return "", false, errors.Wrapf(err, "could not generate a password for host %s in team %d", h.ID, t.TeamNumber)
+	}
+	return pass, true, nil
+}
+
+// CredentialSheet lists the passwords of the provided identities and every provisioned host of the team
+func (t *Team) CredentialSheet(identities map[string]*Identity) (*CredentialSheet, error) {
+	sheet := &CredentialSheet{
+		TeamID:      t.Path(),
+		TeamNumber:  t.TeamNumber,
+		Credentials: []*Credential{},
+	}
+
+	ids := []string{}
+	for id := range identities {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		i := identities[id]
+		pass, policy, generated := t.identityCredential(i)
+		sheet.Credentials = append(sheet.Credentials, &Credential{
+			Type:      CredentialTypeIdentity,
+			ID:        i.ID,
+			Name:      i.Email,
+			Policy:    policy,
+			Password:  pass,
+			Generated: generated,
+		})
+	}
+
+	phids := []string{}
+	for phid := range t.ProvisionedHosts {
+		phids = append(phids, phid)
+	}
+	sort.Strings(phids)
+	for _, phid := range phids {
+		ph := t.ProvisionedHosts[phid]
+		if ph.Host == nil {
+			continue
+		}
+		pass, err := ph.ActualPassword()
+		if err != nil {
+			return nil, err
+		}
+		_, generated, _ := t.HostPassword(ph.Host)
+		c := &Credential{
+			Type:      CredentialTypeHost,
+			ID:        ph.Path(),
+			Name:      ph.Host.Hostname,
+			Password:  pass,
+			Generated: generated,
+		}
+		if generated {
+			c.Policy = ph.Host.Vars[VarKeyPasswordPolicy]
+		}
+		sheet.Credentials = append(sheet.Credentials, c)
+	}
+	return sheet, nil
+}
+
+// CredentialSheets creates the credential sheet of each team, ordered by team number
+func CredentialSheets(teams map[string]*Team, identities map[string]*Identity) ([]*CredentialSheet, error) {
+	sheets := []*CredentialSheet{}
+	for _, t := range teams {
+		sheet, err := t.CredentialSheet(identities)
+		if err != nil {
+			return nil, err
+		}
+		sheets = append(sheets, sheet)
+	}
+	sort.Slice(sheets, func(i, j int) bool { return sheets[i].TeamNumber < sheets[j].TeamNumber })
+	return sheets, nil
+}
+
+// WriteCredentialSheetsJSON renders credential sheets as indented JSON
+func WriteCredentialSheetsJSON(w io.Writer, sheets []*CredentialSheet) error {
+	data, err := json.MarshalIndent(sheets, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// WriteCredentialSheetsCSV renders credential sheets as a single CSV table with a row per team and credential
+func WriteCredentialSheetsCSV(w io.Writer, sheets []*CredentialSheet) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{
+		{"team", "type", "id", "name", "policy", "generated", "password"},
+	}
+	for _, s := range sheets {
+		for _, c := range s.Credentials {
+			rows = append(rows, []string{
+				strconv.Itoa(s.TeamNumber),
+				c.Type,
+				c.ID,
+				c.Name,
+				c.Policy,
+				strconv.FormatBool(c.Generated),
+				c.Password,
+			})
+		}
+	}
+	err := cw.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteCredentialSheetsMarkdown renders credential sheets as a markdown table per team, suitable for printing and
+// handing to each team
+func WriteCredentialSheetsMarkdown(w io.Writer, sheets []*CredentialSheet) error {
+	for _, s := range sheets {
+		fmt.Fprintf(w, "# Team %d Credentials\n\n", s.TeamNumber)
+		fmt.Fprintln(w, "| Type | Name | ID | Password |")
+		fmt.Fprintln(w, "| ---- | ---- | -- | -------- |")
+		for _, c := range s.Credentials {
+			fmt.Fprintf(w, "| %s | %s | %s | `%s` |\n", c.Type, c.Name, c.ID, c.Password)
+		}
+		_, err := fmt.Fprintln(w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,12 +2,45 @@ package core
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cespare/xxhash"
+	"github.com/miekg/dns"
 	"github.com/pkg/errors"
 )
 
+const (
+	// DNSTypeDynamic is the DNS type whose records are set through RFC 2136 dynamic updates against it's dns_servers
+	DNSTypeDynamic = `rfc2136`
+
+	// DNSTypeNSUpdate is an alias of DNSTypeDynamic
+	DNSTypeNSUpdate = `nsupdate`
+
+	// DNSConfigKeyTSIGKey is the DNS config key holding the name of the TSIG key dynamic updates are signed with
+	DNSConfigKeyTSIGKey = `tsig_key`
+
+	// DNSConfigKeyTSIGSecret is the DNS config key holding the base64 encoded TSIG secret
+	DNSConfigKeyTSIGSecret = `tsig_secret`
+
+	// DNSConfigKeyTSIGAlgorithm is the DNS config key holding the TSIG algorithm (hmac-sha256 by default)
+	DNSConfigKeyTSIGAlgorithm = `tsig_algorithm`
+
+	// DNSConfigKeyTTL is the DNS config key holding the TTL of records set through dynamic updates
+	DNSConfigKeyTTL = `ttl`
+
+	// DNSConfigKeyNet is the DNS config key holding the transport dynamic updates are sent over (udp or tcp)
+	DNSConfigKeyNet = `update_net`
+
+	// DefaultDNSRecordTTL is the TTL of records set through dynamic updates when the DNS config does not set one
+	DefaultDNSRecordTTL = 300
+
+	// DefaultDNSUpdateTimeout is how long laforge waits on a DNS server to answer a dynamic update
+	DefaultDNSUpdateTimeout = 10 * time.Second
+)
+
 // DNS represents a configurable type for the creation of competition DNS infrastructure
 //easyjson:json
 type DNS struct {
@@ -74,3 +107,91 @@ func (d *DNS) Swap(m Mergeable) error {
 	*d = *rawVal
 	return nil
 }
+
+// IsDynamic returns true if records are set through RFC 2136 dynamic updates against the DNS servers
+func (d *DNS) IsDynamic() bool {
+	switch strings.ToLower(d.Type) {
+	case DNSTypeDynamic, DNSTypeNSUpdate:
+		return true
+	default:
+		return false
+	}
+}
+
+// Servers returns the dns_servers as host:port addresses, defaulting to port 53
+func (d *DNS) Servers() []string {
+	servers := []string{}
+	for _, s := range d.DNSServers {
+		if _, _, err := net.SplitHostPort(s); err == nil {
+			servers = append(servers, s)
+			continue
+		}
+		servers = append(servers, net.JoinHostPort(strings.Trim(s, "[]"), "53"))
+	}
+	return servers
+}
+
+// TTL returns the TTL of records set through dynamic updates
+func (d *DNS) TTL() uint32 {
+	ttl, err := strconv.ParseUint(d.Config[DNSConfigKeyTTL], 10, 32)
+	if err != nil || ttl == 0 {
+		return DefaultDNSRecordTTL
+	}
+	return uint32(ttl)
+}
+
+// TSIG returns the fully qualified key name, algorithm and secret dynamic updates are signed with. The key name is
+// empty when TSIG has not been configured.
+func (d *DNS) TSIG() (string, string, string) {
+	key, secret := d.Config[DNSConfigKeyTSIGKey], d.Config[DNSConfigKeyTSIGSecret]
+	if key == "" || secret == "" {
+		return "", "", ""
+	}
+	algorithm := dns.HmacSHA256
+	if a := strings.ToLower(d.Config[DNSConfigKeyTSIGAlgorithm]); a != "" {
+		algorithm = dns.Fqdn(a)
+		if algorithm == "hmac-md5." {
+			algorithm = dns.HmacMD5
+		}
+	}
+	return dns.Fqdn(key), algorithm, secret
+}
+
+// Update atomically replaces the rrtype RRset of name in zone with rrs through an RFC 2136 dynamic update, signed with
+// TSIG when it is configured. Since the RRset is replaced rather than appended to, updates are idempotent. The servers
+// are tried in order until one of them accepts the update.
+func (d *DNS) Update(zone, name string, rrtype uint16, rrs []dns.RR) error {
+	servers := d.Servers()
+	if len(servers) == 0 {
+		return fmt.Errorf("dns %s has no dns_servers to send updates to", d.ID)
+	}
+
+	m := new(dns.Msg)
+	m.SetUpdate(dns.Fqdn(zone))
+	m.RemoveRRset([]dns.RR{&dns.ANY{Hdr: dns.RR_Header{Name: dns.Fqdn(name), Rrtype: rrtype}}})
+	m.Insert(rrs)
+
+	c := &dns.Client{
+		Net:     strings.ToLower(d.Config[DNSConfigKeyNet]),
+		Timeout: DefaultDNSUpdateTimeout,
+	}
+	if key, algorithm, secret := d.TSIG(); key != "" {
+		c.TsigSecret = map[string]string{key: secret}
+		m.SetTsig(key, algorithm, 300, time.Now().Unix())
+	}
+
+	errs := []string{}
+	for _, server := range servers {
+		r, _, err := c.Exchange(m, server)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", server, err))
+			continue
+		}
+		if r.Rcode != dns.RcodeSuccess {
+			errs = append(errs, fmt.Sprintf("%s: %s", server, dns.RcodeToString[r.Rcode]))
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("update of %s %s in zone %s failed on every dns server (%s)", name, dns.TypeToString[rrtype], zone, strings.Join(errs, ", "))
+}
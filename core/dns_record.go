@@ -1,11 +1,15 @@
 package core
 
 import (
+	"bytes"
 	"fmt"
 	"path"
+	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/cespare/xxhash"
+	"github.com/miekg/dns"
 	"github.com/pkg/errors"
 )
 
@@ -129,3 +133,94 @@ func (r *DNSRecord) Inherited() bool {
 func (r *DNSRecord) SetValue(val string) {
 	r.Values = append(r.Values, val)
 }
+
+// Render returns a copy of the record with it's zone, name and values rendered as templates against the provisioning
+// step, which allows per team zones such as "team{{ .Team.TeamNumber }}.{{ .Competition.DNS.RootDomain }}". Inherited
+// records take the provisioned host's IP address as their value.
+func (r *DNSRecord) Render(pstep *ProvisioningStep) (*DNSRecord, error) {
+	var err error
+	rendered := *r
+	rendered.Values = []string{}
+	rendered.Zone, err = renderDNSTemplate(r.ID, "zone", r.Zone, pstep)
+	if err != nil {
+		return nil, err
+	}
+	rendered.Name, err = renderDNSTemplate(r.ID, "name", r.Name, pstep)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range r.Values {
+		val, err := renderDNSTemplate(r.ID, "value", v, pstep)
+		if err != nil {
+			return nil, err
+		}
+		rendered.Values = append(rendered.Values, val)
+	}
+	if r.Inherited() && pstep.ProvisionedHost != nil {
+		rendered.SetValue(pstep.ProvisionedHost.SubnetIP)
+	}
+	return &rendered, nil
+}
+
+func renderDNSTemplate(id, field, text string, ctx interface{}) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New(field).Funcs(TemplateFuncLib).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", errors.Wrapf(err, "dns record %s has an invalid %s template", id, field)
+	}
+	buf := new(bytes.Buffer)
+	err = tmpl.Execute(buf, ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not render the %s of dns record %s", field, id)
+	}
+	return buf.String(), nil
+}
+
+// FQDN returns the fully qualified name of the record. Names are relative to the record's zone unless they end with a
+// dot or already fall within the zone, and a name of "@" refers to the zone itself.
+func (r *DNSRecord) FQDN() string {
+	zone := dns.Fqdn(r.Zone)
+	name := dns.Fqdn(r.Name)
+	switch {
+	case r.Name == "" || r.Name == "@":
+		return zone
+	case strings.HasSuffix(r.Name, "."), dns.IsSubDomain(zone, name):
+		return name
+	}
+	return dns.Fqdn(r.Name + "." + zone)
+}
+
+// RRType returns the DNS type of the record
+func (r *DNSRecord) RRType() (uint16, error) {
+	rrtype, ok := dns.StringToType[strings.ToUpper(r.Type)]
+	if !ok {
+		return 0, fmt.Errorf("dns record %s has an unknown type %s", r.ID, r.Type)
+	}
+	return rrtype, nil
+}
+
+// RRs returns the resource records of the record's values. Names within the values (such as CNAME targets) are
+// relative to the record's zone, like they would be in a zone file.
+func (r *DNSRecord) RRs(ttl uint32) ([]dns.RR, error) {
+	if _, err := r.RRType(); err != nil {
+		return nil, err
+	}
+	rrtype := strings.ToUpper(r.Type)
+	rrs := []dns.RR{}
+	for _, v := range r.Values {
+		if rrtype == "TXT" && !strings.HasPrefix(v, `"`) {
+			v = strconv.Quote(v)
+		}
+		rr, err := dns.NewRR(fmt.Sprintf("$ORIGIN %s\n%s %d IN %s %s", dns.Fqdn(r.Zone), r.FQDN(), ttl, rrtype, v))
+		if err != nil {
+			return nil, errors.Wrapf(err, "dns record %s has an invalid value %q", r.ID, v)
+		}
+		if rr == nil {
+			return nil, fmt.Errorf("dns record %s has an empty value", r.ID)
+		}
+		rrs = append(rrs, rr)
+	}
+	return rrs, nil
+}
@@ -1,34 +1,159 @@
 package core
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/gen0cide/laforge/core/cli"
+)
+
 // DNSRecordJob attempts to set a DNS record via NSUpdate
 // easyjson:json
 type DNSRecordJob struct {
 	GenericJob
 	Target    *ProvisioningStep `json:"-"`
 	DNSRecord *DNSRecord        `json:"-"`
+	Rendered  *DNSRecord        `json:"-"`
+}
+
+// CreateDNSRecordJob creates a new DNS record job for a Doer object with the Planner
+func CreateDNSRecordJob(id string, offset int, m *Metadata, pstep *ProvisioningStep) (*DNSRecordJob, error) {
+	j := &DNSRecordJob{
+		Target: pstep,
+	}
+	j.Metadata = m
+	j.MetadataID = m.GetID()
+	j.Offset = offset
+	j.JobID = id
+	j.DNSRecord = j.Target.DNSRecord
+	j.JobType = "dns_record_job"
+	j.CreatedAt = time.Now()
+	return j, nil
+}
+
+// skip returns true if the record should not be sent to the DNS servers
+func (j *DNSRecordJob) skip() bool {
+	return j.DNSRecord.Disabled || j.Target.Competition.DNS == nil || !j.Target.Competition.DNS.IsDynamic()
 }
 
 // CanProceed implements the Doer interface
-func (j *DNSRecordJob) CanProceed() error {
-	return nil
+func (j *DNSRecordJob) CanProceed(e chan error) {
+	if j.DNSRecord == nil || j.Target == nil {
+		e <- errors.New("cannot proceed with dns record job with nil targets")
+		return
+	}
+	if j.Target.Competition == nil {
+		e <- fmt.Errorf("dns record job %s has no competition", j.JobID)
+		return
+	}
+	if j.skip() {
+		e <- nil
+		return
+	}
+	if len(j.Target.Competition.DNS.Servers()) == 0 {
+		e <- fmt.Errorf("dns record job %s cannot proceed without dns_servers in the competition's dns block", j.JobID)
+		return
+	}
+	e <- nil
 }
 
 // EnsureDependencies implements the Doer interface
-func (j *DNSRecordJob) EnsureDependencies(l *Laforge) error {
-	return nil
+func (j *DNSRecordJob) EnsureDependencies(e chan error) {
+	if j.skip() {
+		e <- nil
+		return
+	}
+	rendered, err := j.DNSRecord.Render(j.Target)
+	if err != nil {
+		e <- err
+		return
+	}
+	if rendered.Zone == "" {
+		e <- fmt.Errorf("dns record %s has no zone", j.DNSRecord.ID)
+		return
+	}
+	if len(rendered.Values) == 0 {
+		e <- fmt.Errorf("dns record %s has no values", j.DNSRecord.ID)
+		return
+	}
+	j.Rendered = rendered
+	e <- nil
 }
 
 // Do implements the Doer interface
-func (j *DNSRecordJob) Do() error {
+func (j *DNSRecordJob) Do(e chan error) {
+	if j.skip() {
+		cli.Logger.Infof("Skipping %s: dns records are not dynamically updated for this competition", j.JobID)
+		e <- nil
+		return
+	}
+	d := j.Target.Competition.DNS
+	cli.Logger.Warnf("Performing DNS Record Job:\n  %s %s: %s %s %s\n  %s   %s: %s", color.HiBlueString(">>"), color.HiCyanString(ObjectTypeDNSRecord.String()), color.HiGreenString("%s", j.Rendered.FQDN()), color.HiGreenString("%s", strings.ToUpper(j.Rendered.Type)), color.HiGreenString("%s", strings.Join(j.Rendered.Values, ",")), color.HiBlueString(">>"), color.HiCyanString("DNS"), color.HiGreenString("%s", strings.Join(d.Servers(), ",")))
+	rrtype, err := j.Rendered.RRType()
+	if err != nil {
+		e <- err
+		return
+	}
+	rrs, err := j.Rendered.RRs(d.TTL())
+	if err != nil {
+		e <- err
+		return
+	}
+	err = d.Update(j.Rendered.Zone, j.Rendered.FQDN(), rrtype, rrs)
+	if err != nil {
+		cli.Logger.Errorf("Error executing %s: %v", j.JobID, err)
+		e <- err
+		return
+	}
+	e <- nil
+}
+
+// checkDNSRecordConflicts returns an error when the jobs of two provisioning steps would set the same name and type to
+// different values. Every update replaces the whole RRset, so a record whose zone and name are not templated per team
+// would otherwise be overwritten by each team in turn, leaving only the last one. Records which fail to render are
+// left for their job to report.
+func checkDNSRecordConflicts(jobs []*DNSRecordJob) error {
+	type owner struct {
+		jobID  string
+		values string
+	}
+	owners := map[string]owner{}
+	for _, j := range jobs {
+		if j.DNSRecord == nil || j.Target == nil || j.Target.Competition == nil || j.skip() {
+			continue
+		}
+		rendered, err := j.DNSRecord.Render(j.Target)
+		if err != nil || rendered.Zone == "" {
+			continue
+		}
+		values := append([]string{}, rendered.Values...)
+		sort.Strings(values)
+		key := fmt.Sprintf("%s %s", strings.ToLower(rendered.FQDN()), strings.ToUpper(rendered.Type))
+		o := owner{jobID: j.JobID, values: strings.Join(values, ",")}
+		prev, found := owners[key]
+		if !found {
+			owners[key] = o
+			continue
+		}
+		if prev.values != o.values {
+			return fmt.Errorf("dns record %s sets %s to different values in %s and %s (template the record's zone or name per team, e.g. with {{ .Team.TeamNumber }})", j.DNSRecord.ID, key, prev.jobID, o.jobID)
+		}
+	}
 	return nil
 }
 
 // CleanUp implements the Doer interface
-func (j *DNSRecordJob) CleanUp() error {
-	return nil
+func (j *DNSRecordJob) CleanUp(e chan error) {
+	e <- nil
 }
 
 // Finish implements the Doer interface
-func (j *DNSRecordJob) Finish() error {
-	return nil
+func (j *DNSRecordJob) Finish(e chan error) {
+	cli.Logger.Infof("Finished %s", j.JobID)
+	e <- nil
 }
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/miekg/dns"
+
+	"github.com/gen0cide/laforge/core/cli"
+	"github.com/gen0cide/laforge/dnsserver"
+)
+
+const testTSIGSecret = "c2VjcmV0c2VjcmV0c2VjcmV0"
+
+// testDNSRecordJob creates a job setting record for a host of the provided team
+func testDNSRecordJob(d *DNS, record *DNSRecord, team int, ip string) *DNSRecordJob {
+	pstep := &ProvisioningStep{
+		ID:              fmt.Sprintf("/envs/test/builds/local/teams/%d/networks/corp/hosts/web01/steps/1-www", team),
+		Team:            &Team{TeamNumber: team},
+		Competition:     &Competition{DNS: d},
+		ProvisionedHost: &ProvisionedHost{SubnetIP: ip},
+		DNSRecord:       record,
+	}
+	j := &DNSRecordJob{
+		Target:    pstep,
+		DNSRecord: record,
+	}
+	j.JobID = pstep.ID
+	return j
+}
+
+// runDNSRecordJob renders and sends the job's record, returning the first error
+func runDNSRecordJob(j *DNSRecordJob) error {
+	for _, phase := range []func(chan error){j.CanProceed, j.EnsureDependencies, j.Do} {
+		e := make(chan error, 1)
+		phase(e)
+		if err := <-e; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestDNSRecordJobUpdates(t *testing.T) {
+	cli.SetLogOutput(ioutil.Discard)
+	s := dnsserver.New("team1.example.com", "team2.example.com")
+	s.AddTSIGKey("laforge", testTSIGSecret)
+	err := s.Start("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	d := &DNS{
+		ID:         "/dns/test",
+		Type:       DNSTypeDynamic,
+		RootDomain: "example.com",
+		DNSServers: []string{s.Addr()},
+		Config: map[string]string{
+			DNSConfigKeyTSIGKey:    "laforge",
+			DNSConfigKeyTSIGSecret: testTSIGSecret,
+		},
+	}
+	www := &DNSRecord{ID: "/dns-records/www", Name: "www", Type: "a", Zone: "team{{ .Team.TeamNumber }}.{{ .Competition.DNS.RootDomain }}"}
+
+	j := testDNSRecordJob(d, www, 1, "10.0.1.5")
+	for n := 0; n < 2; n++ {
+		err = runDNSRecordJob(j)
+		if err != nil {
+			t.Fatalf("update %d failed: %v", n+1, err)
+		}
+		if vals := s.Values("www.team1.example.com", dns.TypeA); !reflect.DeepEqual(vals, []string{"10.0.1.5"}) {
+			t.Fatalf("after update %d, www.team1 has %v", n+1, vals)
+		}
+	}
+
+	j.Target.ProvisionedHost.SubnetIP = "10.0.1.6"
+	err = runDNSRecordJob(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vals := s.Values("www.team1.example.com", dns.TypeA); !reflect.DeepEqual(vals, []string{"10.0.1.6"}) {
+		t.Errorf("changed address did not replace the record: %v", vals)
+	}
+
+	txt := &DNSRecord{ID: "/dns-records/txt", Name: "@", Type: "TXT", Zone: "team1.example.com", Values: []string{"hello world", "v=spf1 -all"}}
+	err = runDNSRecordJob(testDNSRecordJob(d, txt, 1, "10.0.1.5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vals := s.Values("team1.example.com", dns.TypeTXT); len(vals) != 2 {
+		t.Errorf("expected 2 TXT records at the zone apex, got %v", vals)
+	}
+
+	d.Config[DNSConfigKeyTSIGSecret] = "d3JvbmdzZWNyZXR3cm9uZw=="
+	if err = runDNSRecordJob(j); err == nil {
+		t.Error("expected an update signed with the wrong secret to be rejected")
+	}
+	delete(d.Config, DNSConfigKeyTSIGKey)
+	if err = runDNSRecordJob(j); err == nil || !strings.Contains(err.Error(), "NOTAUTH") {
+		t.Errorf("expected an unsigned update to be rejected with NOTAUTH, got %v", err)
+	}
+
+	d.Config[DNSConfigKeyTSIGKey] = "laforge"
+	d.Config[DNSConfigKeyTSIGSecret] = testTSIGSecret
+	d.Config[DNSConfigKeyNet] = "tcp"
+	err = runDNSRecordJob(testDNSRecordJob(d, www, 2, "10.0.2.5"))
+	if err != nil {
+		t.Fatalf("update over TCP failed: %v", err)
+	}
+	if vals := s.Values("www.team2.example.com", dns.TypeA); !reflect.DeepEqual(vals, []string{"10.0.2.5"}) {
+		t.Errorf("update over TCP did not set www.team2: %v", vals)
+	}
+
+	err = runDNSRecordJob(testDNSRecordJob(d, www, 3, "10.0.3.5"))
+	if err == nil || !strings.Contains(err.Error(), "NOTAUTH") {
+		t.Errorf("expected an update outside of the server's zones to be rejected with NOTAUTH, got %v", err)
+	}
+}
+
+func TestDNSRecordConflicts(t *testing.T) {
+	d := &DNS{ID: "/dns/test", Type: DNSTypeDynamic, RootDomain: "example.com", DNSServers: []string{"127.0.0.1"}}
+	templated := &DNSRecord{ID: "/dns-records/www", Name: "www", Type: "A", Zone: "team{{ .Team.TeamNumber }}.example.com"}
+	shared := &DNSRecord{ID: "/dns-records/shared", Name: "www", Type: "A", Zone: "example.com"}
+	static := &DNSRecord{ID: "/dns-records/scoreboard", Name: "scoreboard", Type: "A", Zone: "example.com", Values: []string{"10.0.0.10"}}
+
+	jobs := []*DNSRecordJob{
+		testDNSRecordJob(d, templated, 1, "10.0.1.5"),
+		testDNSRecordJob(d, templated, 2, "10.0.2.5"),
+		testDNSRecordJob(d, static, 1, "10.0.1.5"),
+		testDNSRecordJob(d, static, 2, "10.0.2.5"),
+	}
+	if err := checkDNSRecordConflicts(jobs); err != nil {
+		t.Errorf("unexpected conflict: %v", err)
+	}
+
+	jobs = append(jobs, testDNSRecordJob(d, shared, 1, "10.0.1.5"), testDNSRecordJob(d, shared, 2, "10.0.2.5"))
+	if err := checkDNSRecordConflicts(jobs); err == nil {
+		t.Error("expected teams setting www.example.com to different addresses to conflict")
+	}
+}
@@ -0,0 +1,286 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gen0cide/laforge/core/cli"
+)
+
+const (
+	// EventTaskStarted is emitted when a task is picked up by the orchestrator
+	EventTaskStarted = `task_started`
+
+	// EventTaskSucceeded is emitted when every phase of a task has completed
+	EventTaskSucceeded = `task_succeeded`
+
+	// EventTaskFailed is emitted when a phase of a task has failed
+	EventTaskFailed = `task_failed`
+
+	// EventTaskSkipped is emitted when a task is not performed because a task it depends on failed
+	EventTaskSkipped = `task_skipped`
+
+	// EventPhaseStarted is emitted before each attempt at a phase of a task
+	EventPhaseStarted = `phase_started`
+
+	// EventPhaseFinished is emitted when an attempt at a phase of a task succeeds
+	EventPhaseFinished = `phase_finished`
+
+	// EventPhaseFailed is emitted when an attempt at a phase of a task fails
+	EventPhaseFailed = `phase_failed`
+
+	// PhaseCanProceed is the phase in which a task checks it's target is ready
+	PhaseCanProceed = `can_proceed`
+
+	// PhaseEnsureDependencies is the phase in which a task prepares what it needs on it's target
+	PhaseEnsureDependencies = `ensure_dependencies`
+
+	// PhaseDo is the phase in which a task does it's work
+	PhaseDo = `do`
+
+	// PhaseCleanUp is the phase in which a task removes anything it left on it's target
+	PhaseCleanUp = `clean_up`
+
+	// PhaseFinish is the phase in which a task is completed
+	PhaseFinish = `finish`
+)
+
+// EventQueueSize is how many events may be waiting to be sent before the orchestrator is slowed down
+var EventQueueSize = 1024
+
+// Event is a single structured JSON event about the progress of a plan's execution
+type Event struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	Node       string    `json:"node"`
+	TeamID     string    `json:"team_id,omitempty"`
+	HostID     string    `json:"host_id,omitempty"`
+	JobType    string    `json:"job_type,omitempty"`
+	Phase      string    `json:"phase,omitempty"`
+	Attempt    int       `json:"attempt,omitempty"`
+	DurationMS int64     `json:"duration_ms,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// EventSink is a destination for the JSON encoded events of an EventStream
+type EventSink interface {
+	// Send delivers a single JSON encoded event
+	Send(data []byte) error
+
+	// Close releases the sink's resources
+	Close() error
+}
+
+// NewEventSink creates the sink for a target, which is either a unix socket (unix:///path/to/socket), a webhook URL
+// (http:// or https://), or a file which events are appended to (file:///path/to/file or a plain path).
+func NewEventSink(target string) (EventSink, error) {
+	switch {
+	case strings.HasPrefix(target, "unix://"):
+		return &SocketSink{Path: strings.TrimPrefix(target, "unix://")}, nil
+	case strings.HasPrefix(target, "http://"), strings.HasPrefix(target, "https://"):
+		return &WebhookSink{
+			URL:    target,
+			Client: &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	}
+	filename := strings.TrimPrefix(target, "file://")
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not open event stream file %s", filename)
+	}
+	return &FileSink{File: f}, nil
+}
+
+// FileSink appends events to a file, one per line
+type FileSink struct {
+	File *os.File
+}
+
+// Send implements the EventSink interface
+func (s *FileSink) Send(data []byte) error {
+	_, err := s.File.Write(append(data, '\n'))
+	return err
+}
+
+// Close implements the EventSink interface
+func (s *FileSink) Close() error {
+	return s.File.Close()
+}
+
+// SocketSink writes events to a unix socket, one per line. The socket is dialed again if the connection is lost.
+type SocketSink struct {
+	Path string
+	conn net.Conn
+}
+
+// Send implements the EventSink interface
+func (s *SocketSink) Send(data []byte) error {
+	data = append(data, '\n')
+	for retried := false; ; retried = true {
+		if s.conn == nil {
+			conn, err := net.Dial("unix", s.Path)
+			if err != nil {
+				return err
+			}
+			s.conn = conn
+		}
+		_, err := s.conn.Write(data)
+		if err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+		if retried {
+			return err
+		}
+	}
+}
+
+// Close implements the EventSink interface
+func (s *SocketSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
+
+// WebhookSink POSTs each event to a URL
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+// Send implements the EventSink interface
+func (s *WebhookSink) Send(data []byte) error {
+	resp, err := s.Client.Post(s.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close implements the EventSink interface
+func (s *WebhookSink) Close() error {
+	return nil
+}
+
+// EventStream delivers events to it's sinks in the order they were emitted. Events are sent from a separate goroutine
+// so that a slow sink does not hold up the tasks of a plan.
+type EventStream struct {
+	Sinks   []EventSink
+	queue   chan []byte
+	done    chan struct{}
+	started sync.Map
+}
+
+// NewEventStream creates an event stream to the sinks of the provided targets (see NewEventSink)
+func NewEventStream(targets []string) (*EventStream, error) {
+	s := &EventStream{
+		Sinks: []EventSink{},
+		queue: make(chan []byte, EventQueueSize),
+		done:  make(chan struct{}),
+	}
+	for _, target := range targets {
+		sink, err := NewEventSink(target)
+		if err != nil {
+			for _, x := range s.Sinks {
+				x.Close()
+			}
+			return nil, err
+		}
+		s.Sinks = append(s.Sinks, sink)
+	}
+	go s.run()
+	return s, nil
+}
+
+// Emit queues an event to be sent to every sink
+func (s *EventStream) Emit(e *Event) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		cli.Logger.Errorf("Could not encode %s event for %s: %v", e.Type, e.Node, err)
+		return
+	}
+	s.queue <- data
+}
+
+// Close sends any queued events and closes the stream's sinks
+func (s *EventStream) Close() error {
+	close(s.queue)
+	<-s.done
+	var err error
+	for _, sink := range s.Sinks {
+		if cerr := sink.Close(); cerr != nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (s *EventStream) run() {
+	defer close(s.done)
+	for data := range s.queue {
+		for _, sink := range s.Sinks {
+			err := sink.Send(data)
+			if err != nil {
+				cli.Logger.Warnf("Could not send event to %T: %v", sink, err)
+			}
+		}
+	}
+}
+
+// emit sends an event about a task to the plan's event stream (if it has one)
+func (p *Plan) emit(id string, task Doer, e *Event) {
+	if p.Events == nil {
+		return
+	}
+	e.Time = time.Now().UTC()
+	e.Node = id
+	e.TeamID = PathPrefix(id, "teams")
+	e.HostID = PathPrefix(id, "hosts")
+	if task != nil {
+		e.JobType = task.GetJobType()
+	}
+
+	switch e.Type {
+	case EventTaskStarted:
+		p.Events.started.Store(id, e.Time)
+	case EventTaskSucceeded, EventTaskFailed:
+		if started, ok := p.Events.started.Load(id); ok {
+			e.DurationMS = int64(e.Time.Sub(started.(time.Time)) / time.Millisecond)
+		}
+	}
+	p.Events.Emit(e)
+}
+
+// perform runs one phase of a task in the task's timeout, emitting an event as the phase starts and ends
+func (p *Plan) perform(id string, task Doer, phase string, attempt int, f TimeoutFunc) error {
+	p.emit(id, task, &Event{Type: EventPhaseStarted, Phase: phase, Attempt: attempt})
+	start := time.Now()
+	err := PerformInTimeout(task.GetTimeout(), f)
+	e := &Event{
+		Type:       EventPhaseFinished,
+		Phase:      phase,
+		Attempt:    attempt,
+		DurationMS: int64(time.Since(start) / time.Millisecond),
+	}
+	if err != nil {
+		e.Type = EventPhaseFailed
+		e.Error = err.Error()
+	}
+	p.emit(id, task, e)
+	return err
+}
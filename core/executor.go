@@ -0,0 +1,69 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/gen0cide/laforge/core/cli"
+	"github.com/hashicorp/terraform/tfdiags"
+)
+
+// ErrRebootRequiresAgent is returned when a script or command which reboots the host would be performed over SSH or
+// WinRM, as only agents wait for the host to come back before moving on to the next step
+var ErrRebootRequiresAgent = errors.New("reboot and reboot_exit_code are only supported on hosts provisioned by an agent (see laforge controller --apply)")
+
+// Executor performs provisioning steps on a provisioned host through something other than it's Connection, such
+// as an agent that has connected to a controller in pull mode.
+type Executor interface {
+	// Handles returns true if the executor can currently perform steps on the provisioned host
+	Handles(ph *ProvisionedHost) bool
+
+	// Execute performs the provisioning step of a job, sending the result to e
+	Execute(job Doer, step *ProvisioningStep, e chan error)
+}
+
+// executorStep returns the provisioning step of a task if the plan's Executor should perform it
+func (p *Plan) executorStep(task Doer) (*ProvisioningStep, bool) {
+	if p.Executor == nil || task.GetMetadata() == nil {
+		return nil, false
+	}
+	pstep, ok := task.GetMetadata().Dependency.(*ProvisioningStep)
+	if !ok || pstep.ProvisionedHost == nil {
+		return nil, false
+	}
+	switch pstep.ProvisionerType {
+	case ObjectTypeActiveDirectory.String():
+		// active directory steps render credentials on the controller side and always go over WinRM
+		return nil, false
+	case ObjectTypeDNSRecord.String():
+		// dns records are sent to the DNS servers by laforge itself rather than from the host
+		return nil, false
+	}
+	return pstep, p.Executor.Handles(pstep.ProvisionedHost)
+}
+
+// orchestrateWithExecutor performs a task through the plan's Executor in place of the task's own connection based
+// lifecycle.
+func (p *Plan) orchestrateWithExecutor(id string, task Doer, pstep *ProvisioningStep, slot *Slot) (d tfdiags.Diagnostics) {
+	cli.Logger.Infof("Performing Task Through Executor: %s", id)
+	attempts, err := p.performWithRetries(id, task, slot, func(e chan error) {
+		p.Executor.Execute(task, pstep, e)
+	})
+	if err != nil {
+		cli.Logger.Errorf("Task %s failed after %d attempt(s): %v", id, len(attempts), err)
+		p.FailedNodes.Add(id)
+		p.record(id, task, JobStatusFailed, err)
+		d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task execution failure", tfdiags.FormatErrorPrefixed(err, id)))
+		err = p.WriteRevisionFile(task, RevStatusFailed, attempts...)
+		if err != nil {
+			d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task execution failure", tfdiags.FormatErrorPrefixed(err, id)))
+		}
+		return d
+	}
+	p.record(id, task, JobStatusSuccessful, nil)
+	cli.Logger.Infof("Marking ACTIVE In State: %s", id)
+	err = p.WriteRevisionFile(task, RevStatusActive, attempts...)
+	if err != nil {
+		d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task revision file writer failure", tfdiags.FormatErrorPrefixed(err, id)))
+	}
+	return d
+}
@@ -14,7 +14,11 @@ const (
 
 	// ExpertDifficulty is an expert difficulty finding
 	ExpertDifficulty
+)
 
+// Severities number from zero like difficulties, matching the integer severity a finding declares in it's
+// configuration.
+const (
 	// ZeroSeverity is a zero severity finding
 	ZeroSeverity FindingSeverity = iota
 
@@ -0,0 +1,257 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UncategorizedFinding is the category used to total findings which do not declare any tags
+const UncategorizedFinding = `uncategorized`
+
+// FindingRecord attaches a single finding to the provisioned host and team it was deployed to
+type FindingRecord struct {
+	TeamID            string            `json:"team_id"`
+	TeamNumber        int               `json:"team_number"`
+	ProvisionedHostID string            `json:"provisioned_host_id"`
+	NetworkID         string            `json:"network_id"`
+	HostID            string            `json:"host_id"`
+	Hostname          string            `json:"hostname"`
+	ProvisionerID     string            `json:"provisioner_id"`
+	Name              string            `json:"name"`
+	Description       string            `json:"description,omitempty"`
+	Severity          FindingSeverity   `json:"severity"`
+	Difficulty        FindingDifficulty `json:"difficulty"`
+	Categories        []string          `json:"categories"`
+	Score             int               `json:"score"`
+	SeverityString    string            `json:"severity_name"`
+	DifficultyString  string            `json:"difficulty_name"`
+}
+
+// FindingTotal is an aggregate of findings sharing the same host, team, or category
+type FindingTotal struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+	Score int    `json:"score"`
+}
+
+// FindingsReport is a scoring rollup of every finding deployed within a build
+type FindingsReport struct {
+	Findings   []*FindingRecord `json:"findings"`
+	ByHost     []*FindingTotal  `json:"by_host"`
+	ByTeam     []*FindingTotal  `json:"by_team"`
+	ByCategory []*FindingTotal  `json:"by_category"`
+	Total      *FindingTotal    `json:"total"`
+}
+
+// NewFindingsReport walks every provisioned host of the provided teams, attaching the findings of each
+// script provisioned onto the host to that host and it's team, and calculates the score totals.
+func NewFindingsReport(teams map[string]*Team) *FindingsReport {
+	r := &FindingsReport{
+		Findings:   []*FindingRecord{},
+		ByHost:     []*FindingTotal{},
+		ByTeam:     []*FindingTotal{},
+		ByCategory: []*FindingTotal{},
+		Total:      &FindingTotal{Key: "total"},
+	}
+
+	teamList := []*Team{}
+	for _, t := range teams {
+		teamList = append(teamList, t)
+	}
+	sort.Slice(teamList, func(i, j int) bool { return teamList[i].TeamNumber < teamList[j].TeamNumber })
+
+	categories := map[string]*FindingTotal{}
+	for _, team := range teamList {
+		teamTotal := &FindingTotal{Key: team.Path()}
+		pnids := []string{}
+		for pnid := range team.ProvisionedNetworks {
+			pnids = append(pnids, pnid)
+		}
+		sort.Strings(pnids)
+		for _, pnid := range pnids {
+			pn := team.ProvisionedNetworks[pnid]
+			phids := []string{}
+			for phid := range pn.ProvisionedHosts {
+				phids = append(phids, phid)
+			}
+			sort.Strings(phids)
+			for _, phid := range phids {
+				ph := pn.ProvisionedHosts[phid]
+				hostTotal := &FindingTotal{Key: ph.Path()}
+				for _, step := range ph.StepsByOffset {
+					script, ok := step.Provisioner.(*Script)
+					if !ok {
+						continue
+					}
+					for _, f := range script.Findings {
+						rec := &FindingRecord{
+							TeamID:            team.Path(),
+							TeamNumber:        team.TeamNumber,
+							ProvisionedHostID: ph.Path(),
+							NetworkID:         pn.Network.Path(),
+							HostID:            ph.Host.Path(),
+							Hostname:          ph.Host.Hostname,
+							ProvisionerID:     script.Path(),
+							Name:              f.Name,
+							Description:       f.Description,
+							Severity:          f.Severity,
+							Difficulty:        f.Difficulty,
+							Categories:        f.Tags,
+							Score:             f.TotalScore(),
+							SeverityString:    f.Severity.String(),
+							DifficultyString:  f.Difficulty.String(),
+						}
+						if len(rec.Categories) == 0 {
+							rec.Categories = []string{UncategorizedFinding}
+						}
+						r.Findings = append(r.Findings, rec)
+						hostTotal.add(rec.Score)
+						teamTotal.add(rec.Score)
+						r.Total.add(rec.Score)
+						for _, cat := range rec.Categories {
+							if _, ok := categories[cat]; !ok {
+								categories[cat] = &FindingTotal{Key: cat}
+							}
+							categories[cat].add(rec.Score)
+						}
+					}
+				}
+				r.ByHost = append(r.ByHost, hostTotal)
+			}
+		}
+		r.ByTeam = append(r.ByTeam, teamTotal)
+	}
+
+	for _, cat := range categories {
+		r.ByCategory = append(r.ByCategory, cat)
+	}
+	sort.Slice(r.ByCategory, func(i, j int) bool { return r.ByCategory[i].Key < r.ByCategory[j].Key })
+
+	return r
+}
+
+func (f *FindingTotal) add(score int) {
+	f.Count++
+	f.Score += score
+}
+
+// ToJSON renders the report as indented JSON
+func (r *FindingsReport) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// WriteCSV renders the report as a single CSV table. The record column denotes whether a row is a
+// finding or one of the host, team, category, or overall totals.
+func (r *FindingsReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{
+		{"record", "team", "provisioned_host", "hostname", "provisioner", "finding", "categories", "severity", "difficulty", "count", "score"},
+	}
+	for _, x := range r.Findings {
+		rows = append(rows, []string{
+			"finding",
+			strconv.Itoa(x.TeamNumber),
+			x.ProvisionedHostID,
+			x.Hostname,
+			x.ProvisionerID,
+			x.Name,
+			strings.Join(x.Categories, ";"),
+			strconv.Itoa(int(x.Severity)),
+			strconv.Itoa(int(x.Difficulty)),
+			"1",
+			strconv.Itoa(x.Score),
+		})
+	}
+	totals := []struct {
+		record string
+		list   []*FindingTotal
+	}{
+		{"host_total", r.ByHost},
+		{"team_total", r.ByTeam},
+		{"category_total", r.ByCategory},
+		{"total", []*FindingTotal{r.Total}},
+	}
+	for _, t := range totals {
+		for _, x := range t.list {
+			row := []string{t.record, "", "", "", "", "", "", "", "", strconv.Itoa(x.Count), strconv.Itoa(x.Score)}
+			switch t.record {
+			case "host_total":
+				row[2] = x.Key
+			case "team_total":
+				row[1] = x.Key
+			case "category_total":
+				row[6] = x.Key
+			}
+			rows = append(rows, row)
+		}
+	}
+	err := cw.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteMarkdown renders the report as a set of markdown tables suitable for a scoring rubric.
+func (r *FindingsReport) WriteMarkdown(w io.Writer) error {
+	lines := []string{
+		"# Findings",
+		"",
+		"| Team | Host | Hostname | Provisioner | Finding | Categories | Severity | Difficulty | Score |",
+		"| ---- | ---- | -------- | ----------- | ------- | ---------- | -------- | ---------- | ----- |",
+	}
+	for _, x := range r.Findings {
+		lines = append(lines, markdownRow(
+			strconv.Itoa(x.TeamNumber),
+			x.ProvisionedHostID,
+			x.Hostname,
+			x.ProvisionerID,
+			x.Name,
+			strings.Join(x.Categories, ", "),
+			x.SeverityString,
+			x.DifficultyString,
+			strconv.Itoa(x.Score),
+		))
+	}
+	sections := []struct {
+		title  string
+		header string
+		list   []*FindingTotal
+	}{
+		{"Totals By Host", "Host", r.ByHost},
+		{"Totals By Team", "Team", r.ByTeam},
+		{"Totals By Category", "Category", r.ByCategory},
+	}
+	for _, s := range sections {
+		lines = append(lines,
+			"",
+			fmt.Sprintf("## %s", s.title),
+			"",
+			fmt.Sprintf("| %s | Findings | Score |", s.header),
+			"| ---- | -------- | ----- |",
+		)
+		for _, x := range s.list {
+			lines = append(lines, markdownRow(x.Key, strconv.Itoa(x.Count), strconv.Itoa(x.Score)))
+		}
+	}
+	lines = append(lines,
+		"",
+		fmt.Sprintf("**Total:** %d findings worth %d points", r.Total.Count, r.Total.Score),
+		"",
+	)
+	_, err := io.WriteString(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func markdownRow(cols ...string) string {
+	for i, c := range cols {
+		cols[i] = strings.Replace(c, "|", `\|`, -1)
+	}
+	return fmt.Sprintf("| %s |", strings.Join(cols, " | "))
+}
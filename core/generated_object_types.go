@@ -91,12 +91,16 @@ const (
 	// Included is a classification of Laforge objects that help the compiler understand if the what hosts and networks should be included in an environment.
 	ObjectTypeIncluded
 
+	// ObjectTypeActiveDirectory is an enum value for type ObjectType.
+	// ActiveDirectory is a type of Laforge object that describes a provisioning step where identities are created as users within an Active Directory domain.
+	ObjectTypeActiveDirectory
+
 	_ObjectTypeNamespace = `github.com.gen0cide.laforge.core`
 	_ObjectTypePkgName   = `core`
 	_ObjectTypePkgPath   = `github.com/gen0cide/laforge/core`
 )
 
-const _ObjectTypeName = "unknownbuildcompetitioncommanddns_recordenvironmenthostidentitynetworkremote_filescriptteamuseramiprovisioned_hostprovisioned_networkprovisioning_stepconnectionincluded"
+const _ObjectTypeName = "unknownbuildcompetitioncommanddns_recordenvironmenthostidentitynetworkremote_filescriptteamuseramiprovisioned_hostprovisioned_networkprovisioning_stepconnectionincludedactive_directory"
 
 var _ObjectTypeNames = []string{
 	_ObjectTypeName[0:7],
@@ -118,6 +122,7 @@ var _ObjectTypeNames = []string{
 	_ObjectTypeName[133:150],
 	_ObjectTypeName[150:160],
 	_ObjectTypeName[160:168],
+	_ObjectTypeName[168:184],
 }
 
 // ObjectTypeNames returns a list of possible string values of ObjectType.
@@ -147,6 +152,7 @@ var _ObjectTypeMap = map[ObjectType]string{
 	16: _ObjectTypeName[133:150],
 	17: _ObjectTypeName[150:160],
 	18: _ObjectTypeName[160:168],
+	19: _ObjectTypeName[168:184],
 }
 
 // String implements the Stringer interface.
@@ -177,6 +183,7 @@ var _ObjectTypeKinds = map[ObjectType]string{
 	ObjectTypeProvisioningStep:   `core.ObjectTypeProvisioningStep`,
 	ObjectTypeConnection:         `core.ObjectTypeConnection`,
 	ObjectTypeIncluded:           `core.ObjectTypeIncluded`,
+	ObjectTypeActiveDirectory:    `core.ObjectTypeActiveDirectory`,
 }
 
 // Kind returns a string of the Go type for the given message.
@@ -212,6 +219,7 @@ var _ObjectTypeSources = map[ObjectType]string{
 	ObjectTypeProvisioningStep:   `github.com/gen0cide/laforge/core.ObjectTypeProvisioningStep`,
 	ObjectTypeConnection:         `github.com/gen0cide/laforge/core.ObjectTypeConnection`,
 	ObjectTypeIncluded:           `github.com/gen0cide/laforge/core.ObjectTypeIncluded`,
+	ObjectTypeActiveDirectory:    `github.com/gen0cide/laforge/core.ObjectTypeActiveDirectory`,
 }
 
 // Source returns an import path directly to the type.
@@ -247,6 +255,7 @@ var _ObjectTypePaths = map[ObjectType]string{
 	ObjectTypeProvisioningStep:   `github.com.gen0cide.laforge.core.object_type_provisioning_step`,
 	ObjectTypeConnection:         `github.com.gen0cide.laforge.core.object_type_connection`,
 	ObjectTypeIncluded:           `github.com.gen0cide.laforge.core.object_type_included`,
+	ObjectTypeActiveDirectory:    `github.com.gen0cide.laforge.core.object_type_active_directory`,
 }
 
 // Source returns an import path directly to the type.
@@ -297,6 +306,7 @@ var _ObjectTypeValue = map[string]ObjectType{
 	_ObjectTypeName[133:150]: 16,
 	_ObjectTypeName[150:160]: 17,
 	_ObjectTypeName[160:168]: 18,
+	_ObjectTypeName[168:184]: 19,
 }
 
 // ParseObjectType attempts to convert a string to a ObjectType
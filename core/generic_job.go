@@ -56,6 +56,7 @@ type Doer interface {
 	GetTimeout() int
 	GetMetadata() *Metadata
 	SetStatus(s JobStatus)
+	GetJobType() string
 	StandardOutput(line string)
 	StandardError(line string)
 	SetPlan(p *Plan)
@@ -63,6 +64,23 @@ type Doer interface {
 	CurrentStatus() JobStatus
 }
 
+// String implements the Stringer interface
+func (s JobStatus) String() string {
+	switch s {
+	case JobStatusPlanned:
+		return "PLANNED"
+	case JobStatusEnqueued:
+		return "ENQUEUED"
+	case JobStatusInProgress:
+		return "IN_PROGRESS"
+	case JobStatusFailed:
+		return "FAILED"
+	case JobStatusSuccessful:
+		return "SUCCESSFUL"
+	}
+	return "UNKNOWN"
+}
+
 // NewTimeoutExtension creates a wrapped error for the scheduler to retry at a later time
 func NewTimeoutExtension(err error) *ErrTimeoutExtension {
 	return &ErrTimeoutExtension{
@@ -169,6 +187,11 @@ func (j *GenericJob) CurrentStatus() JobStatus {
 	return j.Status
 }
 
+// GetJobType implements the Doer interface
+func (j *GenericJob) GetJobType() string {
+	return j.JobType
+}
+
 // SetStatus implements the Doer interface
 func (j *GenericJob) SetStatus(s JobStatus) {
 	j.Status = s
@@ -202,9 +225,15 @@ func (j *GenericJob) GetMetadata() *Metadata {
 // StandardOutput shows the standard output of a job's execution
 func (j *GenericJob) StandardOutput(line string) {
 	cli.Logger.Debugf("%s (STDOUT): %s", j.JobID, line)
+	if j.Plan != nil && j.Plan.Monitor != nil {
+		j.Plan.Monitor.TaskOutput(j.JobID, StreamStdout, line)
+	}
 }
 
 // StandardError prints the standard error of a jobs execution
 func (j *GenericJob) StandardError(line string) {
 	cli.Logger.Debugf("%s (STDERR): %s", j.JobID, line)
+	if j.Plan != nil && j.Plan.Monitor != nil {
+		j.Plan.Monitor.TaskOutput(j.JobID, StreamStderr, line)
+	}
 }
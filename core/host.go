@@ -20,31 +20,32 @@ const (
 // Host defines a configurable type for customizing host parameters within the infrastructure.
 //easyjson:json
 type Host struct {
-	ID               string                 `cty:"id" hcl:"id,label" json:"id,omitempty"`
-	Hostname         string                 `cty:"hostname" hcl:"hostname,attr" json:"hostname,omitempty"`
-	Description      string                 `cty:"description" hcl:"description,optional" json:"description,omitempty"`
-	OS               string                 `cty:"os" hcl:"os,attr" json:"os,omitempty"`
-	AMI              string                 `cty:"ami" hcl:"ami,optional" json:"ami,omitempty"`
-	LastOctet        int                    `cty:"last_octet" hcl:"last_octet,attr" json:"last_octet,omitempty"`
-	InstanceSize     string                 `cty:"instance_size" hcl:"instance_size,attr" json:"instance_size,omitempty"`
-	Disk             Disk                   `cty:"disk" hcl:"disk,block" json:"disk,omitempty"`
-	ProvisionSteps   []string               `cty:"provision_steps" hcl:"provision_steps,optional" json:"provision_steps,omitempty"`
-	ExposedTCPPorts  []string               `cty:"exposed_tcp_ports" hcl:"exposed_tcp_ports,optional" json:"exposed_tcp_ports,omitempty"`
-	ExposedUDPPorts  []string               `cty:"exposed_udp_ports" hcl:"exposed_udp_ports,optional" json:"exposed_udp_ports,omitempty"`
-	OverridePassword string                 `cty:"override_password" hcl:"override_password,optional" json:"override_password,omitempty"`
-	UserGroups       []string               `cty:"user_groups" hcl:"user_groups,optional" json:"user_groups,omitempty"`
-	Dependencies     []*HostDependency      `cty:"depends_on" hcl:"depends_on,block" json:"depends_on,omitempty"`
-	IO               *IO                    `cty:"io" hcl:"io,block" json:"io,omitempty"`
-	Vars             map[string]string      `cty:"vars" hcl:"vars,optional" json:"vars,omitempty"`
-	Tags             map[string]string      `cty:"tags" hcl:"tags,optional" json:"tags,omitempty"`
-	Maintainer       *User                  `cty:"maintainer" hcl:"maintainer,block" json:"maintainer,omitempty"`
-	OnConflict       *OnConflict            `cty:"on_conflict" hcl:"on_conflict,block" json:"on_conflict,omitempty"`
-	Provisioners     []Provisioner          `json:"-"`
-	Caller           Caller                 `json:"-"`
-	Scripts          map[string]*Script     `json:"-"`
-	Commands         map[string]*Command    `json:"-"`
-	RemoteFiles      map[string]*RemoteFile `json:"-"`
-	DNSRecords       map[string]*DNSRecord  `json:"-"`
+	ID                string                      `cty:"id" hcl:"id,label" json:"id,omitempty"`
+	Hostname          string                      `cty:"hostname" hcl:"hostname,attr" json:"hostname,omitempty"`
+	Description       string                      `cty:"description" hcl:"description,optional" json:"description,omitempty"`
+	OS                string                      `cty:"os" hcl:"os,attr" json:"os,omitempty"`
+	AMI               string                      `cty:"ami" hcl:"ami,optional" json:"ami,omitempty"`
+	LastOctet         int                         `cty:"last_octet" hcl:"last_octet,attr" json:"last_octet,omitempty"`
+	InstanceSize      string                      `cty:"instance_size" hcl:"instance_size,attr" json:"instance_size,omitempty"`
+	Disk              Disk                        `cty:"disk" hcl:"disk,block" json:"disk,omitempty"`
+	ProvisionSteps    []string                    `cty:"provision_steps" hcl:"provision_steps,optional" json:"provision_steps,omitempty"`
+	ExposedTCPPorts   []string                    `cty:"exposed_tcp_ports" hcl:"exposed_tcp_ports,optional" json:"exposed_tcp_ports,omitempty"`
+	ExposedUDPPorts   []string                    `cty:"exposed_udp_ports" hcl:"exposed_udp_ports,optional" json:"exposed_udp_ports,omitempty"`
+	OverridePassword  string                      `cty:"override_password" hcl:"override_password,optional" json:"override_password,omitempty"`
+	UserGroups        []string                    `cty:"user_groups" hcl:"user_groups,optional" json:"user_groups,omitempty"`
+	Dependencies      []*HostDependency           `cty:"depends_on" hcl:"depends_on,block" json:"depends_on,omitempty"`
+	IO                *IO                         `cty:"io" hcl:"io,block" json:"io,omitempty"`
+	Vars              map[string]string           `cty:"vars" hcl:"vars,optional" json:"vars,omitempty"`
+	Tags              map[string]string           `cty:"tags" hcl:"tags,optional" json:"tags,omitempty"`
+	Maintainer        *User                       `cty:"maintainer" hcl:"maintainer,block" json:"maintainer,omitempty"`
+	OnConflict        *OnConflict                 `cty:"on_conflict" hcl:"on_conflict,block" json:"on_conflict,omitempty"`
+	Provisioners      []Provisioner               `json:"-"`
+	Caller            Caller                      `json:"-"`
+	Scripts           map[string]*Script          `json:"-"`
+	Commands          map[string]*Command         `json:"-"`
+	RemoteFiles       map[string]*RemoteFile      `json:"-"`
+	DNSRecords        map[string]*DNSRecord       `json:"-"`
+	ActiveDirectories map[string]*ActiveDirectory `json:"-"`
 }
 
 // Disk is a configurable type for setting the root volume's disk size in GB
@@ -141,6 +142,9 @@ func (h *Host) GetProvisionersHash() uint64 {
 	for _, x := range h.RemoteFiles {
 		p = append(p, x.Hash())
 	}
+	for _, x := range h.ActiveDirectories {
+		p = append(p, x.Hash())
+	}
 	return p.Hash()
 }
 
@@ -278,6 +282,7 @@ func (h *Host) Index(base *Laforge) error {
 	h.Commands = map[string]*Command{}
 	h.RemoteFiles = map[string]*RemoteFile{}
 	h.DNSRecords = map[string]*DNSRecord{}
+	h.ActiveDirectories = map[string]*ActiveDirectory{}
 	iprov := map[string]string{}
 	h.Provisioners = []Provisioner{}
 
@@ -329,6 +334,24 @@ func (h *Host) Index(base *Laforge) error {
 			cli.Logger.Debugf("Resolved %T dependency %s for %s", record, record.ID, h.ID)
 		}
 	}
+	for name, ad := range base.ActiveDirectories {
+		status, found := iprov[name]
+		if !found {
+			continue
+		}
+		if status == ObjectTypeIncluded.String() {
+			if ad.DomainController != h.ID {
+				return fmt.Errorf("active directory %s has domain_controller %s and cannot be provisioned by host %s\n%s", ad.ID, ad.DomainController, h.ID, h.Caller.Error())
+			}
+			err := ad.Index(base)
+			if err != nil {
+				return err
+			}
+			h.ActiveDirectories[name] = ad
+			iprov[name] = ObjectTypeActiveDirectory.String()
+			cli.Logger.Debugf("Resolved %T dependency %s for %s", ad, ad.ID, h.ID)
+		}
+	}
 	for x, status := range iprov {
 		if status == ObjectTypeIncluded.String() {
 			return fmt.Errorf("unmet provision_step dependency %s for host %s\n%s", x, h.ID, h.Caller.Error())
@@ -344,6 +367,8 @@ func (h *Host) Index(base *Laforge) error {
 			h.Provisioners = append(h.Provisioners, h.RemoteFiles[s])
 		case ObjectTypeDNSRecord.String():
 			h.Provisioners = append(h.Provisioners, h.DNSRecords[s])
+		case ObjectTypeActiveDirectory.String():
+			h.Provisioners = append(h.Provisioners, h.ActiveDirectories[s])
 		default:
 			return fmt.Errorf("unmet provision_step dependency %s for host %s\n%s", s, h.ID, h.Caller.Error())
 		}
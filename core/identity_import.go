@@ -0,0 +1,483 @@
+package core
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+
+	"github.com/gen0cide/laforge/generators/creds"
+	"github.com/gen0cide/laforge/generators/identities"
+)
+
+const (
+	// IdentityVarUsername is the identity var holding the identity's account name
+	IdentityVarUsername = `username`
+
+	// IdentityVarDepartment is the identity var holding the department the identity works in
+	IdentityVarDepartment = `department`
+
+	// IdentityVarTitle is the identity var holding the identity's job title
+	IdentityVarTitle = `title`
+)
+
+var (
+	hclIdentifierRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_\-]*$`)
+
+	// csvIdentityColumns maps CSV column names (lower cased, without spaces or punctuation) of identity fields to the
+	// field they set
+	csvIdentityColumns = map[string]string{
+		"id":             "id",
+		"firstname":      "firstname",
+		"givenname":      "firstname",
+		"lastname":       "lastname",
+		"surname":        "lastname",
+		"sn":             "lastname",
+		"email":          "email",
+		"mail":           "email",
+		"emailaddress":   "email",
+		"password":       "password",
+		"description":    "description",
+		"avatarfile":     "avatar_file",
+		"username":       IdentityVarUsername,
+		"uid":            IdentityVarUsername,
+		"samaccountname": IdentityVarUsername,
+	}
+
+	// ldifIdentityAttributes maps the (lower cased) LDIF attributes that are imported to the identity field or var
+	// they set. Attributes which are not listed are ignored.
+	ldifIdentityAttributes = map[string]string{
+		"givenname":                  "firstname",
+		"sn":                         "lastname",
+		"cn":                         "cn",
+		"mail":                       "email",
+		"description":                "description",
+		"uid":                        IdentityVarUsername,
+		"samaccountname":             IdentityVarUsername,
+		"department":                 IdentityVarDepartment,
+		"title":                      IdentityVarTitle,
+		"telephonenumber":            "phone",
+		"mobile":                     "mobile",
+		"employeeid":                 "employee_id",
+		"employeenumber":             "employee_id",
+		"company":                    "company",
+		"physicaldeliveryofficename": "office",
+		"l":                          "city",
+	}
+
+	// ldifPersonClasses are the object classes of LDIF entries which are imported as identities
+	ldifPersonClasses = []string{"person", "organizationalperson", "inetorgperson", "user"}
+
+	// ldifExcludedClasses are object classes of entries which are never imported, even though they also carry a
+	// person class (Active Directory computer accounts are users as well)
+	ldifExcludedClasses = []string{"computer"}
+)
+
+// IdentityImporter turns generated people and records from other directories into identities, giving each a unique
+// username and an email address within the competition's domain
+type IdentityImporter struct {
+	Domain    string
+	generator *identities.Generator
+	ids       map[string]bool
+	usernames map[string]bool
+}
+
+// NewIdentityImporter creates an importer for the provided email domain. The seed determines generated identities,
+// so the same seed always produces the same people.
+func NewIdentityImporter(domain string, seed string) *IdentityImporter {
+	return &IdentityImporter{
+		Domain:    domain,
+		generator: identities.NewGenerator(creds.SeedFrom(seed)),
+		ids:       map[string]bool{},
+		usernames: map[string]bool{},
+	}
+}
+
+// Reserve prevents the importer from creating identities which collide with existing ones
+func (ii *IdentityImporter) Reserve(existing ...*Identity) {
+	for _, i := range existing {
+		ii.ids[i.ID] = true
+		if u := identities.Sanitize(i.Vars[IdentityVarUsername]); u != "" {
+			ii.reserveUsername(u)
+		}
+		ii.reserveUsername(i.Base())
+	}
+}
+
+// Generate creates count realistic identities with departments and titles in their vars
+func (ii *IdentityImporter) Generate(count int) ([]*Identity, error) {
+	if ii.Domain == "" {
+		return nil, errors.New("an email domain is required to generate identities")
+	}
+	ret := []*Identity{}
+	for _, p := range ii.generator.Generate(count) {
+		i := &Identity{
+			Firstname: p.Firstname,
+			Lastname:  p.Lastname,
+			Email:     p.Email(ii.Domain),
+			Vars: map[string]string{
+				IdentityVarUsername:   p.Username,
+				IdentityVarDepartment: p.Department,
+				IdentityVarTitle:      p.Title,
+			},
+			Tags: map[string]string{},
+		}
+		err := ii.finalize(i)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, i)
+	}
+	return ret, nil
+}
+
+// ImportCSV creates identities from a CSV file with a header row. Columns named after identity fields (firstname,
+// lastname, email, password, description, avatar_file and common aliases of them) set those fields, columns prefixed
+// with "tag." set tags, and every other column is set as a var.
+func (ii *IdentityImporter) ImportCSV(r io.Reader) ([]*Identity, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read CSV header")
+	}
+	columns := make([]string, len(header))
+	for idx, h := range header {
+		columns[idx] = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+	}
+
+	ret := []*Identity{}
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read CSV line %d", line)
+		}
+		i := &Identity{
+			Vars: map[string]string{},
+			Tags: map[string]string{},
+		}
+		for idx, val := range record {
+			val = strings.TrimSpace(val)
+			if idx >= len(columns) || val == "" {
+				continue
+			}
+			col := columns[idx]
+			if strings.HasPrefix(strings.ToLower(col), "tag.") {
+				i.Tags[strcase.ToSnake(col[4:])] = val
+				continue
+			}
+			key := strcase.ToSnake(col)
+			if field, ok := csvIdentityColumns[identities.Sanitize(col)]; ok {
+				key = field
+			}
+			ii.set(i, key, val)
+		}
+		err = ii.finalize(i)
+		if err != nil {
+			return nil, errors.Wrapf(err, "CSV line %d", line)
+		}
+		ret = append(ret, i)
+	}
+	return ret, nil
+}
+
+// ImportLDIF creates identities from the person entries of an LDIF export (such as one from ldapsearch or ldifde).
+// Names, email, account names and common organizational attributes are imported, while everything else is ignored.
+func (ii *IdentityImporter) ImportLDIF(r io.Reader) ([]*Identity, error) {
+	entries, err := parseLDIF(r)
+	if err != nil {
+		return nil, err
+	}
+	ret := []*Identity{}
+	for _, entry := range entries {
+		if !isLDIFPerson(entry) {
+			continue
+		}
+		i := &Identity{
+			Vars: map[string]string{},
+			Tags: map[string]string{},
+		}
+		cn := ""
+		for _, attr := range entry.attrs {
+			key, ok := ldifIdentityAttributes[attr.name]
+			if !ok {
+				continue
+			}
+			if key == "cn" {
+				cn = attr.value
+				continue
+			}
+			ii.set(i, key, attr.value)
+		}
+		if i.Firstname == "" && i.Lastname == "" && cn != "" {
+			parts := strings.Fields(cn)
+			i.Firstname = parts[0]
+			i.Lastname = strings.Join(parts[1:], " ")
+		}
+		err = ii.finalize(i)
+		if err != nil {
+			return nil, errors.Wrapf(err, "LDIF entry %s", entry.dn)
+		}
+		ret = append(ret, i)
+	}
+	return ret, nil
+}
+
+// set assigns an identity field, or a var when the key is not one of the identity's fields
+func (ii *IdentityImporter) set(i *Identity, key, val string) {
+	switch key {
+	case "id":
+		i.ID = val
+	case "firstname":
+		i.Firstname = val
+	case "lastname":
+		i.Lastname = val
+	case "email":
+		i.Email = val
+	case "password":
+		i.Password = val
+	case "description":
+		i.Description = val
+	case "avatar_file":
+		i.AvatarFile = val
+	default:
+		if _, ok := i.Vars[key]; !ok {
+			i.Vars[key] = val
+		}
+	}
+}
+
+// reserveUsername marks a username as taken by both the importer and it's generator
+func (ii *IdentityImporter) reserveUsername(username string) {
+	ii.usernames[username] = true
+	ii.generator.Reserve(username)
+}
+
+// finalize gives the identity a unique username, ID and email address. A username which was derived from the
+// identity's email or name is numbered when it is already taken, while one that was set explicitly is an error, since
+// renaming it would no longer match the directory it was imported from.
+func (ii *IdentityImporter) finalize(i *Identity) error {
+	if i.Firstname == "" && i.Lastname == "" {
+		return errors.New("identity has no name")
+	}
+	username := identities.Sanitize(i.Vars[IdentityVarUsername])
+	if username != "" {
+		if ii.usernames[username] {
+			return fmt.Errorf("username %s is used by more than one identity", i.Vars[IdentityVarUsername])
+		}
+	} else {
+		if i.Email != "" {
+			username = identities.Sanitize(strings.SplitN(i.Email, "@", 2)[0])
+		}
+		if username == "" {
+			username = identities.Username(i.Firstname, i.Lastname)
+		}
+		base := username
+		for n := 2; ii.usernames[username]; n++ {
+			username = fmt.Sprintf("%s%d", base, n)
+		}
+		i.Vars[IdentityVarUsername] = username
+	}
+	if i.Email == "" {
+		if ii.Domain == "" {
+			return fmt.Errorf("identity %s has no email address and no domain was provided", username)
+		}
+		i.Email = fmt.Sprintf("%s@%s", i.Vars[IdentityVarUsername], ii.Domain)
+	}
+
+	if i.ID == "" {
+		i.ID = path.Join("/identities", username)
+		for n := 2; ii.ids[i.ID]; n++ {
+			i.ID = path.Join("/identities", fmt.Sprintf("%s%d", username, n))
+		}
+	}
+	if ii.ids[i.ID] {
+		return fmt.Errorf("identity %s already exists", i.ID)
+	}
+	err := i.ValidatePath()
+	if err != nil {
+		return errors.Wrapf(err, "invalid identity ID %s", i.ID)
+	}
+	ii.ids[i.ID] = true
+	ii.reserveUsername(username)
+	ii.reserveUsername(i.Base())
+	return nil
+}
+
+// WriteIdentitiesHCL renders identities as laforge configuration, so that they can be included from a base or
+// environment like any other file of identity blocks
+func WriteIdentitiesHCL(w io.Writer, ids []*Identity) error {
+	bw := bufio.NewWriter(w)
+	for idx, i := range ids {
+		if idx > 0 {
+			fmt.Fprintln(bw)
+		}
+		fmt.Fprintf(bw, "identity %s {\n", QuotedHCLString(i.ID))
+		fmt.Fprintf(bw, "  firstname = %s\n", QuotedHCLString(i.Firstname))
+		fmt.Fprintf(bw, "  lastname = %s\n", QuotedHCLString(i.Lastname))
+		fmt.Fprintf(bw, "  email = %s\n", QuotedHCLString(i.Email))
+		fmt.Fprintf(bw, "  password = %s\n", QuotedHCLString(i.Password))
+		if i.Description != "" {
+			fmt.Fprintf(bw, "  description = %s\n", QuotedHCLString(i.Description))
+		}
+		if i.AvatarFile != "" {
+			fmt.Fprintf(bw, "  avatar_file = %s\n", QuotedHCLString(i.AvatarFile))
+		}
+		writeHCLMap(bw, "vars", i.Vars)
+		writeHCLMap(bw, "tags", i.Tags)
+		fmt.Fprintln(bw, "}")
+	}
+	return bw.Flush()
+}
+
+func writeHCLMap(w io.Writer, name string, m map[string]string) {
+	if len(m) == 0 {
+		return
+	}
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(w, "\n  %s = {\n", name)
+	for _, k := range keys {
+		key := k
+		if !hclIdentifierRegexp.MatchString(k) {
+			key = QuotedHCLString(k)
+		}
+		fmt.Fprintf(w, "    %s = %s\n", key, QuotedHCLString(m[k]))
+	}
+	fmt.Fprintln(w, "  }")
+}
+
+// DefinedIn reports whether the identity was defined in the provided file
+func (i *Identity) DefinedIn(filename string) bool {
+	if len(i.Caller) == 0 {
+		return false
+	}
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return false
+	}
+	for _, cf := range i.Caller {
+		if cf.CallerFile == abs {
+			return true
+		}
+	}
+	return false
+}
+
+type ldifAttr struct {
+	name  string
+	value string
+}
+
+type ldifEntry struct {
+	dn    string
+	attrs []ldifAttr
+}
+
+// parseLDIF reads the entries of an LDIF file, unfolding continued lines and decoding base64 values
+func parseLDIF(r io.Reader) ([]*ldifEntry, error) {
+	entries := []*ldifEntry{}
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, " ") && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read LDIF")
+	}
+
+	var entry *ldifEntry
+	for n, line := range lines {
+		if line == "" {
+			entry = nil
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		sep := strings.Index(line, ":")
+		if sep < 1 {
+			return nil, fmt.Errorf("invalid LDIF line %d: %q", n+1, line)
+		}
+		name := strings.ToLower(line[:sep])
+		value := line[sep+1:]
+		switch {
+		case strings.HasPrefix(value, ":"):
+			data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid base64 value for %s on LDIF line %d", name, n+1)
+			}
+			value = string(data)
+		case strings.HasPrefix(value, "<"):
+			// values loaded from URLs are not supported
+			continue
+		default:
+			value = strings.TrimSpace(value)
+		}
+
+		if entry == nil {
+			if name == "version" {
+				continue
+			}
+			entry = &ldifEntry{attrs: []ldifAttr{}}
+			entries = append(entries, entry)
+		}
+		if name == "dn" {
+			entry.dn = value
+			continue
+		}
+		entry.attrs = append(entry.attrs, ldifAttr{name: name, value: value})
+	}
+	return entries, nil
+}
+
+func isLDIFPerson(entry *ldifEntry) bool {
+	hasClass := false
+	isPerson := false
+	for _, attr := range entry.attrs {
+		if attr.name != "objectclass" {
+			continue
+		}
+		hasClass = true
+		for _, c := range ldifExcludedClasses {
+			if strings.EqualFold(attr.value, c) {
+				return false
+			}
+		}
+		for _, c := range ldifPersonClasses {
+			if strings.EqualFold(attr.value, c) {
+				isPerson = true
+			}
+		}
+	}
+	if hasClass {
+		return isPerson
+	}
+	for _, attr := range entry.attrs {
+		if attr.name == "sn" || attr.name == "givenname" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImportLDIFSkipsComputersAndDuplicates(t *testing.T) {
+	ldif := `dn: CN=John Smith,OU=Staff,DC=corp,DC=local
+objectClass: top
+objectClass: person
+objectClass: user
+givenName: John
+sn: Smith
+
+dn: CN=Jane Smith,OU=Staff,DC=corp,DC=local
+objectClass: user
+givenName: Jane
+sn: Smith
+
+dn: CN=WEB01,OU=Servers,DC=corp,DC=local
+objectClass: top
+objectClass: person
+objectClass: organizationalPerson
+objectClass: user
+objectClass: computer
+cn: WEB01
+sAMAccountName: WEB01$
+`
+	ids, err := NewIdentityImporter("corp.local", "test").ImportLDIF(strings.NewReader(ldif))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("expected 2 identities, got %d", len(ids))
+	}
+	if ids[0].Vars[IdentityVarUsername] != "jsmith" || ids[1].Vars[IdentityVarUsername] != "jsmith2" {
+		t.Errorf("derived usernames were not made unique: %s, %s", ids[0].Vars[IdentityVarUsername], ids[1].Vars[IdentityVarUsername])
+	}
+	if ids[1].Email != "jsmith2@corp.local" {
+		t.Errorf("unexpected email %s", ids[1].Email)
+	}
+
+	dupes := `dn: CN=John Smith,DC=corp,DC=local
+objectClass: user
+givenName: John
+sn: Smith
+sAMAccountName: jsmith
+
+dn: CN=Jim Smith,DC=corp,DC=local
+objectClass: user
+givenName: Jim
+sn: Smith
+sAMAccountName: JSmith
+`
+	_, err = NewIdentityImporter("corp.local", "test").ImportLDIF(strings.NewReader(dupes))
+	if err == nil {
+		t.Error("expected an error importing two entries with the same account name")
+	}
+}
@@ -0,0 +1,291 @@
+package core
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/buntdb"
+
+	"github.com/gen0cide/laforge/core/cli"
+)
+
+const (
+	// DBKeyJournalRun is the database key of the current apply's journal
+	DBKeyJournalRun = `/journal/run`
+
+	// DBKeyJournalNodePrefix prefixes the database keys of the journal entries of each node in the current apply
+	DBKeyJournalNodePrefix = `/journal/nodes/`
+)
+
+var (
+	// ErrNoJournal is thrown when there is no journal of a previous apply in the state DB
+	ErrNoJournal = errors.New("no apply journal exists in the state database")
+)
+
+// JournalTransition records a single change in a job's status
+type JournalTransition struct {
+	Status JobStatus `json:"status"`
+	Time   time.Time `json:"time"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// JournalEntry is the history of a single node of the plan during an apply
+type JournalEntry struct {
+	ID          string               `json:"id"`
+	Status      JobStatus            `json:"status"`
+	Transitions []*JournalTransition `json:"transitions"`
+}
+
+// Last returns the most recent transition of the entry
+func (e *JournalEntry) Last() *JournalTransition {
+	if len(e.Transitions) == 0 {
+		return nil
+	}
+	return e.Transitions[len(e.Transitions)-1]
+}
+
+// JournalRun describes the apply a journal belongs to
+type JournalRun struct {
+	Checksum  uint64      `json:"checksum"`
+	StartedAt time.Time   `json:"started_at"`
+	ResumedAt []time.Time `json:"resumed_at,omitempty"`
+	EndedAt   time.Time   `json:"ended_at,omitempty"`
+	Order     []string    `json:"order"`
+}
+
+// Finished returns true if the apply the journal belongs to ran to completion
+func (r *JournalRun) Finished() bool {
+	return !r.EndedAt.IsZero()
+}
+
+// Journal records the JobStatus transitions of every task of an apply in the state DB as they happen, so that an
+// interrupted apply can be resumed.
+type Journal struct {
+	sync.Mutex
+	DB      *buntdb.DB
+	Run     *JournalRun
+	Entries map[string]*JournalEntry
+}
+
+// NewJournal replaces any previous journal in the state DB with a new one for the plan, recording each of it's
+// tainted tasks as enqueued. The plan's tasks must already be set up.
+func NewJournal(db *buntdb.DB, plan *Plan, checksum uint64) (*Journal, error) {
+	j := &Journal{
+		DB: db,
+		Run: &JournalRun{
+			Checksum:  checksum,
+			StartedAt: time.Now().UTC(),
+			Order:     []string{},
+		},
+		Entries: map[string]*JournalEntry{},
+	}
+	now := time.Now().UTC()
+	for _, id := range plan.GlobalOrder {
+		if _, ok := plan.Tasks[id]; !ok || !plan.Tainted[id] {
+			continue
+		}
+		j.Run.Order = append(j.Run.Order, id)
+		j.Entries[id] = &JournalEntry{
+			ID:          id,
+			Status:      JobStatusEnqueued,
+			Transitions: []*JournalTransition{{Status: JobStatusEnqueued, Time: now}},
+		}
+	}
+
+	err := db.Update(func(tx *buntdb.Tx) error {
+		err := deleteJournal(tx)
+		if err != nil {
+			return err
+		}
+		err = setJSON(tx, DBKeyJournalRun, j.Run)
+		if err != nil {
+			return err
+		}
+		for id, entry := range j.Entries {
+			err = setJSON(tx, DBKeyJournalNodePrefix+id, entry)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not write the apply journal")
+	}
+	return j, nil
+}
+
+// LoadJournal loads the journal of the last apply from the state DB, returning ErrNoJournal if there is not one
+func LoadJournal(db *buntdb.DB) (*Journal, error) {
+	j := &Journal{
+		DB:      db,
+		Run:     &JournalRun{},
+		Entries: map[string]*JournalEntry{},
+	}
+	err := db.View(func(tx *buntdb.Tx) error {
+		val, err := tx.Get(DBKeyJournalRun)
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal([]byte(val), j.Run)
+		if err != nil {
+			return err
+		}
+		var ierr error
+		err = tx.AscendKeys(DBKeyJournalNodePrefix+"*", func(key, value string) bool {
+			entry := &JournalEntry{}
+			ierr = json.Unmarshal([]byte(value), entry)
+			if ierr != nil {
+				return false
+			}
+			j.Entries[entry.ID] = entry
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		return ierr
+	})
+	if err == buntdb.ErrNotFound {
+		return nil, ErrNoJournal
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the apply journal")
+	}
+	return j, nil
+}
+
+// Record appends a status transition to the journal entry of a node and persists it
+func (j *Journal) Record(id string, status JobStatus, cause error) error {
+	j.Lock()
+	defer j.Unlock()
+	entry, ok := j.Entries[id]
+	if !ok {
+		entry = &JournalEntry{ID: id, Transitions: []*JournalTransition{}}
+		j.Entries[id] = entry
+	}
+	t := &JournalTransition{
+		Status: status,
+		Time:   time.Now().UTC(),
+	}
+	if cause != nil {
+		t.Error = cause.Error()
+	}
+	entry.Status = status
+	entry.Transitions = append(entry.Transitions, t)
+	return j.DB.Update(func(tx *buntdb.Tx) error {
+		return setJSON(tx, DBKeyJournalNodePrefix+id, entry)
+	})
+}
+
+// Resume records that the apply of the journal was resumed
+func (j *Journal) Resume() error {
+	j.Lock()
+	defer j.Unlock()
+	j.Run.ResumedAt = append(j.Run.ResumedAt, time.Now().UTC())
+	return j.DB.Update(func(tx *buntdb.Tx) error {
+		return setJSON(tx, DBKeyJournalRun, j.Run)
+	})
+}
+
+// Finish records that the apply of the journal ran to completion
+func (j *Journal) Finish() error {
+	j.Lock()
+	defer j.Unlock()
+	j.Run.EndedAt = time.Now().UTC()
+	return j.DB.Update(func(tx *buntdb.Tx) error {
+		return setJSON(tx, DBKeyJournalRun, j.Run)
+	})
+}
+
+// Completed returns true if the node finished successfully in the journal's apply
+func (j *Journal) Completed(id string) bool {
+	j.Lock()
+	defer j.Unlock()
+	entry, ok := j.Entries[id]
+	return ok && entry.Status == JobStatusSuccessful
+}
+
+// EntriesByStatus returns the entries of the journal with the provided status, in the order of the apply
+func (j *Journal) EntriesByStatus(status JobStatus) []*JournalEntry {
+	j.Lock()
+	defer j.Unlock()
+	ret := []*JournalEntry{}
+	for _, id := range j.Run.Order {
+		if entry, ok := j.Entries[id]; ok && entry.Status == status {
+			ret = append(ret, entry)
+		}
+	}
+	return ret
+}
+
+// Resume removes the nodes that completed in the journal's apply from the plan's tainted nodes, so only the nodes that
+// were in progress, failed or never started are performed.
+func (p *Plan) Resume(j *Journal) int {
+	skipped := 0
+	for id := range p.Tainted {
+		if j.Completed(id) {
+			delete(p.Tainted, id)
+			skipped++
+		}
+	}
+	p.Journal = j
+	return skipped
+}
+
+// record records a status transition of a task in the plan's journal, and reports it to the plan's monitor and event
+// stream (if it has them)
+func (p *Plan) record(id string, task Doer, status JobStatus, cause error) {
+	task.SetStatus(status)
+	if p.Monitor != nil {
+		p.Monitor.TaskStatus(id, task, status, cause)
+	}
+	switch status {
+	case JobStatusInProgress:
+		p.emit(id, task, &Event{Type: EventTaskStarted})
+	case JobStatusSuccessful:
+		p.emit(id, task, &Event{Type: EventTaskSucceeded})
+	case JobStatusFailed:
+		e := &Event{Type: EventTaskFailed}
+		if cause != nil {
+			e.Error = cause.Error()
+		}
+		p.emit(id, task, e)
+	}
+	if p.Journal == nil {
+		return
+	}
+	err := p.Journal.Record(id, status, cause)
+	if err != nil {
+		cli.Logger.Errorf("Could not record %s as %s in the apply journal: %v", id, status, err)
+	}
+}
+
+func deleteJournal(tx *buntdb.Tx) error {
+	keys := []string{}
+	err := tx.AscendKeys("/journal/*", func(key, value string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		_, err = tx.Delete(key)
+		if err != nil && err != buntdb.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+func setJSON(tx *buntdb.Tx, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, _, err = tx.Set(key, string(data), nil)
+	return err
+}
@@ -1321,6 +1321,14 @@ func easyjsonA5722a85DecodeGithubComGen0cideLaforgeCore7(in *jlexer.Lexer, out *
 			out.Timeout = int(in.Int())
 		case "ignore_errors":
 			out.IgnoreErrors = bool(in.Bool())
+		case "reboot":
+			out.Reboot = bool(in.Bool())
+		case "reboot_exit_code":
+			out.RebootExitCode = int(in.Int())
+		case "retries":
+			out.Retries = int(in.Int())
+		case "retry_backoff":
+			out.RetryBackoff = int(in.Int())
 		case "args":
 			if in.IsNull() {
 				in.Skip()
@@ -1547,6 +1555,46 @@ func easyjsonA5722a85EncodeGithubComGen0cideLaforgeCore7(out *jwriter.Writer, in
 		}
 		out.Bool(bool(in.IgnoreErrors))
 	}
+	if in.Reboot {
+		const prefix string = ",\"reboot\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Reboot))
+	}
+	if in.RebootExitCode != 0 {
+		const prefix string = ",\"reboot_exit_code\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.RebootExitCode))
+	}
+	if in.Retries != 0 {
+		const prefix string = ",\"retries\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Retries))
+	}
+	if in.RetryBackoff != 0 {
+		const prefix string = ",\"retry_backoff\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.RetryBackoff))
+	}
 	if len(in.Args) != 0 {
 		const prefix string = ",\"args\":"
 		if first {
@@ -1841,6 +1889,10 @@ func easyjsonA5722a85DecodeGithubComGen0cideLaforgeCore9(in *jlexer.Lexer, out *
 			}
 		case "external_id":
 			out.ExternalID = string(in.String())
+		case "attempts":
+			if data := in.Raw(); in.Ok() {
+				in.AddError(json.Unmarshal(data, &out.Attempts))
+			}
 		case "vars":
 			if in.IsNull() {
 				in.Skip()
@@ -1926,6 +1978,11 @@ func easyjsonA5722a85EncodeGithubComGen0cideLaforgeCore9(out *jwriter.Writer, in
 			out.RawByte('}')
 		}
 	}
+	if len(in.Attempts) != 0 {
+		const prefix string = ",\"attempts\":"
+		out.RawString(prefix)
+		out.Raw(json.Marshal(in.Attempts))
+	}
 	out.RawByte('}')
 }
 
@@ -2170,6 +2227,10 @@ func easyjsonA5722a85DecodeGithubComGen0cideLaforgeCore11(in *jlexer.Lexer, out
 			out.Perms = string(in.String())
 		case "disabled":
 			out.Disabled = bool(in.Bool())
+		case "retries":
+			out.Retries = int(in.Int())
+		case "retry_backoff":
+			out.RetryBackoff = int(in.Int())
 		case "on_conflict":
 			if in.IsNull() {
 				in.Skip()
@@ -2310,6 +2371,26 @@ func easyjsonA5722a85EncodeGithubComGen0cideLaforgeCore11(out *jwriter.Writer, i
 		}
 		out.Bool(bool(in.Disabled))
 	}
+	if in.Retries != 0 {
+		const prefix string = ",\"retries\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Retries))
+	}
+	if in.RetryBackoff != 0 {
+		const prefix string = ",\"retry_backoff\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.RetryBackoff))
+	}
 	if in.OnConflict != nil {
 		const prefix string = ",\"on_conflict\":"
 		if first {
@@ -4483,6 +4564,10 @@ func easyjsonA5722a85DecodeGithubComGen0cideLaforgeCore23(in *jlexer.Lexer, out
 				}
 				in.Delim(']')
 			}
+		case "active_directories":
+			if data := in.Raw(); in.Ok() {
+				in.AddError(json.Unmarshal(data, &out.DefinedActiveDirectories))
+			}
 		case "environments":
 			if in.IsNull() {
 				in.Skip()
@@ -4700,6 +4785,37 @@ func easyjsonA5722a85DecodeGithubComGen0cideLaforgeCore23(in *jlexer.Lexer, out
 				}
 				in.Delim(']')
 			}
+		case "amis":
+			if in.IsNull() {
+				in.Skip()
+				out.DefinedAMIs = nil
+			} else {
+				in.Delim('[')
+				if out.DefinedAMIs == nil {
+					if !in.IsDelim(']') {
+						out.DefinedAMIs = make([]*AMI, 0, 8)
+					} else {
+						out.DefinedAMIs = []*AMI{}
+					}
+				} else {
+					out.DefinedAMIs = (out.DefinedAMIs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v188 *AMI
+					if in.IsNull() {
+						in.Skip()
+						v188 = nil
+					} else {
+						if v188 == nil {
+							v188 = new(AMI)
+						}
+						(*v188).UnmarshalEasyJSON(in)
+					}
+					out.DefinedAMIs = append(out.DefinedAMIs, v188)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -4891,6 +5007,11 @@ func easyjsonA5722a85EncodeGithubComGen0cideLaforgeCore23(out *jwriter.Writer, i
 			out.RawByte(']')
 		}
 	}
+	if len(in.DefinedActiveDirectories) != 0 {
+		const prefix string = ",\"active_directories\":"
+		out.RawString(prefix)
+		out.Raw(json.Marshal(in.DefinedActiveDirectories))
+	}
 	if len(in.DefinedEnvironments) != 0 {
 		const prefix string = ",\"environments\":"
 		out.RawString(prefix)
@@ -5017,6 +5138,24 @@ func easyjsonA5722a85EncodeGithubComGen0cideLaforgeCore23(out *jwriter.Writer, i
 			out.RawByte(']')
 		}
 	}
+	if len(in.DefinedAMIs) != 0 {
+		const prefix string = ",\"amis\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v189, v190 := range in.DefinedAMIs {
+				if v189 > 0 {
+					out.RawByte(',')
+				}
+				if v190 == nil {
+					out.RawString("null")
+				} else {
+					(*v190).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
 	out.RawByte('}')
 }
 
@@ -7916,6 +8055,10 @@ func easyjsonA5722a85DecodeGithubComGen0cideLaforgeCore39(in *jlexer.Lexer, out
 				}
 				in.Delim('}')
 			}
+		case "password_policies":
+			if data := in.Raw(); in.Ok() {
+				in.AddError(json.Unmarshal(data, &out.PasswordPolicies))
+			}
 		case "on_conflict":
 			if in.IsNull() {
 				in.Skip()
@@ -8010,6 +8153,16 @@ func easyjsonA5722a85EncodeGithubComGen0cideLaforgeCore39(out *jwriter.Writer, i
 			out.RawByte('}')
 		}
 	}
+	if len(in.PasswordPolicies) != 0 {
+		const prefix string = ",\"password_policies\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw(json.Marshal(in.PasswordPolicies))
+	}
 	if in.OnConflict != nil {
 		const prefix string = ",\"on_conflict\":"
 		if first {
@@ -8231,6 +8384,14 @@ func easyjsonA5722a85DecodeGithubComGen0cideLaforgeCore41(in *jlexer.Lexer, out
 			}
 		case "ignore_errors":
 			out.IgnoreErrors = bool(in.Bool())
+		case "reboot":
+			out.Reboot = bool(in.Bool())
+		case "reboot_exit_code":
+			out.RebootExitCode = int(in.Int())
+		case "retries":
+			out.Retries = int(in.Int())
+		case "retry_backoff":
+			out.RetryBackoff = int(in.Int())
 		case "cooldown":
 			out.Cooldown = int(in.Int())
 		case "timeout":
@@ -8386,6 +8547,46 @@ func easyjsonA5722a85EncodeGithubComGen0cideLaforgeCore41(out *jwriter.Writer, i
 		}
 		out.Bool(bool(in.IgnoreErrors))
 	}
+	if in.Reboot {
+		const prefix string = ",\"reboot\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Reboot))
+	}
+	if in.RebootExitCode != 0 {
+		const prefix string = ",\"reboot_exit_code\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.RebootExitCode))
+	}
+	if in.Retries != 0 {
+		const prefix string = ",\"retries\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Retries))
+	}
+	if in.RetryBackoff != 0 {
+		const prefix string = ",\"retry_backoff\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.RetryBackoff))
+	}
 	if in.Cooldown != 0 {
 		const prefix string = ",\"cooldown\":"
 		if first {
@@ -8896,6 +9097,16 @@ func easyjsonA5722a85DecodeGithubComGen0cideLaforgeCore44(in *jlexer.Lexer, out
 				}
 				(*out.Maintainer).UnmarshalEasyJSON(in)
 			}
+		case "on_conflict":
+			if in.IsNull() {
+				in.Skip()
+				out.OnConflict = nil
+			} else {
+				if out.OnConflict == nil {
+					out.OnConflict = new(OnConflict)
+				}
+				(*out.OnConflict).UnmarshalEasyJSON(in)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -9014,6 +9225,16 @@ func easyjsonA5722a85EncodeGithubComGen0cideLaforgeCore44(out *jwriter.Writer, i
 		}
 		(*in.Maintainer).MarshalEasyJSON(out)
 	}
+	if in.OnConflict != nil {
+		const prefix string = ",\"on_conflict\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.OnConflict).MarshalEasyJSON(out)
+	}
 	out.RawByte('}')
 }
 
@@ -0,0 +1,275 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ConfigKeyMaxParallel is the environment config key limiting how many tasks run at once across the whole plan
+	ConfigKeyMaxParallel = `max_parallel`
+
+	// ConfigKeyMaxParallelPerTeam is the environment config key limiting how many tasks of a single team run at once
+	ConfigKeyMaxParallelPerTeam = `max_parallel_per_team`
+
+	// ConfigKeyMaxParallelPerHost is the environment config key limiting how many tasks of a single host run at once
+	ConfigKeyMaxParallelPerHost = `max_parallel_per_host`
+
+	// ConfigKeyNetworkHeavyRate is the environment config key for how many network heavy tasks may start per second
+	ConfigKeyNetworkHeavyRate = `network_heavy_rate`
+
+	// ConfigKeyNetworkHeavyBurst is the environment config key for how many network heavy tasks may start at once
+	// before network_heavy_rate applies
+	ConfigKeyNetworkHeavyBurst = `network_heavy_burst`
+
+	// TagNetworkHeavy marks a script, command or remote file as network heavy when it's tags set it to "true"
+	TagNetworkHeavy = `network_heavy`
+)
+
+// ConcurrencyLimits describes how many tasks of a plan may run at once. A limit of 0 is unlimited.
+type ConcurrencyLimits struct {
+	Global       int     `json:"global"`
+	PerTeam      int     `json:"per_team"`
+	PerHost      int     `json:"per_host"`
+	NetworkRate  float64 `json:"network_heavy_rate"`
+	NetworkBurst int     `json:"network_heavy_burst"`
+}
+
+// LimitsFromConfig parses the concurrency limits out of an environment's config map
+func LimitsFromConfig(config map[string]string) (*ConcurrencyLimits, error) {
+	l := &ConcurrencyLimits{}
+	ints := map[string]*int{
+		ConfigKeyMaxParallel:        &l.Global,
+		ConfigKeyMaxParallelPerTeam: &l.PerTeam,
+		ConfigKeyMaxParallelPerHost: &l.PerHost,
+		ConfigKeyNetworkHeavyBurst:  &l.NetworkBurst,
+	}
+	for key, dst := range ints {
+		val, ok := config[key]
+		if !ok || val == "" {
+			continue
+		}
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 0 {
+			return nil, errors.Errorf("environment config %s must be a positive integer, not %q", key, val)
+		}
+		*dst = n
+	}
+	if val, ok := config[ConfigKeyNetworkHeavyRate]; ok && val != "" {
+		rate, err := strconv.ParseFloat(val, 64)
+		if err != nil || rate < 0 {
+			return nil, errors.Errorf("environment config %s must be a positive number, not %q", ConfigKeyNetworkHeavyRate, val)
+		}
+		l.NetworkRate = rate
+	}
+	if l.NetworkRate > 0 && l.NetworkBurst == 0 {
+		l.NetworkBurst = 1
+	}
+	return l, nil
+}
+
+// String implements the Stringer interface
+func (l *ConcurrencyLimits) String() string {
+	limit := func(n int) string {
+		if n == 0 {
+			return "unlimited"
+		}
+		return strconv.Itoa(n)
+	}
+	network := "unlimited"
+	if l.NetworkRate > 0 {
+		network = fmt.Sprintf("%g/s (burst %d)", l.NetworkRate, l.NetworkBurst)
+	}
+	return fmt.Sprintf("global=%s per_team=%s per_host=%s network_heavy=%s", limit(l.Global), limit(l.PerTeam), limit(l.PerHost), network)
+}
+
+// Limiter enforces a plan's ConcurrencyLimits as it's tasks are performed
+type Limiter struct {
+	sync.Mutex
+	Limits  *ConcurrencyLimits
+	global  chan struct{}
+	teams   map[string]chan struct{}
+	hosts   map[string]chan struct{}
+	network *TokenBucket
+}
+
+// NewLimiter creates a limiter for the provided limits
+func NewLimiter(limits *ConcurrencyLimits) *Limiter {
+	l := &Limiter{
+		Limits: limits,
+		teams:  map[string]chan struct{}{},
+		hosts:  map[string]chan struct{}{},
+	}
+	if limits.Global > 0 {
+		l.global = make(chan struct{}, limits.Global)
+	}
+	if limits.NetworkRate > 0 {
+		l.network = NewTokenBucket(limits.NetworkRate, limits.NetworkBurst)
+	}
+	return l
+}
+
+// Acquire blocks until the node may be performed under every limit, returning a function which releases it. The most
+// specific limits are acquired first so that a waiting node never holds a slot of the global limit.
+func (l *Limiter) Acquire(id string, heavy bool) func() {
+	held := []chan struct{}{}
+	if host := PathPrefix(id, "hosts"); host != "" {
+		held = append(held, l.semaphore(l.hosts, host, l.Limits.PerHost))
+	}
+	if team := PathPrefix(id, "teams"); team != "" {
+		held = append(held, l.semaphore(l.teams, team, l.Limits.PerTeam))
+	}
+	held = append(held, l.global)
+
+	acquired := []chan struct{}{}
+	for _, sem := range held {
+		if sem == nil {
+			continue
+		}
+		sem <- struct{}{}
+		acquired = append(acquired, sem)
+	}
+	if heavy && l.network != nil {
+		l.network.Wait()
+	}
+
+	return func() {
+		for _, sem := range acquired {
+			<-sem
+		}
+	}
+}
+
+// Slot is a node's hold on the limits of a Limiter, which can be given up while the node waits on something other
+// than it's own work (such as the backoff before a retry)
+type Slot struct {
+	limiter *Limiter
+	id      string
+	heavy   bool
+	release func()
+}
+
+// Hold acquires the limits for a node like Acquire, returning a slot which can be released and reacquired. A nil
+// limiter returns a nil slot, which holds nothing.
+func (l *Limiter) Hold(id string, heavy bool) *Slot {
+	if l == nil {
+		return nil
+	}
+	return &Slot{
+		limiter: l,
+		id:      id,
+		heavy:   heavy,
+		release: l.Acquire(id, heavy),
+	}
+}
+
+// Release gives up the limits held by the slot. Releasing a slot which is not held does nothing.
+func (s *Slot) Release() {
+	if s == nil || s.release == nil {
+		return
+	}
+	s.release()
+	s.release = nil
+}
+
+// Reacquire blocks until the limits given up by Release are held again
+func (s *Slot) Reacquire() {
+	if s == nil || s.release != nil {
+		return
+	}
+	s.release = s.limiter.Acquire(s.id, s.heavy)
+}
+
+// semaphore returns the semaphore of a team or host, creating it if this is the first time it was needed
+func (l *Limiter) semaphore(sems map[string]chan struct{}, key string, limit int) chan struct{} {
+	if limit == 0 {
+		return nil
+	}
+	l.Lock()
+	defer l.Unlock()
+	sem, ok := sems[key]
+	if !ok {
+		sem = make(chan struct{}, limit)
+		sems[key] = sem
+	}
+	return sem
+}
+
+// TokenBucket allows events at a steady rate per second with bursts of up to it's capacity
+type TokenBucket struct {
+	sync.Mutex
+	Rate     float64
+	Capacity int
+	tokens   float64
+	last     time.Time
+}
+
+// NewTokenBucket creates a full token bucket
+func NewTokenBucket(rate float64, capacity int) *TokenBucket {
+	return &TokenBucket{
+		Rate:     rate,
+		Capacity: capacity,
+		tokens:   float64(capacity),
+		last:     time.Now(),
+	}
+}
+
+// Wait blocks until a token is available and takes it
+func (b *TokenBucket) Wait() {
+	for {
+		b.Lock()
+		now := time.Now()
+		b.tokens = math.Min(float64(b.Capacity), b.tokens+now.Sub(b.last).Seconds()*b.Rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.Unlock()
+			return
+		}
+		wait := time.Duration((1 - b.tokens) / b.Rate * float64(time.Second))
+		b.Unlock()
+		time.Sleep(wait)
+	}
+}
+
+// PathPrefix returns the part of a laforge path up to and including the element following segment, or "" if the
+// path does not contain segment. PathPrefix("/envs/a/teams/1/networks/b", "teams") is "/envs/a/teams/1".
+func PathPrefix(id string, segment string) string {
+	parts := strings.Split(id, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if parts[i] == segment {
+			return strings.Join(parts[:i+2], "/")
+		}
+	}
+	return ""
+}
+
+// IsNetworkHeavy returns true if the node is a provisioning step who's provisioner is tagged as network heavy
+func IsNetworkHeavy(m *Metadata) bool {
+	if m == nil {
+		return false
+	}
+	pstep, ok := m.Dependency.(*ProvisioningStep)
+	if !ok {
+		return false
+	}
+	var tags map[string]string
+	switch p := pstep.Provisioner.(type) {
+	case *Script:
+		tags = p.Tags
+	case *Command:
+		tags = p.Tags
+	case *RemoteFile:
+		tags = p.Tags
+	case *ActiveDirectory:
+		tags = p.Tags
+	}
+	heavy, _ := strconv.ParseBool(tags[TagNetworkHeavy])
+	return heavy
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlotReleaseAndReacquire(t *testing.T) {
+	l := NewLimiter(&ConcurrencyLimits{Global: 1})
+	slot := l.Hold("/teams/1/hosts/a", false)
+
+	acquired := make(chan func())
+	go func() {
+		acquired <- l.Acquire("/teams/1/hosts/b", false)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("acquired the global limit while it was held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	slot.Release()
+	slot.Release()
+	var release func()
+	select {
+	case release = <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("released slot was not given up")
+	}
+
+	reacquired := make(chan struct{})
+	go func() {
+		slot.Reacquire()
+		close(reacquired)
+	}()
+	select {
+	case <-reacquired:
+		t.Fatal("reacquired the global limit while it was held")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	select {
+	case <-reacquired:
+	case <-time.After(time.Second):
+		t.Fatal("slot was not reacquired")
+	}
+	slot.Release()
+
+	var none *Limiter
+	none.Hold("/teams/1/hosts/a", true).Release()
+}
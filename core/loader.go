@@ -246,7 +246,8 @@ func NewMergeConflict(
 }
 
 // Bind enumerates the Loader's original file, performing recursive include loads to the
-// Loader, generating ASTs for each dependency. Bind finishes with a call to Deconflict().
+// Loader, generating ASTs for each dependency. Bind finishes with a call to Deconflict() and
+// resolves any secret references in the result.
 func (l *Loader) Bind() (*Laforge, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -295,7 +296,15 @@ func (l *Loader) Bind() (*Laforge, error) {
 		}
 		currLen = newLen
 	}
-	return l.Deconflict(filenames)
+	lf, err := l.Deconflict(filenames)
+	if err != nil {
+		return lf, err
+	}
+	err = lf.ResolveSecrets()
+	if err != nil {
+		return lf, err
+	}
+	return lf, nil
 }
 
 type transientContext struct {
@@ -312,6 +321,7 @@ type transientContext struct {
 	Team               *Team                `hcl:"team,block" json:"team,omitempty"`
 	User               *User                `hcl:"user,block" json:"user,omitempty"`
 	AMI                *AMI                 `hcl:"ami,block" json:"ami,omitempty"`
+	ActiveDirectory    *ActiveDirectory     `hcl:"active_directory,block" json:"active_directory,omitempty"`
 	ProvisionedHost    *ProvisionedHost     `hcl:"provisioned_host,block" json:"provisioned_host,omitempty"`
 	ProvisionedNetwork *ProvisionedNetwork  `hcl:"provisioned_network,block" json:"provisioned_network,omitempty"`
 	ProvisioningStep   *ProvisioningStep    `hcl:"provisioning_step,block" json:"provisioning_step,omitempty"`
@@ -338,6 +348,7 @@ type transientReverseContext struct {
 	User            []*User            `hcl:"user,block" json:"user,omitempty"`
 	ProvisionedHost []*ProvisionedHost `hcl:"provisioned_host,block" json:"provisioned_host,omitempty"`
 	AMI             []*AMI             `hcl:"ami,block" json:"ami,omitempty"`
+	ActiveDirectory []*ActiveDirectory `hcl:"active_directory,block" json:"active_directory,omitempty"`
 }
 
 // GetEmptyObjByName returns a pointer to an initialized, but empty object of the specified type (camel case).
@@ -369,6 +380,8 @@ func GetEmptyObjByName(s string) (interface{}, error) {
 		return &User{}, nil
 	case ObjectTypeAMI.String():
 		return &AMI{}, nil
+	case ObjectTypeActiveDirectory.String():
+		return &ActiveDirectory{}, nil
 	case ObjectTypeProvisionedHost.String():
 		return &ProvisionedHost{}, nil
 	case ObjectTypeProvisionedNetwork.String():
@@ -98,6 +98,9 @@ const (
 	// LFTypeScript is a constant to define object type when serialized
 	LFTypeScript LFType = `script`
 
+	// LFTypeActiveDirectory is a constant to define object type when serialized
+	LFTypeActiveDirectory LFType = `active_directory`
+
 	// LFTypeEnvironment is a constant to define object type when serialized
 	LFTypeEnvironment LFType = `environment`
 
@@ -150,6 +153,8 @@ func IsGlobalType(p string) bool {
 		return true
 	case LFTypeScript:
 		return true
+	case LFTypeActiveDirectory:
+		return true
 	case LFTypeEnvironment:
 		return false
 	case LFTypeTeam:
@@ -187,6 +192,8 @@ func TypeByPath(p string) LFType {
 		return LFTypeDNSRecord
 	case "files":
 		return LFTypeRemoteFile
+	case directoriesDir:
+		return LFTypeActiveDirectory
 	}
 
 	if path.Base(path.Dir(p)) == envsDir {
@@ -369,6 +376,8 @@ func (m *Metadata) FillColor() string {
 		return "navajowhite"
 	case LFTypeScript:
 		return "lightgoldenrod1"
+	case LFTypeActiveDirectory:
+		return "plum1"
 	case LFTypeEnvironment:
 		return "chartreuse"
 	case LFTypeBuild:
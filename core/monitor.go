@@ -0,0 +1,19 @@
+package core
+
+const (
+	// StreamStdout identifies the standard output of a task
+	StreamStdout = `stdout`
+
+	// StreamStderr identifies the standard error of a task
+	StreamStderr = `stderr`
+)
+
+// Monitor is notified of the progress of a plan's tasks as the plan is executed. Tasks are performed concurrently, so
+// implementations must be safe to call from multiple goroutines.
+type Monitor interface {
+	// TaskStatus is called every time a task's JobStatus changes, with the error that caused it to fail (if it did)
+	TaskStatus(id string, task Doer, status JobStatus, cause error)
+
+	// TaskOutput is called for every line a task writes to it's standard output or standard error
+	TaskOutput(id string, stream string, line string)
+}
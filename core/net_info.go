@@ -0,0 +1,272 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HostNetInfo is the network information of a single provisioned host
+type HostNetInfo struct {
+	TeamID            string           `json:"team_id"`
+	TeamNumber        int              `json:"team_number"`
+	NetworkID         string           `json:"network_id"`
+	NetworkName       string           `json:"network_name"`
+	ProvisionedHostID string           `json:"provisioned_host_id"`
+	HostID            string           `json:"host_id"`
+	Hostname          string           `json:"hostname"`
+	FQDN              string           `json:"fqdn,omitempty"`
+	OS                string           `json:"os"`
+	PrivateIP         string           `json:"private_ip"`
+	PublicIP          string           `json:"public_ip,omitempty"`
+	ExposedTCPPorts   []string         `json:"exposed_tcp_ports"`
+	ExposedUDPPorts   []string         `json:"exposed_udp_ports"`
+	ProvisionedHost   *ProvisionedHost `json:"-"`
+}
+
+// NetInfoReport is the network information of every provisioned host within a build
+type NetInfoReport struct {
+	Hosts []*HostNetInfo `json:"hosts"`
+}
+
+// NewNetInfoReport walks every provisioned host of the provided teams and resolves it's addresses. Addresses are
+// taken from the terraform outputs in the team's remote state (keyed by team ID) when one is available, matching the
+// <network>-<host> outputs so that a host in several networks reports the addresses of each, with the private
+// address falling back to the host's calculated subnet IP.
+func NewNetInfoReport(teams map[string]*Team, states map[string]*RemoteState) *NetInfoReport {
+	r := &NetInfoReport{
+		Hosts: []*HostNetInfo{},
+	}
+
+	teamList := []*Team{}
+	for _, t := range teams {
+		teamList = append(teamList, t)
+	}
+	sort.Slice(teamList, func(i, j int) bool { return teamList[i].TeamNumber < teamList[j].TeamNumber })
+
+	for _, team := range teamList {
+		state := states[team.Path()]
+		pnids := []string{}
+		for pnid := range team.ProvisionedNetworks {
+			pnids = append(pnids, pnid)
+		}
+		sort.Strings(pnids)
+		for _, pnid := range pnids {
+			pn := team.ProvisionedNetworks[pnid]
+			phids := []string{}
+			for phid := range pn.ProvisionedHosts {
+				phids = append(phids, phid)
+			}
+			sort.Strings(phids)
+			for _, phid := range phids {
+				ph := pn.ProvisionedHosts[phid]
+				info := &HostNetInfo{
+					TeamID:            team.Path(),
+					TeamNumber:        team.TeamNumber,
+					NetworkID:         pn.Network.Path(),
+					NetworkName:       pn.Network.Base(),
+					ProvisionedHostID: ph.Path(),
+					HostID:            ph.Host.Path(),
+					Hostname:          ph.Host.Hostname,
+					OS:                ph.Host.OS,
+					PrivateIP:         ph.SubnetIP,
+					ExposedTCPPorts:   ph.Host.ExposedTCPPorts,
+					ExposedUDPPorts:   ph.Host.ExposedUDPPorts,
+					ProvisionedHost:   ph,
+				}
+				if info.ExposedTCPPorts == nil {
+					info.ExposedTCPPorts = []string{}
+				}
+				if info.ExposedUDPPorts == nil {
+					info.ExposedUDPPorts = []string{}
+				}
+				if ph.Competition != nil && ph.Competition.DNS != nil && ph.Competition.DNS.RootDomain != "" {
+					info.FQDN = fmt.Sprintf("%s.%s", ph.Host.Hostname, ph.Competition.DNS.RootDomain)
+				}
+				if state != nil {
+					hi, ok := state.Hosts[fmt.Sprintf("%s-%s", pn.Network.Base(), ph.Host.Base())]
+					if !ok {
+						// states without per network outputs (such as those of the tfgcp builder) key them on the host
+						hi, ok = state.Hosts[ph.Host.Base()]
+					}
+					if ok {
+						if hi.PrivateIP != "" {
+							info.PrivateIP = hi.PrivateIP
+						}
+						info.PublicIP = hi.PublicIP
+					}
+				}
+				r.Hosts = append(r.Hosts, info)
+			}
+		}
+	}
+
+	return r
+}
+
+// ToJSON renders the report as indented JSON
+func (r *NetInfoReport) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// WriteCSV renders the report as a CSV table. Multiple ports are separated by semicolons.
+func (r *NetInfoReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{
+		{"team", "network", "provisioned_host", "hostname", "fqdn", "os", "private_ip", "public_ip", "tcp_ports", "udp_ports"},
+	}
+	for _, x := range r.Hosts {
+		rows = append(rows, x.TableInfo())
+	}
+	err := cw.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// TableInfo is a helper function for pretty printing host network information
+func (h *HostNetInfo) TableInfo() []string {
+	return []string{
+		strconv.Itoa(h.TeamNumber),
+		h.NetworkName,
+		h.ProvisionedHostID,
+		h.Hostname,
+		h.FQDN,
+		h.OS,
+		h.PrivateIP,
+		h.PublicIP,
+		strings.Join(h.ExposedTCPPorts, ";"),
+		strings.Join(h.ExposedUDPPorts, ";"),
+	}
+}
+
+// Address returns the public IP of the host if it has one, otherwise it's private IP
+func (h *HostNetInfo) Address() string {
+	if h.PublicIP != "" {
+		return h.PublicIP
+	}
+	return h.PrivateIP
+}
+
+// IsWindows returns true if the host is running a Windows operating system
+func (h *HostNetInfo) IsWindows() bool {
+	return h.ProvisionedHost != nil && h.ProvisionedHost.Host.IsWindows()
+}
+
+// Teams returns the distinct team numbers within the report in order
+func (r *NetInfoReport) Teams() []int {
+	seen := map[int]bool{}
+	teams := []int{}
+	for _, x := range r.Hosts {
+		if !seen[x.TeamNumber] {
+			seen[x.TeamNumber] = true
+			teams = append(teams, x.TeamNumber)
+		}
+	}
+	sort.Ints(teams)
+	return teams
+}
+
+// WriteEtcHosts renders the report as an /etc/hosts file. Every host is aliased as t<team>-<hostname> so that
+// multiple teams can share a single file. When the report only contains a single team, the plain hostname
+// and FQDN are added as aliases as well.
+func (r *NetInfoReport) WriteEtcHosts(w io.Writer) error {
+	single := len(r.Teams()) == 1
+	lines := []string{
+		"# generated by laforge",
+	}
+	team := -1
+	for _, x := range r.Hosts {
+		if x.TeamNumber != team {
+			team = x.TeamNumber
+			lines = append(lines, "", fmt.Sprintf("# team %d", team))
+		}
+		names := []string{fmt.Sprintf("t%d-%s", x.TeamNumber, x.Hostname)}
+		if single {
+			if x.FQDN != "" {
+				names = append(names, x.FQDN)
+			}
+			names = append(names, x.Hostname)
+		}
+		lines = append(lines, fmt.Sprintf("%-15s %s", x.Address(), strings.Join(names, " ")))
+	}
+	lines = append(lines, "")
+	_, err := io.WriteString(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// WriteAnsibleInventory renders the report as an INI style Ansible inventory. Hosts are grouped by team, by team
+// network, and by operating system family. Linux hosts use the build's SSH key located at keyfile, while Windows
+// hosts connect with WinRM using the host's password.
+func (r *NetInfoReport) WriteAnsibleInventory(w io.Writer, keyfile string) error {
+	groups := map[string][]string{}
+	groupNames := []string{}
+	addToGroup := func(group, entry string) {
+		if _, ok := groups[group]; !ok {
+			groupNames = append(groupNames, group)
+		}
+		groups[group] = append(groups[group], entry)
+	}
+
+	for _, x := range r.Hosts {
+		name := fmt.Sprintf("t%d-%s", x.TeamNumber, x.Hostname)
+		vars := []string{
+			fmt.Sprintf("ansible_host=%s", x.Address()),
+			fmt.Sprintf("laforge_team=%d", x.TeamNumber),
+			fmt.Sprintf("laforge_hostname=%s", x.Hostname),
+			fmt.Sprintf("laforge_private_ip=%s", x.PrivateIP),
+		}
+		if x.IsWindows() {
+			pass, err := x.ProvisionedHost.ActualPassword()
+			if err != nil {
+				return err
+			}
+			vars = append(vars,
+				"ansible_connection=winrm",
+				"ansible_port=5985",
+				"ansible_user=Administrator",
+				fmt.Sprintf("ansible_password=%s", strconv.Quote(pass)),
+				"ansible_winrm_transport=ntlm",
+				"ansible_winrm_server_cert_validation=ignore",
+			)
+		} else {
+			vars = append(vars,
+				"ansible_user=root",
+				fmt.Sprintf("ansible_ssh_private_key_file=%s", keyfile),
+			)
+		}
+		entry := fmt.Sprintf("%s %s", name, strings.Join(vars, " "))
+
+		addToGroup(fmt.Sprintf("team%d", x.TeamNumber), entry)
+		addToGroup(fmt.Sprintf("team%d_%s", x.TeamNumber, ansibleGroupName(x.NetworkName)), name)
+		if x.IsWindows() {
+			addToGroup("windows", name)
+		} else {
+			addToGroup("linux", name)
+		}
+	}
+
+	lines := []string{"# generated by laforge"}
+	for _, g := range groupNames {
+		lines = append(lines, "", fmt.Sprintf("[%s]", g))
+		lines = append(lines, groups[g]...)
+	}
+	lines = append(lines, "")
+	_, err := io.WriteString(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func ansibleGroupName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestNetInfoReportMultiHomedHost(t *testing.T) {
+	team := &Team{ID: "/envs/test/builds/local/teams/0", TeamNumber: 0, ProvisionedNetworks: map[string]*ProvisionedNetwork{}}
+	host := &Host{ID: "/hosts/web01", Hostname: "web01"}
+	for _, net := range []string{"corp", "dmz"} {
+		pn := &ProvisionedNetwork{
+			ID:               team.ID + "/networks/" + net,
+			Network:          &Network{ID: "/networks/" + net},
+			ProvisionedHosts: map[string]*ProvisionedHost{},
+		}
+		ph := &ProvisionedHost{ID: pn.ID + "/hosts/web01", Host: host}
+		pn.ProvisionedHosts[ph.ID] = ph
+		team.ProvisionedNetworks[pn.ID] = pn
+	}
+
+	rs := &RemoteState{Team: team, Hosts: map[string]*HostTableInfo{}}
+	rs.hostInfo("corp-web01").PrivateIP = "10.0.1.10"
+	rs.hostInfo("dmz-web01").PrivateIP = "10.0.2.10"
+	r := NewNetInfoReport(map[string]*Team{team.ID: team}, map[string]*RemoteState{team.Path(): rs})
+
+	addrs := map[string]string{}
+	for _, h := range r.Hosts {
+		addrs[h.NetworkName] = h.PrivateIP
+	}
+	if addrs["corp"] != "10.0.1.10" || addrs["dmz"] != "10.0.2.10" {
+		t.Errorf("expected each network to report it's own address, got %v", addrs)
+	}
+}
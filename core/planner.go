@@ -46,6 +46,11 @@ type Plan struct {
 	Walker            *dag.Walker       `json:"-"`
 	Errored           bool              `json:"-"`
 	FailedNodes       *dag.Set          `json:"-"`
+	Executor          Executor          `json:"-"`
+	Journal           *Journal          `json:"-"`
+	Limiter           *Limiter          `json:"-"`
+	Monitor           Monitor           `json:"-"`
+	Events            *EventStream      `json:"-"`
 }
 
 // NewEmptyPlan returns an initialized, but empty plan object.
@@ -121,6 +126,7 @@ func (p *Plan) SetupTasks() error {
 	if p.Tasks == nil {
 		p.Tasks = map[string]Doer{}
 	}
+	dnsJobs := []*DNSRecordJob{}
 	for id, x := range p.GlobalOrder {
 		cli.Logger.Debugf("STEP: %s", x)
 		metaobj := p.Graph.Metastore[x]
@@ -150,6 +156,19 @@ func (p *Plan) SetupTasks() error {
 					return err
 				}
 				job = j
+			case ObjectTypeDNSRecord.String():
+				j, err := CreateDNSRecordJob(x, id, metaobj, pstep)
+				if err != nil {
+					return err
+				}
+				dnsJobs = append(dnsJobs, j)
+				job = j
+			case ObjectTypeActiveDirectory.String():
+				j, err := CreateActiveDirectoryJob(x, id, metaobj, pstep)
+				if err != nil {
+					return err
+				}
+				job = j
 			default:
 				continue
 			}
@@ -161,16 +180,17 @@ func (p *Plan) SetupTasks() error {
 			p.Tasks[x] = job
 		}
 	}
-	return nil
+	return checkDNSRecordConflicts(dnsJobs)
 }
 
 // WriteRevisionFile writes a deng revision file
-func (p *Plan) WriteRevisionFile(d Doer, status RevStatus) error {
+func (p *Plan) WriteRevisionFile(d Doer, status RevStatus, attempts ...*Attempt) error {
 	filename := fmt.Sprintf(".%s.pstep.lfrevision", filepath.Base(d.GetTargetID()))
 	pathToRevFile := filepath.Join(p.Base.BaseDir, filepath.Dir(d.GetTargetID()), filename)
 	rev := d.GetMetadata().ToRevision()
 	rev.Touch()
 	rev.Status = status
+	rev.Attempts = attempts
 	err := ioutil.WriteFile(pathToRevFile, []byte(rev.ToJSONString()), 0644)
 	if err != nil {
 		return err
@@ -206,12 +226,13 @@ func (p *Plan) Orchestrator(v dag.Vertex) (d tfdiags.Diagnostics) {
 	if err != nil {
 		cli.Logger.Errorf("Ancestor Search Error: %v", err)
 		p.Errored = true
-		d.Append(tfdiags.Sourceless(tfdiags.Error, "descendent acquisition failed", tfdiags.FormatErrorPrefixed(err, id)))
+		d = d.Append(tfdiags.Sourceless(tfdiags.Error, "descendent acquisition failed", tfdiags.FormatErrorPrefixed(err, id)))
 		return d
 	}
 	if p.FailedNodes.Intersection(descendents).Len() > 0 {
 		cli.Logger.Errorf("Node %s has failed lineage. Skipping execution.", id)
-		d.Append(tfdiags.Sourceless(tfdiags.Error, "node has tainted lineage, skipping", id))
+		p.emit(id, p.Tasks[id], &Event{Type: EventTaskSkipped, Error: "node has failed lineage"})
+		d = d.Append(tfdiags.Sourceless(tfdiags.Error, "node has tainted lineage, skipping", id))
 		return d
 	}
 	task, found := p.Tasks[id]
@@ -221,71 +242,83 @@ func (p *Plan) Orchestrator(v dag.Vertex) (d tfdiags.Diagnostics) {
 		// d.Append(tfdiags.Sourceless(tfdiags.Error, "missing laforge job object for node", id))
 		return d
 	}
+	slot := p.Limiter.Hold(id, IsNetworkHeavy(task.GetMetadata()))
+	defer slot.Release()
 	task.SetBase(p.Base)
+	p.record(id, task, JobStatusInProgress, nil)
+	if pstep, ok := p.executorStep(task); ok {
+		return p.orchestrateWithExecutor(id, task, pstep, slot)
+	}
 	cli.Logger.Infof("Checking State: %s", id)
-	err = PerformInTimeout(task.GetTimeout(), task.CanProceed)
+	err = p.perform(id, task, PhaseCanProceed, 1, task.CanProceed)
 	if err != nil {
 		cli.Logger.Errorf("Task %s could not proceed: %v", id, err)
 		p.FailedNodes.Add(v)
-		d.Append(tfdiags.Sourceless(tfdiags.Error, "task preparation failure", tfdiags.FormatErrorPrefixed(err, id)))
+		p.record(id, task, JobStatusFailed, err)
+		d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task preparation failure", tfdiags.FormatErrorPrefixed(err, id)))
 		err = p.WriteRevisionFile(task, RevStatusFailed)
 		if err != nil {
-			d.Append(tfdiags.Sourceless(tfdiags.Error, "task cleanup failure", tfdiags.FormatErrorPrefixed(err, id)))
+			d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task cleanup failure", tfdiags.FormatErrorPrefixed(err, id)))
 		}
 		return d
 	}
 	cli.Logger.Infof("Ensuring Dependencies: %s", id)
-	err = PerformInTimeout(task.GetTimeout(), task.EnsureDependencies)
+	err = p.perform(id, task, PhaseEnsureDependencies, 1, task.EnsureDependencies)
 	if err != nil {
 		cli.Logger.Errorf("Task %s failed to ensure dependencies: %v", id, err)
 		p.FailedNodes.Add(v)
-		d.Append(tfdiags.Sourceless(tfdiags.Error, "task dependency failure", tfdiags.FormatErrorPrefixed(err, id)))
+		p.record(id, task, JobStatusFailed, err)
+		d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task dependency failure", tfdiags.FormatErrorPrefixed(err, id)))
 		err = p.WriteRevisionFile(task, RevStatusFailed)
 		if err != nil {
-			d.Append(tfdiags.Sourceless(tfdiags.Error, "task dependency failure", tfdiags.FormatErrorPrefixed(err, id)))
+			d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task dependency failure", tfdiags.FormatErrorPrefixed(err, id)))
 		}
 		return d
 	}
 	cli.Logger.Infof("Performing Task: %s", id)
-	err = PerformInTimeout(task.GetTimeout(), task.Do)
+	attempts, err := p.performWithRetries(id, task, slot, task.Do)
 	if err != nil {
-		cli.Logger.Errorf("Task %s failed: %v", id, err)
+		cli.Logger.Errorf("Task %s failed after %d attempt(s): %v", id, len(attempts), err)
 		p.FailedNodes.Add(v)
-		d.Append(tfdiags.Sourceless(tfdiags.Error, "task execution failure", tfdiags.FormatErrorPrefixed(err, id)))
-		err = p.WriteRevisionFile(task, RevStatusFailed)
+		p.record(id, task, JobStatusFailed, err)
+		d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task execution failure", tfdiags.FormatErrorPrefixed(err, id)))
+		err = p.WriteRevisionFile(task, RevStatusFailed, attempts...)
 		if err != nil {
-			d.Append(tfdiags.Sourceless(tfdiags.Error, "task execution failure", tfdiags.FormatErrorPrefixed(err, id)))
+			d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task execution failure", tfdiags.FormatErrorPrefixed(err, id)))
 		}
 		return d
 	}
 	cli.Logger.Infof("Cleaning Up: %s", id)
-	err = PerformInTimeout(task.GetTimeout(), task.CleanUp)
+	err = p.perform(id, task, PhaseCleanUp, 1, task.CleanUp)
 	if err != nil {
 		cli.Logger.Errorf("Task %s could not cleanup: %v", id, err)
 		p.FailedNodes.Add(v)
-		d.Append(tfdiags.Sourceless(tfdiags.Error, "task cleanup failure", tfdiags.FormatErrorPrefixed(err, id)))
-		err = p.WriteRevisionFile(task, RevStatusFailed)
+		p.record(id, task, JobStatusFailed, err)
+		d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task cleanup failure", tfdiags.FormatErrorPrefixed(err, id)))
+		err = p.WriteRevisionFile(task, RevStatusFailed, attempts...)
 		if err != nil {
-			d.Append(tfdiags.Sourceless(tfdiags.Error, "task cleanup failure", tfdiags.FormatErrorPrefixed(err, id)))
+			d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task cleanup failure", tfdiags.FormatErrorPrefixed(err, id)))
 		}
 		return d
 	}
 	cli.Logger.Infof("Finishing: %s", id)
-	err = PerformInTimeout(task.GetTimeout(), task.Finish)
+	err = p.perform(id, task, PhaseFinish, 1, task.Finish)
 	if err != nil {
 		cli.Logger.Errorf("Task %s could not finish: %v", id, err)
 		p.FailedNodes.Add(v)
-		d.Append(tfdiags.Sourceless(tfdiags.Error, "task finishing failure", tfdiags.FormatErrorPrefixed(err, id)))
-		err = p.WriteRevisionFile(task, RevStatusFailed)
+		p.record(id, task, JobStatusFailed, err)
+		d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task finishing failure", tfdiags.FormatErrorPrefixed(err, id)))
+		err = p.WriteRevisionFile(task, RevStatusFailed, attempts...)
 		if err != nil {
-			d.Append(tfdiags.Sourceless(tfdiags.Error, "task finishing failure", tfdiags.FormatErrorPrefixed(err, id)))
+			d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task finishing failure", tfdiags.FormatErrorPrefixed(err, id)))
 		}
 		return d
 	}
+	p.record(id, task, JobStatusSuccessful, nil)
 	cli.Logger.Infof("Marking ACTIVE In State: %s", id)
-	err = p.WriteRevisionFile(task, RevStatusActive)
+	err = p.WriteRevisionFile(task, RevStatusActive, attempts...)
 	if err != nil {
-		d.Append(tfdiags.Sourceless(tfdiags.Error, "task revision file writer failure", tfdiags.FormatErrorPrefixed(err, id)))
+		d = d.Append(tfdiags.Sourceless(tfdiags.Error, "task revision file writer failure", tfdiags.FormatErrorPrefixed(err, id)))
 		return d
 	}
 	// here is where we should do some work
@@ -126,18 +126,30 @@ func (p *ProvisionedHost) SetID() string {
 }
 
 // ActualPassword attempts to get everything just right interms of what the actual password of this machine is
-func (p *ProvisionedHost) ActualPassword() string {
+func (p *ProvisionedHost) ActualPassword() (string, error) {
 	pass := p.Competition.RootPassword
 	if p.Host.OverridePassword != "" {
 		pass = p.Host.OverridePassword
+	} else if p.Team != nil {
+		gen, ok, err := p.Team.HostPassword(p.Host)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			pass = gen
+		}
 	}
-	return pass
+	return pass, nil
 }
 
 // CreateConnection creates this host's skeleton connection file to be used
-func (p *ProvisionedHost) CreateConnection() *Connection {
+func (p *ProvisionedHost) CreateConnection() (*Connection, error) {
 	if p.Conn != nil {
-		return p.Conn
+		return p.Conn, nil
+	}
+	pass, err := p.ActualPassword()
+	if err != nil {
+		return nil, err
 	}
 	c := &Connection{
 		ID:                 path.Join(p.Path(), "conn"),
@@ -161,7 +173,7 @@ func (p *ProvisionedHost) CreateConnection() *Connection {
 			HTTPS:      false,
 			SkipVerify: true,
 			User:       "Administrator",
-			Password:   p.ActualPassword(),
+			Password:   pass,
 		}
 	} else {
 		keyfile := path.Join(p.Build.Path(), "data", "ssh.pem")
@@ -174,12 +186,12 @@ func (p *ProvisionedHost) CreateConnection() *Connection {
 			Port:         22,
 			User:         "root",
 			IdentityFile: relp,
-			Password:     p.ActualPassword(),
+			Password:     pass,
 		}
 	}
 
 	p.Conn = c
-	return c
+	return c, nil
 }
 
 // CreateProvisioningStep creates a new provisioning step object for the provisioned host, mapping parent objects.
@@ -117,7 +117,7 @@ func (p *ProvisionedNetwork) SetID() string {
 }
 
 // CreateProvisionedHost creates the actual provisioned host object and assigns the parental objects accordingly.
-func (p *ProvisionedNetwork) CreateProvisionedHost(host *Host) *ProvisionedHost {
+func (p *ProvisionedNetwork) CreateProvisionedHost(host *Host) (*ProvisionedHost, error) {
 	ph := &ProvisionedHost{
 		Host:               host,
 		SubnetIP:           host.CalcIP(p.CIDR),
@@ -130,16 +130,23 @@ func (p *ProvisionedNetwork) CreateProvisionedHost(host *Host) *ProvisionedHost
 		Competition:        p.Competition,
 	}
 	p.ProvisionedHosts[ph.SetID()] = ph
-	ph.Conn = ph.CreateConnection()
+	conn, err := ph.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+	ph.Conn = conn
 	ph.Conn.SetID()
-	return ph
+	return ph, nil
 }
 
 // CreateProvisionedHosts enumerates the parent environment's host by network and creates provisioned host objects in this tree.
 func (p *ProvisionedNetwork) CreateProvisionedHosts() error {
 	for _, h := range p.Team.Environment.HostByNetwork[p.Network.Path()] {
-		ph := p.CreateProvisionedHost(h)
-		err := ph.CreateProvisioningSteps()
+		ph, err := p.CreateProvisionedHost(h)
+		if err != nil {
+			return err
+		}
+		err = ph.CreateProvisioningSteps()
 		if err != nil {
 			return err
 		}
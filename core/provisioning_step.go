@@ -29,6 +29,7 @@ type ProvisioningStep struct {
 	Command            *Command            `json:"-"`
 	RemoteFile         *RemoteFile         `json:"-"`
 	DNSRecord          *DNSRecord          `json:"-"`
+	ActiveDirectory    *ActiveDirectory    `json:"-"`
 	OnConflict         *OnConflict         `json:"-"`
 	Caller             Caller              `json:"-"`
 	Dir                string              `json:"-"`
@@ -126,6 +127,8 @@ func (p *ProvisioningStep) SetID() string {
 		p.RemoteFile = v
 	case *Script:
 		p.Script = v
+	case *ActiveDirectory:
+		p.ActiveDirectory = v
 	}
 
 	return p.ID
@@ -0,0 +1,370 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Query fields understood by ParseQuery
+const (
+	QueryFieldType              = `type`
+	QueryFieldPath              = `path`
+	QueryFieldHost              = `host`
+	QueryFieldTag               = `tag`
+	QueryFieldVar               = `var`
+	QueryFieldChanged           = `changed`
+	QueryFieldDownstream        = `downstream`
+	QueryFieldUpstream          = `upstream`
+	QueryFieldDownstreamChanged = `downstream-changed`
+	QueryFieldUpstreamChanged   = `upstream-changed`
+)
+
+// Query is a set of terms which are all required to match an object within a snapshot. Terms are separated by
+// whitespace and have one of the following forms (any term can be negated by prefixing it with !):
+//
+//	type=host,script          object is one of the listed LFTypes
+//	path=GLOB (or just GLOB)  object ID matches the glob (globs without a / match the last element of the ID)
+//	host=NAME                 object belongs to the host with the provided ID base or hostname
+//	tag.KEY / tag.KEY=VALUE   object (or it's host) has the tag, optionally equal to VALUE
+//	var.KEY / var.KEY=VALUE   object (or it's host) has the var, optionally equal to VALUE
+//	changed                   object is new or it's checksum differs from the persisted snapshot
+//	downstream=GLOB           object is reachable on the graph from an object matching GLOB
+//	upstream=GLOB             an object matching GLOB is reachable on the graph from the object
+//	downstream-changed=GLOB   like downstream, but only considering changed objects matching GLOB
+//	upstream-changed=GLOB     like upstream, but only considering changed objects matching GLOB
+type Query struct {
+	Raw   string       `json:"raw"`
+	Terms []*QueryTerm `json:"terms"`
+}
+
+// QueryTerm is a single predicate of a query
+type QueryTerm struct {
+	Negate   bool   `json:"negate,omitempty"`
+	Field    string `json:"field"`
+	Key      string `json:"key,omitempty"`
+	Value    string `json:"value,omitempty"`
+	HasValue bool   `json:"has_value,omitempty"`
+}
+
+// QueryResult is a single object matched by a query
+type QueryResult struct {
+	ID         string            `json:"id"`
+	ObjectType LFType            `json:"object_type"`
+	Checksum   uint64            `json:"checksum"`
+	Changed    bool              `json:"changed"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Vars       map[string]string `json:"vars,omitempty"`
+	Metadata   *Metadata         `json:"-"`
+}
+
+// ParseQuery parses a query string into it's terms. An empty query matches every object.
+func ParseQuery(raw string) (*Query, error) {
+	q := &Query{
+		Raw:   raw,
+		Terms: []*QueryTerm{},
+	}
+	for _, tok := range strings.Fields(raw) {
+		term := &QueryTerm{}
+		if strings.HasPrefix(tok, "!") {
+			term.Negate = true
+			tok = tok[1:]
+		}
+		if tok == "" {
+			return nil, errors.New("query contains an empty negation")
+		}
+
+		field := tok
+		if idx := strings.Index(tok, "="); idx >= 0 {
+			field = tok[:idx]
+			term.Value = tok[idx+1:]
+			term.HasValue = true
+		}
+
+		switch {
+		case strings.HasPrefix(field, QueryFieldTag+"."):
+			term.Field = QueryFieldTag
+			term.Key = strings.TrimPrefix(field, QueryFieldTag+".")
+		case strings.HasPrefix(field, QueryFieldVar+"."):
+			term.Field = QueryFieldVar
+			term.Key = strings.TrimPrefix(field, QueryFieldVar+".")
+		case field == QueryFieldChanged:
+			term.Field = QueryFieldChanged
+			if term.HasValue && term.Value != "true" && term.Value != "false" {
+				return nil, errors.Errorf("changed must be true or false, got %q", term.Value)
+			}
+			if term.HasValue && term.Value == "false" {
+				term.Negate = !term.Negate
+			}
+		case field == QueryFieldType, field == QueryFieldPath, field == QueryFieldHost,
+			field == QueryFieldDownstream, field == QueryFieldUpstream,
+			field == QueryFieldDownstreamChanged, field == QueryFieldUpstreamChanged:
+			if !term.HasValue || term.Value == "" {
+				return nil, errors.Errorf("query term %s requires a value", field)
+			}
+			term.Field = field
+		case !term.HasValue:
+			term.Field = QueryFieldPath
+			term.Value = field
+			term.HasValue = true
+		default:
+			return nil, errors.Errorf("unknown query field %q", field)
+		}
+
+		if (term.Field == QueryFieldTag || term.Field == QueryFieldVar) && term.Key == "" {
+			return nil, errors.Errorf("query term %s requires a key", tok)
+		}
+		if term.Field == QueryFieldPath || term.Field == QueryFieldDownstream || term.Field == QueryFieldUpstream ||
+			term.Field == QueryFieldDownstreamChanged || term.Field == QueryFieldUpstreamChanged {
+			if _, err := path.Match(term.Value, ""); err != nil {
+				return nil, errors.Wrapf(err, "invalid glob %q", term.Value)
+			}
+		}
+
+		q.Terms = append(q.Terms, term)
+	}
+	return q, nil
+}
+
+// String implements the Stringer interface
+func (t *QueryTerm) String() string {
+	prefix := ""
+	if t.Negate {
+		prefix = "!"
+	}
+	field := t.Field
+	if t.Key != "" {
+		field = fmt.Sprintf("%s.%s", t.Field, t.Key)
+	}
+	if !t.HasValue {
+		return prefix + field
+	}
+	return fmt.Sprintf("%s%s=%s", prefix, field, t.Value)
+}
+
+// Execute runs the query against the current snapshot, using persisted (which may be nil) to determine
+// which objects have changed. Results are sorted by ID.
+func (q *Query) Execute(current, persisted *Snapshot) ([]*QueryResult, error) {
+	if current == nil {
+		return nil, errors.New("cannot query a nil snapshot")
+	}
+
+	ex := &queryExecution{
+		current:   current,
+		persisted: persisted,
+		forward:   map[string][]string{},
+		reverse:   map[string][]string{},
+		related:   map[string]map[string]bool{},
+	}
+	if current.Edges != nil {
+		for x := range current.GetEdges().Iter() {
+			edge, ok := x.(Edge)
+			if !ok {
+				continue
+			}
+			ex.forward[edge.Source] = append(ex.forward[edge.Source], edge.Target)
+			ex.reverse[edge.Target] = append(ex.reverse[edge.Target], edge.Source)
+		}
+	}
+
+	results := []*QueryResult{}
+	for id, meta := range current.Metastore {
+		matched := true
+		for _, term := range q.Terms {
+			if ex.match(term, id, meta) == term.Negate {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		tags, vars := QueryAttributes(meta.Dependency)
+		results = append(results, &QueryResult{
+			ID:         id,
+			ObjectType: meta.ObjectType,
+			Checksum:   meta.Checksum,
+			Changed:    ex.changed(id),
+			Tags:       tags,
+			Vars:       vars,
+			Metadata:   meta,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+	return results, nil
+}
+
+// QueryResultsToJSON renders query results as indented JSON
+func QueryResultsToJSON(results []*QueryResult) ([]byte, error) {
+	return json.MarshalIndent(results, "", "  ")
+}
+
+// QueryAttributes returns the tags and vars of an object. Provisioned hosts, connections and provisioning steps
+// report the attributes of their host, provisioned networks those of their network, while teams, builds and
+// environments report their config as vars.
+func QueryAttributes(dep Dependency) (map[string]string, map[string]string) {
+	switch v := dep.(type) {
+	case *Host:
+		return v.Tags, v.Vars
+	case *ProvisionedHost:
+		if v.Host != nil {
+			return v.Host.Tags, v.Host.Vars
+		}
+	case *Connection:
+		if v.Host != nil {
+			return v.Host.Tags, v.Host.Vars
+		}
+	case *ProvisioningStep:
+		if v.Host != nil {
+			return v.Host.Tags, v.Host.Vars
+		}
+	case *Network:
+		return v.Tags, v.Vars
+	case *ProvisionedNetwork:
+		if v.Network != nil {
+			return v.Network.Tags, v.Network.Vars
+		}
+	case *Script:
+		return v.Tags, v.Vars
+	case *Command:
+		return v.Tags, v.Vars
+	case *RemoteFile:
+		return v.Tags, v.Vars
+	case *DNSRecord:
+		return v.Tags, v.Vars
+	case *ActiveDirectory:
+		return v.Tags, v.Vars
+	case *Team:
+		return v.Tags, v.Config
+	case *Build:
+		return v.Tags, v.Config
+	case *Environment:
+		return v.Tags, v.Config
+	}
+	return nil, nil
+}
+
+type queryExecution struct {
+	current   *Snapshot
+	persisted *Snapshot
+	forward   map[string][]string
+	reverse   map[string][]string
+	related   map[string]map[string]bool
+}
+
+func (ex *queryExecution) match(term *QueryTerm, id string, meta *Metadata) bool {
+	switch term.Field {
+	case QueryFieldType:
+		for _, t := range strings.Split(term.Value, ",") {
+			if LFType(t) == meta.ObjectType {
+				return true
+			}
+		}
+		return false
+	case QueryFieldPath:
+		return queryGlobMatch(term.Value, id)
+	case QueryFieldHost:
+		h := queryHostOf(meta.Dependency)
+		return h != nil && (h.Base() == term.Value || h.Hostname == term.Value)
+	case QueryFieldTag, QueryFieldVar:
+		tags, vars := QueryAttributes(meta.Dependency)
+		attrs := tags
+		if term.Field == QueryFieldVar {
+			attrs = vars
+		}
+		val, ok := attrs[term.Key]
+		if !ok {
+			return false
+		}
+		return !term.HasValue || val == term.Value
+	case QueryFieldChanged:
+		return ex.changed(id)
+	case QueryFieldDownstream, QueryFieldDownstreamChanged:
+		return ex.reachable(term, ex.forward)[id]
+	case QueryFieldUpstream, QueryFieldUpstreamChanged:
+		return ex.reachable(term, ex.reverse)[id]
+	}
+	return false
+}
+
+// changed returns true if the object is new or has a different checksum than in the persisted snapshot
+func (ex *queryExecution) changed(id string) bool {
+	if ex.persisted == nil {
+		return true
+	}
+	cur, ok := ex.current.Metastore[id]
+	if !ok {
+		return false
+	}
+	old, ok := ex.persisted.Metastore[id]
+	if !ok {
+		return true
+	}
+	return old.Checksum != cur.Checksum
+}
+
+// reachable returns the set of objects that can be reached from any object matching the term's glob by following
+// the provided edges (forward edges for downstream terms, reverse edges for upstream terms). The matched objects
+// themselves are not included unless they are related to another matched object.
+func (ex *queryExecution) reachable(term *QueryTerm, edges map[string][]string) map[string]bool {
+	key := term.Field + "=" + term.Value
+	if set, ok := ex.related[key]; ok {
+		return set
+	}
+
+	onlyChanged := term.Field == QueryFieldDownstreamChanged || term.Field == QueryFieldUpstreamChanged
+	queue := []string{}
+	for id := range ex.current.Metastore {
+		if !queryGlobMatch(term.Value, id) {
+			continue
+		}
+		if onlyChanged && !ex.changed(id) {
+			continue
+		}
+		queue = append(queue, id)
+	}
+
+	set := map[string]bool{}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[id] {
+			if next == "root" || set[next] {
+				continue
+			}
+			set[next] = true
+			queue = append(queue, next)
+		}
+	}
+
+	ex.related[key] = set
+	return set
+}
+
+// queryGlobMatch matches an ID against a glob. Globs without a slash are matched against the last element of the ID.
+func queryGlobMatch(pattern, id string) bool {
+	target := id
+	if !strings.Contains(pattern, "/") {
+		target = path.Base(id)
+	}
+	matched, err := path.Match(pattern, target)
+	return err == nil && matched
+}
+
+func queryHostOf(dep Dependency) *Host {
+	switch v := dep.(type) {
+	case *Host:
+		return v
+	case *ProvisionedHost:
+		return v.Host
+	case *Connection:
+		return v.Host
+	case *ProvisioningStep:
+		return v.Host
+	}
+	return nil
+}
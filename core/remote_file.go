@@ -20,20 +20,22 @@ import (
 //easyjson:json
 //nolint:maligned
 type RemoteFile struct {
-	ID          string            `hcl:"id,label" json:"id,omitempty"`
-	SourceType  string            `hcl:"source_type,attr" json:"source_type,omitempty"`
-	Source      string            `hcl:"source,attr" json:"source,omitempty"`
-	Destination string            `hcl:"destination,attr" json:"destination,omitempty"`
-	Vars        map[string]string `hcl:"vars,optional" json:"vars,omitempty"`
-	Tags        map[string]string `hcl:"tags,optional" json:"tags,omitempty"`
-	Template    bool              `hcl:"template,optional" json:"template,omitempty"`
-	Perms       string            `hcl:"perms,optional" json:"perms,omitempty"`
-	Disabled    bool              `hcl:"disabled,optional" json:"disabled,omitempty"`
-	OnConflict  *OnConflict       `hcl:"on_conflict,block" json:"on_conflict,omitempty"`
-	MD5         string            `hcl:"md5,optional" json:"md5,omitempty"`
-	Caller      Caller            `json:"-"`
-	AbsPath     string            `json:"-"`
-	Ext         string            `json:"-"`
+	ID           string            `hcl:"id,label" json:"id,omitempty"`
+	SourceType   string            `hcl:"source_type,attr" json:"source_type,omitempty"`
+	Source       string            `hcl:"source,attr" json:"source,omitempty"`
+	Destination  string            `hcl:"destination,attr" json:"destination,omitempty"`
+	Vars         map[string]string `hcl:"vars,optional" json:"vars,omitempty"`
+	Tags         map[string]string `hcl:"tags,optional" json:"tags,omitempty"`
+	Template     bool              `hcl:"template,optional" json:"template,omitempty"`
+	Perms        string            `hcl:"perms,optional" json:"perms,omitempty"`
+	Disabled     bool              `hcl:"disabled,optional" json:"disabled,omitempty"`
+	Retries      int               `hcl:"retries,optional" json:"retries,omitempty"`
+	RetryBackoff int               `hcl:"retry_backoff,optional" json:"retry_backoff,omitempty"`
+	OnConflict   *OnConflict       `hcl:"on_conflict,block" json:"on_conflict,omitempty"`
+	MD5          string            `hcl:"md5,optional" json:"md5,omitempty"`
+	Caller       Caller            `json:"-"`
+	AbsPath      string            `json:"-"`
+	Ext          string            `json:"-"`
 }
 
 // Hash implements the Hasher interface
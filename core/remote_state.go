@@ -1,7 +1,17 @@
 package core
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
 )
 
 // TODO: this file could be changed to use the Terraform Go code to read state files, but that code is relatively
@@ -17,203 +27,224 @@ const (
 )
 
 var (
-	// PrivateIPOutputRegexp matches outputs for private IP addresses
-	PrivateIPOutputRegexp = regexp.MustCompile(`\A(\w+)\.private_ip\z`)
+	// PrivateIPOutputRegexp matches outputs for private IP addresses (both host.private_ip and host-private_ip forms)
+	PrivateIPOutputRegexp = regexp.MustCompile(`\A([\w-]+?)[.-]private_ip\z`)
 
-	// PublicIPOutputRegexp matches outputs for private IP addresses
-	PublicIPOutputRegexp = regexp.MustCompile(`\A(\w+)\.public_ip\z`)
+	// PublicIPOutputRegexp matches outputs for public IP addresses (both host.public_ip and host-public_ip forms)
+	PublicIPOutputRegexp = regexp.MustCompile(`\A([\w-]+?)[.-]public_ip\z`)
 )
 
-// // RemoteState is used to gather data stored in the remote etcd backend of a laforge configured environment
-// type RemoteState struct {
-// 	Base         *Laforge
-// 	LocalConfig  *TerraformState
-// 	RemoteConfig *TerraformState
-// 	Hosts        map[string]*HostTableInfo
-// }
-
-// // GetState retrieves a remote configuration of a laforge deployed terraform environment
-// func GetState(base *Laforge) (*RemoteState, error) {
-// 	configFile := filepath.Join(base.TeamRoot, ".terraform", "terraform.tfstate")
-// 	localconfig, err := ParseTerraformStateFile(configFile)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	endpoints := []string{}
-
-// 	for _, x := range localconfig.Backend.Config["endpoints"].([]interface{}) {
-// 		endpoints = append(endpoints, x.(string))
-// 	}
-
-// 	etcdclient, err := clientv3.New(clientv3.Config{
-// 		Endpoints:   endpoints,
-// 		Username:    localconfig.Backend.Config["username"].(string),
-// 		Password:    localconfig.Backend.Config["password"].(string),
-// 		DialTimeout: 10 * time.Second,
-// 	})
-
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	defer etcdclient.Close()
-
-// 	key := fmt.Sprintf("%sdefault", localconfig.Backend.Config["prefix"].(string))
-// 	resp, err := etcdclient.Get(context.TODO(), key)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	var data []byte
-// 	for _, ev := range resp.Kvs {
-// 		if string(ev.Key) == key {
-// 			data = ev.Value
-// 			break
-// 		}
-// 	}
-
-// 	if len(data) == 0 {
-// 		return nil, errors.New("remote state was empty for etcd key")
-// 	}
-
-// 	state, err := ParseTerraformStateFromBytes(data)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	rs := &RemoteState{
-// 		Base:         base,
-// 		LocalConfig:  localconfig,
-// 		RemoteConfig: state,
-// 		Hosts:        map[string]*HostTableInfo{},
-// 	}
-
-// 	rs.ParseHostInfo()
-
-// 	return rs, nil
-// }
-
-// // ParseHostInfo attempts to parse host output information from the terraform configuration
-// func (r *RemoteState) ParseHostInfo() {
-// 	if r.RemoteConfig == nil {
-// 		return
-// 	}
-// 	for _, m := range r.RemoteConfig.Modules {
-// 		for k, v := range m.Outputs {
-// 			sm, ok := v.(map[string]interface{})
-// 			if !ok {
-// 				continue
-// 			}
-// 			if PublicIPOutputRegexp.MatchString(k) {
-// 				hostname := strings.Replace(k, `.public_ip`, ``, -1)
-// 				hostinfo, found := r.Hosts[hostname]
-// 				if !found {
-// 					hostinfo = &HostTableInfo{Hostname: hostname}
-// 					r.Hosts[hostname] = hostinfo
-// 				}
-// 				hostinfo.PublicIP = sm["value"].(string)
-// 				continue
-// 			}
-// 			if PrivateIPOutputRegexp.MatchString(k) {
-// 				hostname := strings.Replace(k, `.private_ip`, ``, -1)
-// 				hostinfo, found := r.Hosts[hostname]
-// 				if !found {
-// 					hostinfo = &HostTableInfo{Hostname: hostname}
-// 					r.Hosts[hostname] = hostinfo
-// 				}
-// 				hostinfo.PrivateIP = sm["value"].(string)
-// 				continue
-// 			}
-// 		}
-// 	}
-// }
-
-// // HostTableInfo represents host information parsed out of a remote state
-// type HostTableInfo struct {
-// 	PublicIP  string `json:"public_ip"`
-// 	PrivateIP string `json:"private_ip"`
-// 	Hostname  string `json:"hostname"`
-// }
-
-// // TableInfo is a helper function for pretty pretting host information
-// func (h *HostTableInfo) TableInfo() []string {
-// 	return []string{
-// 		h.Hostname,
-// 		h.PublicIP,
-// 		h.PrivateIP,
-// 	}
-// }
-
-// // TerraformState represents the structure of the Terraform .tfstate file
-// type TerraformState struct {
-// 	Version int
-// 	Serial  int
-// 	Backend *TerraformBackend
-// 	Modules []TerraformStateModule
-// }
-
-// // TerraformBackend represents the structure of the "backend" section of the Terraform .tfstate file
-// type TerraformBackend struct {
-// 	Type   string
-// 	Config map[string]interface{}
-// }
-
-// // TerraformStateModule represents the structure of a "module" section of the Terraform .tfstate file
-// type TerraformStateModule struct {
-// 	Path      []string
-// 	Outputs   map[string]interface{}
-// 	Resources map[string]interface{}
-// }
-
-// // IsRemote returns true if this Terraform state is configured for remote state storage
-// func (state *TerraformState) IsRemote() bool {
-// 	return state.Backend != nil && state.Backend.Type != "local"
-// }
-
-// // ParseTerraformStateFileFromLocation parses the Terraform .tfstate file. If a local backend is used then search the given path, or
-// // return nil if the file is missing. If the backend is not local then parse the Terraform .tfstate
-// // file from the location specified by workingDir. If no location is specified, search the current
-// // directory. If the file doesn't exist at any of the default locations, return nil.
-// func ParseTerraformStateFileFromLocation(backend string, config map[string]interface{}, workingDir string) (*TerraformState, error) {
-// 	stateFile, ok := config["path"].(string)
-// 	if backend == "local" && ok && util.FileExists(stateFile) {
-// 		return ParseTerraformStateFile(stateFile)
-// 	} else if util.FileExists(util.JoinPath(workingDir, DefaultPathToLocalStateFile)) {
-// 		return ParseTerraformStateFile(util.JoinPath(workingDir, DefaultPathToLocalStateFile))
-// 	} else if util.FileExists(util.JoinPath(workingDir, DefaultPathToRemoteStateFile)) {
-// 		return ParseTerraformStateFile(util.JoinPath(workingDir, DefaultPathToRemoteStateFile))
-// 	}
-// 	return nil, nil
-// }
-
-// // ParseTerraformStateFile parses the Terraform .tfstate file at the given path
-// func ParseTerraformStateFile(path string) (*TerraformState, error) {
-// 	bytes, err := ioutil.ReadFile(path)
-// 	if err != nil {
-// 		return nil, errors.Wrap(CantParseTerraformStateFile{Path: path, UnderlyingErr: err}, "failure to read terraform state file")
-// 	}
-
-// 	return ParseTerraformStateFromBytes(bytes)
-// }
-
-// // ParseTerraformStateFromBytes parses the Terraform state file data in the given byte slice
-// func ParseTerraformStateFromBytes(terraformStateData []byte) (*TerraformState, error) {
-// 	terraformState := &TerraformState{}
-
-// 	if err := json.Unmarshal(terraformStateData, terraformState); err != nil {
-// 		return nil, errors.Wrap(err, "failure to parse bytes into terraform state")
-// 	}
-
-// 	return terraformState, nil
-// }
-
-// // CantParseTerraformStateFile is an error type used to represent a failure to parse a terraform state file
-// type CantParseTerraformStateFile struct {
-// 	Path          string
-// 	UnderlyingErr error
-// }
-
-// // Error implements the error interface
-// func (err CantParseTerraformStateFile) Error() string {
-// 	return fmt.Sprintf("Error parsing Terraform state file %s: %s", err.Path, err.UnderlyingErr.Error())
-// }
+// RemoteState is the host information of a single team's terraform state
+type RemoteState struct {
+	Team  *Team
+	State *TerraformState
+	Hosts map[string]*HostTableInfo
+}
+
+// GetRemoteState reads the terraform state of a team out of the team's directory, pulling it from the
+// configured backend if the state is stored remotely, and parses the host outputs out of it.
+func GetRemoteState(team *Team, dir string) (*RemoteState, error) {
+	state, err := LoadTerraformState(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	rs := &RemoteState{
+		Team:  team,
+		State: state,
+		Hosts: map[string]*HostTableInfo{},
+	}
+
+	rs.ParseHostInfo()
+
+	return rs, nil
+}
+
+// ParseHostInfo attempts to parse host output information from the terraform configuration
+func (r *RemoteState) ParseHostInfo() {
+	if r.State == nil {
+		return
+	}
+	for k, v := range r.State.StringOutputs() {
+		if m := PublicIPOutputRegexp.FindStringSubmatch(k); m != nil {
+			r.hostInfo(m[1]).PublicIP = v
+			continue
+		}
+		if m := PrivateIPOutputRegexp.FindStringSubmatch(k); m != nil {
+			r.hostInfo(m[1]).PrivateIP = v
+			continue
+		}
+	}
+}
+
+func (r *RemoteState) hostInfo(hostname string) *HostTableInfo {
+	hostinfo, found := r.Hosts[hostname]
+	if !found {
+		hostinfo = &HostTableInfo{Hostname: hostname}
+		r.Hosts[hostname] = hostinfo
+	}
+	return hostinfo
+}
+
+// HostTableInfo represents host information parsed out of a remote state
+type HostTableInfo struct {
+	PublicIP  string `json:"public_ip"`
+	PrivateIP string `json:"private_ip"`
+	Hostname  string `json:"hostname"`
+}
+
+// TableInfo is a helper function for pretty pretting host information
+func (h *HostTableInfo) TableInfo() []string {
+	return []string{
+		h.Hostname,
+		h.PublicIP,
+		h.PrivateIP,
+	}
+}
+
+// TerraformState represents the structure of the Terraform .tfstate file. Terraform 0.11 and earlier keep outputs
+// inside of each module while 0.12 and later keep them at the root, so both are supported.
+type TerraformState struct {
+	Version int                         `json:"version"`
+	Serial  int                         `json:"serial"`
+	Backend *TerraformBackend           `json:"backend,omitempty"`
+	Modules []TerraformStateModule      `json:"modules,omitempty"`
+	Outputs map[string]*TerraformOutput `json:"outputs,omitempty"`
+}
+
+// TerraformBackend represents the structure of the "backend" section of the Terraform .tfstate file
+type TerraformBackend struct {
+	Type   string                 `json:"type"`
+	Config map[string]interface{} `json:"config"`
+}
+
+// TerraformStateModule represents the structure of a "module" section of the Terraform .tfstate file
+type TerraformStateModule struct {
+	Path      []string                    `json:"path"`
+	Outputs   map[string]*TerraformOutput `json:"outputs"`
+	Resources map[string]interface{}      `json:"resources"`
+}
+
+// TerraformOutput represents a single output value in the Terraform .tfstate file
+type TerraformOutput struct {
+	Sensitive bool        `json:"sensitive"`
+	Type      interface{} `json:"type"`
+	Value     interface{} `json:"value"`
+}
+
+// IsRemote returns true if this Terraform state is configured for remote state storage
+func (state *TerraformState) IsRemote() bool {
+	return state.Backend != nil && state.Backend.Type != "" && state.Backend.Type != "local"
+}
+
+// StringOutputs returns every output of the state which has a string value, regardless of which module it was declared in.
+func (state *TerraformState) StringOutputs() map[string]string {
+	ret := map[string]string{}
+	add := func(outputs map[string]*TerraformOutput) {
+		keys := []string{}
+		for k := range outputs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if outputs[k] == nil {
+				continue
+			}
+			if v, ok := outputs[k].Value.(string); ok {
+				ret[k] = v
+			}
+		}
+	}
+	for _, m := range state.Modules {
+		add(m.Outputs)
+	}
+	add(state.Outputs)
+	return ret
+}
+
+// LoadTerraformState locates the terraform state for the terraform working directory dir. A local terraform.tfstate
+// is used if one exists. Otherwise, if the directory has been initialized with a remote backend, the state is pulled
+// from that backend using the terraform binary.
+func LoadTerraformState(dir string) (*TerraformState, error) {
+	localPath := filepath.Join(dir, DefaultPathToLocalStateFile)
+	if _, err := os.Stat(localPath); err == nil {
+		return ParseTerraformStateFile(localPath)
+	}
+
+	remotePath := filepath.Join(dir, DefaultPathToRemoteStateFile)
+	if _, err := os.Stat(remotePath); err != nil {
+		return nil, errors.Errorf("no terraform state was found in %s (has terraform been applied?)", dir)
+	}
+
+	backendState, err := ParseTerraformStateFile(remotePath)
+	if err != nil {
+		return nil, err
+	}
+	if !backendState.IsRemote() {
+		return backendState, nil
+	}
+
+	return PullTerraformState(dir)
+}
+
+// PullTerraformState retrieves the state of an initialized terraform working directory from it's remote backend.
+func PullTerraformState(dir string) (*TerraformState, error) {
+	tfexe, err := FindTerraformExecutable()
+	if err != nil {
+		return nil, errors.Wrap(err, "terraform binary was not located in the path")
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	cmd := exec.Command(tfexe, "state", "pull")
+	cmd.Dir = dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err = cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "terraform state pull failed in %s: %s", dir, stderr.String())
+	}
+
+	if stdout.Len() == 0 {
+		return nil, errors.Errorf("remote terraform state for %s was empty", dir)
+	}
+
+	return ParseTerraformStateFromBytes(stdout.Bytes())
+}
+
+// ParseTerraformStateFile parses the Terraform .tfstate file at the given path
+func ParseTerraformStateFile(path string) (*TerraformState, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(CantParseTerraformStateFile{Path: path, UnderlyingErr: err}, "failure to read terraform state file")
+	}
+
+	state, err := ParseTerraformStateFromBytes(data)
+	if err != nil {
+		return nil, CantParseTerraformStateFile{Path: path, UnderlyingErr: err}
+	}
+
+	return state, nil
+}
+
+// ParseTerraformStateFromBytes parses the Terraform state file data in the given byte slice
+func ParseTerraformStateFromBytes(terraformStateData []byte) (*TerraformState, error) {
+	terraformState := &TerraformState{}
+
+	if err := json.Unmarshal(terraformStateData, terraformState); err != nil {
+		return nil, errors.Wrap(err, "failure to parse bytes into terraform state")
+	}
+
+	return terraformState, nil
+}
+
+// CantParseTerraformStateFile is an error type used to represent a failure to parse a terraform state file
+type CantParseTerraformStateFile struct {
+	Path          string
+	UnderlyingErr error
+}
+
+// Error implements the error interface
+func (err CantParseTerraformStateFile) Error() string {
+	return fmt.Sprintf("Error parsing Terraform state file %s: %s", err.Path, err.UnderlyingErr.Error())
+}
@@ -47,6 +47,7 @@ func RenderHCLv2Object(i interface{}) ([]byte, error) {
 // TemplateFuncLib is a standard template library of functions
 var TemplateFuncLib = template.FuncMap{
 	"hclstring":            QuotedHCLString,
+	"rubystring":           QuotedRubyString,
 	"N":                    iter.N,
 	"UnsafeAtoi":           UnsafeStringAsInt,
 	"Decr":                 Decr,
@@ -59,6 +60,7 @@ var TemplateFuncLib = template.FuncMap{
 	"Repeat":               strings.Repeat,
 	"Split":                strings.Split,
 	"Title":                strings.Title,
+	"TrimSpace":            strings.TrimSpace,
 	"ToLower":              strings.ToLower,
 	"ToSnake":              strcase.ToSnake,
 	"ToScreamingSnake":     strcase.ToScreamingSnake,
@@ -84,6 +86,12 @@ func Decr(i int) int {
 	return i - 1
 }
 
+// QuotedRubyString is a template function to render safe single quoted ruby strings
+func QuotedRubyString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return "'" + r.Replace(s) + "'"
+}
+
 // QuotedHCLString is a template function to render safe HCLv2 strings
 func QuotedHCLString(s string) string {
 	e := new(bytes.Buffer)
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/gen0cide/laforge/core/cli"
+)
+
+var (
+	// DefaultRetryBackoff is the delay in seconds before the first retry of a provisioner that sets retries but
+	// not retry_backoff
+	DefaultRetryBackoff = 5
+
+	// MaxRetryBackoff caps the delay between two attempts of a task
+	MaxRetryBackoff = 10 * time.Minute
+)
+
+// Retrier is implemented by provisioners which can be retried when they fail
+type Retrier interface {
+	// RetryPolicy returns how many times the provisioner is retried and the delay in seconds before the first retry
+	RetryPolicy() (retries int, backoff int)
+}
+
+// Attempt records a single run of a task's Do function
+type Attempt struct {
+	Number    int       `json:"number"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// RetryPolicy implements the Retrier interface
+func (s *Script) RetryPolicy() (int, int) {
+	return s.Retries, s.RetryBackoff
+}
+
+// RetryPolicy implements the Retrier interface
+func (c *Command) RetryPolicy() (int, int) {
+	return c.Retries, c.RetryBackoff
+}
+
+// RetryPolicy implements the Retrier interface
+func (r *RemoteFile) RetryPolicy() (int, int) {
+	return r.Retries, r.RetryBackoff
+}
+
+// RetryBackoff returns the delay before the retry that follows the provided attempt number. The delay doubles with
+// every attempt, starting at backoff seconds.
+func RetryBackoff(backoff int, attempt int) time.Duration {
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+	delay := time.Duration(backoff) * time.Second
+	for i := 1; i < attempt && delay < MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > MaxRetryBackoff {
+		delay = MaxRetryBackoff
+	}
+	return delay
+}
+
+// retryPolicy returns the retry policy of the provisioner a task performs, if it has one
+func retryPolicy(task Doer) (int, int) {
+	if task.GetMetadata() == nil {
+		return 0, 0
+	}
+	pstep, ok := task.GetMetadata().Dependency.(*ProvisioningStep)
+	if !ok {
+		return 0, 0
+	}
+	r, ok := pstep.Provisioner.(Retrier)
+	if !ok {
+		return 0, 0
+	}
+	return r.RetryPolicy()
+}
+
+// performWithRetries runs f as the task's Do phase, running it again with an exponential backoff for as many times as
+// the task's provisioner allows. An attempt which exceeds the task's timeout is not retried. Every attempt is logged
+// and returned, along with the error of the last one. The node's slot is released while it waits to retry so that other
+// nodes are not held up by the backoff.
+func (p *Plan) performWithRetries(id string, task Doer, slot *Slot, f TimeoutFunc) ([]*Attempt, error) {
+	retries, backoff := retryPolicy(task)
+	attempts := []*Attempt{}
+	for n := 1; ; n++ {
+		attempt := &Attempt{
+			Number:    n,
+			StartedAt: time.Now().UTC(),
+		}
+		attempts = append(attempts, attempt)
+		err := p.perform(id, task, PhaseDo, n, f)
+		attempt.EndedAt = time.Now().UTC()
+		if err == nil {
+			if n > 1 {
+				cli.Logger.Warnf("Task %s succeeded on attempt %d of %d", id, n, retries+1)
+			}
+			return attempts, nil
+		}
+		attempt.Error = err.Error()
+		if n > retries {
+			return attempts, err
+		}
+		if errors.Cause(err) == ErrTimeoutExceeded {
+			// the timed out attempt can not be cancelled and may still be running on the host, so it is not retried
+			cli.Logger.Errorf("Task %s timed out on attempt %d of %d, not retrying while it may still be running", id, n, retries+1)
+			return attempts, err
+		}
+		delay := RetryBackoff(backoff, n)
+		cli.Logger.Warnf("Task %s failed attempt %d of %d, retrying in %s: %v", id, n, retries+1, delay, err)
+		slot.Release()
+		time.Sleep(delay)
+		slot.Reacquire()
+	}
+}
@@ -55,6 +55,7 @@ type Revision struct {
 	Timestamp  time.Time         `json:"timestamp"`
 	ExternalID string            `json:"external_id"`
 	Vars       map[string]string `json:"vars"`
+	Attempts   []*Attempt        `json:"attempts,omitempty"`
 }
 
 // Touch sets the current timestamp and status to active for use within templating engines
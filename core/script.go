@@ -16,25 +16,29 @@ import (
 //easyjson:json
 //nolint:maligned
 type Script struct {
-	ID           string            `hcl:"id,label" json:"id,omitempty"`
-	Name         string            `hcl:"name,attr" json:"name,omitempty"`
-	Language     string            `hcl:"language,attr" json:"language,omitempty"`
-	Description  string            `hcl:"description,optional" json:"description,omitempty"`
-	Maintainer   *User             `hcl:"maintainer,block" json:"maintainer,omitempty"`
-	Source       string            `hcl:"source,attr" json:"source,omitempty"`
-	SourceType   string            `hcl:"source_type,attr" json:"source_type,omitempty"`
-	Cooldown     int               `hcl:"cooldown,optional" json:"cooldown,omitempty"`
-	Timeout      int               `hcl:"timeout,optional" json:"timeout,omitempty"`
-	IgnoreErrors bool              `hcl:"ignore_errors,optional" json:"ignore_errors,omitempty"`
-	Args         []string          `hcl:"args,optional" json:"args,omitempty"`
-	IO           *IO               `hcl:"io,block" json:"io,omitempty"`
-	Disabled     bool              `hcl:"disabled,optional" json:"disabled,omitempty"`
-	Vars         map[string]string `hcl:"vars,optional" json:"vars,omitempty"`
-	Tags         map[string]string `hcl:"tags,optional" json:"tags,omitempty"`
-	OnConflict   *OnConflict       `hcl:"on_conflict,block" json:"on_conflict,omitempty"`
-	Findings     []*Finding        `hcl:"finding,block" json:"findings,omitempty"`
-	AbsPath      string            `json:"-"`
-	Caller       Caller            `json:"-"`
+	ID             string            `hcl:"id,label" json:"id,omitempty"`
+	Name           string            `hcl:"name,attr" json:"name,omitempty"`
+	Language       string            `hcl:"language,attr" json:"language,omitempty"`
+	Description    string            `hcl:"description,optional" json:"description,omitempty"`
+	Maintainer     *User             `hcl:"maintainer,block" json:"maintainer,omitempty"`
+	Source         string            `hcl:"source,attr" json:"source,omitempty"`
+	SourceType     string            `hcl:"source_type,attr" json:"source_type,omitempty"`
+	Cooldown       int               `hcl:"cooldown,optional" json:"cooldown,omitempty"`
+	Timeout        int               `hcl:"timeout,optional" json:"timeout,omitempty"`
+	IgnoreErrors   bool              `hcl:"ignore_errors,optional" json:"ignore_errors,omitempty"`
+	Reboot         bool              `hcl:"reboot,optional" json:"reboot,omitempty"`
+	RebootExitCode int               `hcl:"reboot_exit_code,optional" json:"reboot_exit_code,omitempty"`
+	Retries        int               `hcl:"retries,optional" json:"retries,omitempty"`
+	RetryBackoff   int               `hcl:"retry_backoff,optional" json:"retry_backoff,omitempty"`
+	Args           []string          `hcl:"args,optional" json:"args,omitempty"`
+	IO             *IO               `hcl:"io,block" json:"io,omitempty"`
+	Disabled       bool              `hcl:"disabled,optional" json:"disabled,omitempty"`
+	Vars           map[string]string `hcl:"vars,optional" json:"vars,omitempty"`
+	Tags           map[string]string `hcl:"tags,optional" json:"tags,omitempty"`
+	OnConflict     *OnConflict       `hcl:"on_conflict,block" json:"on_conflict,omitempty"`
+	Findings       []*Finding        `hcl:"finding,block" json:"findings,omitempty"`
+	AbsPath        string            `json:"-"`
+	Caller         Caller            `json:"-"`
 }
 
 // Hash implements the Hasher interface
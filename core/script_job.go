@@ -46,6 +46,10 @@ func (j *ScriptJob) CanProceed(e chan error) {
 		e <- errors.New("cannot proceed with script job with nil targets")
 		return
 	}
+	if j.Script.Reboot || j.Script.RebootExitCode != 0 {
+		e <- errors.Wrapf(ErrRebootRequiresAgent, "script %s on %s", j.Script.ID, j.Target.ParentLaforgeID())
+		return
+	}
 	if j.Target.ProvisionedHost.Conn.Active {
 		e <- nil
 		return
@@ -0,0 +1,463 @@
+package core
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SecretPrefix starts a configuration value which references a secret instead of containing it. The reference is
+	// in the form secret:<provider>:<reference> - for example secret:env:ROOT_PASSWORD.
+	SecretPrefix = `secret:`
+
+	// KeyringFilename is the name of the key used to encrypt keyring secrets in the global config directory
+	KeyringFilename = `secrets.key`
+
+	// RedactedValue replaces a secret in redacted output
+	RedactedValue = `<redacted>`
+
+	// MinRedactedLength is the length a secret must reach to be redacted, so that trivially short values do not
+	// garble the output they are redacted from
+	MinRedactedLength = 3
+)
+
+var (
+	// ErrNoKeyring is thrown when a keyring secret is used before a keyring has been created
+	ErrNoKeyring = errors.New("no secrets keyring exists, create one with laforge secret keygen")
+
+	// Secrets redacts every secret resolved by the Loader
+	Secrets = NewRedactor()
+
+	secretProviders = map[string]SecretProvider{
+		"env":     EnvSecretProvider{},
+		"file":    FileSecretProvider{},
+		"keyring": KeyringSecretProvider{},
+		"vault":   VaultSecretProvider{},
+	}
+
+	sensitiveKeyWords = []string{"password", "passwd", "secret", "token"}
+)
+
+// SecretProvider resolves the secret a reference points to
+type SecretProvider interface {
+	Resolve(ref string) (string, error)
+}
+
+// RegisterSecretProvider adds (or replaces) the provider used for secret:<name>:<reference> values
+func RegisterSecretProvider(name string, p SecretProvider) {
+	secretProviders[name] = p
+}
+
+// IsSecretRef returns true if a configuration value references a secret
+func IsSecretRef(val string) bool {
+	return strings.HasPrefix(val, SecretPrefix)
+}
+
+// IsSensitiveKey returns true if the name of a config key suggests it's value is a secret (etcd_password, api_token)
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, w := range sensitiveKeyWords {
+		if strings.Contains(key, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveSecret returns the secret a value references, or the value itself if it is not a reference
+func ResolveSecret(val string) (string, error) {
+	if !IsSecretRef(val) {
+		return val, nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(val, SecretPrefix), ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", fmt.Errorf("secret reference %q is not in the form secret:<provider>:<reference>", val)
+	}
+	p, ok := secretProviders[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("secret reference %q uses an unknown provider %s", val, parts[0])
+	}
+	secret, err := p.Resolve(parts[1])
+	if err != nil {
+		return "", errors.Wrapf(err, "could not resolve %s secret", parts[0])
+	}
+	return secret, nil
+}
+
+// ResolveSecrets replaces every secret reference in the configuration with the secret it points to, and adds the
+// values of password fields and sensitive config keys to the Secrets redactor. It is called by the Loader at bind time.
+func (l *Laforge) ResolveSecrets() error {
+	resolve := func(id string, field string, val *string) error {
+		secret, err := ResolveSecret(*val)
+		if err != nil {
+			return errors.Wrapf(err, "%s %s", id, field)
+		}
+		*val = secret
+		Secrets.Add(secret)
+		return nil
+	}
+	resolveConfig := func(id string, config map[string]string) error {
+		for key, val := range config {
+			if !IsSecretRef(val) && !IsSensitiveKey(key) {
+				continue
+			}
+			err := resolve(id, "config "+key, &val)
+			if err != nil {
+				return err
+			}
+			config[key] = val
+		}
+		return nil
+	}
+
+	for id, c := range l.Competitions {
+		if err := resolve(id, "root_password", &c.RootPassword); err != nil {
+			return err
+		}
+		if err := resolveConfig(id, c.Config); err != nil {
+			return err
+		}
+		if c.DNS != nil {
+			if err := resolveConfig(id+" dns", c.DNS.Config); err != nil {
+				return err
+			}
+		}
+	}
+	for id, i := range l.Identities {
+		if err := resolve(id, "password", &i.Password); err != nil {
+			return err
+		}
+	}
+	for id, h := range l.Hosts {
+		if err := resolve(id, "override_password", &h.OverridePassword); err != nil {
+			return err
+		}
+	}
+	for id, e := range l.Environments {
+		if err := resolveConfig(id, e.Config); err != nil {
+			return err
+		}
+	}
+	for id, b := range l.Builds {
+		if err := resolveConfig(id, b.Config); err != nil {
+			return err
+		}
+	}
+	for id, t := range l.Teams {
+		if err := resolveConfig(id, t.Config); err != nil {
+			return err
+		}
+	}
+	for id, c := range l.Connections {
+		if c.WinRMAuthConfig != nil {
+			if err := resolve(id, "winrm password", &c.WinRMAuthConfig.Password); err != nil {
+				return err
+			}
+		}
+		if c.SSHAuthConfig != nil {
+			if err := resolve(id, "ssh password", &c.SSHAuthConfig.Password); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// EnvSecretProvider resolves secret:env:NAME from the environment variable NAME
+type EnvSecretProvider struct{}
+
+// Resolve implements the SecretProvider interface
+func (EnvSecretProvider) Resolve(ref string) (string, error) {
+	val, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", ref)
+	}
+	return val, nil
+}
+
+// FileSecretProvider resolves secret:file:PATH from the contents of the file at PATH, without a trailing newline
+type FileSecretProvider struct{}
+
+// Resolve implements the SecretProvider interface
+func (FileSecretProvider) Resolve(ref string) (string, error) {
+	data, err := ioutil.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// VaultSecretProvider resolves secret:vault:PATH#FIELD from a Vault compatible HTTP API, using the VAULT_ADDR and
+// VAULT_TOKEN environment variables. Both version 1 and 2 KV secrets engines are supported. FIELD defaults to value.
+type VaultSecretProvider struct{}
+
+// Resolve implements the SecretProvider interface
+func (VaultSecretProvider) Resolve(ref string) (string, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return "", errors.New("VAULT_ADDR is not set")
+	}
+	field := "value"
+	if i := strings.LastIndex(ref, "#"); i != -1 {
+		ref, field = ref[:i], ref[i+1:]
+	}
+	req, err := http.NewRequest("GET", strings.TrimRight(addr, "/")+"/v1/"+strings.TrimLeft(ref, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", os.Getenv("VAULT_TOKEN"))
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("vault returned %s for %s", resp.Status, ref)
+	}
+
+	body := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid response from vault")
+	}
+	data := body.Data
+	// the KV version 2 engine nests the secret's fields in a second data object alongside it's metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, versioned := data["metadata"]; versioned {
+			data = nested
+		}
+	}
+	val, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("vault secret %s has no field %s", ref, field)
+	}
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprintf("%v", val), nil
+}
+
+// KeyringSecretProvider resolves secret:keyring:CIPHERTEXT by decrypting it with the key in the global config
+// directory. Values are encrypted with laforge secret encrypt.
+type KeyringSecretProvider struct{}
+
+// Resolve implements the SecretProvider interface
+func (KeyringSecretProvider) Resolve(ref string) (string, error) {
+	key, err := LoadKeyring()
+	if err != nil {
+		return "", err
+	}
+	return DecryptSecret(key, ref)
+}
+
+// KeyringPath returns the location of the secrets keyring in the global config directory
+func KeyringPath() (string, error) {
+	gcd, err := GlobalConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gcd, KeyringFilename), nil
+}
+
+// LoadKeyring reads the key of the secrets keyring, returning ErrNoKeyring if one has not been created
+func LoadKeyring() ([]byte, error) {
+	keyfile, err := KeyringPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(keyfile)
+	if os.IsNotExist(err) {
+		return nil, ErrNoKeyring
+	}
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%s is not a valid laforge secrets key", keyfile)
+	}
+	return key, nil
+}
+
+// CreateKeyring generates a new key for the secrets keyring. An existing key is only replaced if overwrite is set,
+// since every value encrypted with it becomes unreadable.
+func CreateKeyring(overwrite bool) (string, error) {
+	keyfile, err := KeyringPath()
+	if err != nil {
+		return "", err
+	}
+	if PathExists(keyfile) && !overwrite {
+		return "", fmt.Errorf("a secrets key already exists at %s", keyfile)
+	}
+	key := make([]byte, 32)
+	_, err = io.ReadFull(rand.Reader, key)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(filepath.Dir(keyfile), 0700)
+	if err != nil {
+		return "", err
+	}
+	return keyfile, ioutil.WriteFile(keyfile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600)
+}
+
+// EncryptSecret encrypts a secret with AES-256-GCM, returning the secret:keyring: reference to it
+func EncryptSecret(key []byte, secret string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return SecretPrefix + "keyring:" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret decrypts the ciphertext of a keyring secret
+func DecryptSecret(key []byte, ciphertext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", errors.Wrap(err, "keyring secret is not valid base64")
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("keyring secret is truncated")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("keyring secret could not be decrypted with this keyring's key")
+	}
+	return string(plain), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Redactor replaces known secrets in text
+type Redactor struct {
+	sync.Mutex
+	values map[string]bool
+}
+
+// NewRedactor creates an empty redactor
+func NewRedactor() *Redactor {
+	return &Redactor{values: map[string]bool{}}
+}
+
+// Add registers a secret to be redacted. Secrets shorter than MinRedactedLength are ignored.
+func (r *Redactor) Add(secret string) {
+	if len(secret) < MinRedactedLength {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.values[secret] = true
+}
+
+// Redact replaces every registered secret in s, including where it has been escaped as a JSON or Go string. Only
+// whole values are replaced - a secret which is part of a longer word, or which is the name of a field (followed by
+// a : or =), is left alone so that weak passwords such as dictionary words do not garble the output.
+func (r *Redactor) Redact(s string) string {
+	r.Lock()
+	defer r.Unlock()
+	forms := map[string]bool{}
+	for v := range r.values {
+		forms[v] = true
+		if data, err := json.Marshal(v); err == nil {
+			forms[string(data[1:len(data)-1])] = true
+		}
+		quoted := strconv.Quote(v)
+		forms[quoted[1:len(quoted)-1]] = true
+	}
+	// longest first, so a secret containing another secret is replaced whole
+	ordered := []string{}
+	for v := range forms {
+		ordered = append(ordered, v)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return len(ordered[i]) > len(ordered[j])
+	})
+	for _, v := range ordered {
+		s = redactValue(s, v)
+	}
+	return s
+}
+
+// redactValue replaces the occurrences of v in s which stand on their own as a value
+func redactValue(s, v string) string {
+	b := new(strings.Builder)
+	last := 0
+	for off := 0; off < len(s); {
+		i := strings.Index(s[off:], v)
+		if i < 0 {
+			break
+		}
+		start := off + i
+		end := start + len(v)
+		off = end
+		if isWordByte(v[0]) && start > 0 && isWordByte(s[start-1]) {
+			continue
+		}
+		if isWordByte(v[len(v)-1]) && end < len(s) && isWordByte(s[end]) {
+			continue
+		}
+		if isFieldName(s[end:]) {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(RedactedValue)
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// isWordByte returns true for the bytes that make up a word (including every byte of a multibyte character)
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 0x80 || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// isFieldName returns true if the text following a match assigns to it, such as the closing quote and colon of a
+// JSON object key or the equals sign of an HCL attribute
+func isFieldName(rest string) bool {
+	if len(rest) > 0 && (rest[0] == '"' || rest[0] == '\'') {
+		rest = rest[1:]
+	}
+	rest = strings.TrimLeft(rest, " \t")
+	return len(rest) > 0 && (rest[0] == ':' || rest[0] == '=')
+}
+
+// Redact replaces every secret resolved by the Loader in s
+func Redact(s string) string {
+	return Secrets.Redact(s)
+}
@@ -0,0 +1,31 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestRedactWholeValues(t *testing.T) {
+	r := NewRedactor()
+	r.Add("summer")
+	r.Add("password")
+	r.Add(`p@ss"w0rd`)
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`{"root_password":"summer"}`, `{"root_password":"<redacted>"}`},
+		{`the summertime sadness of summer`, `the summertime sadness of <redacted>`},
+		{`{"password": "password"}`, `{"password": "<redacted>"}`},
+		{`password = "password"`, `password = "<redacted>"`},
+		{`user:summer@host`, `user:<redacted>@host`},
+		{`passwords and passwordless logins`, `passwords and passwordless logins`},
+		{`{"pw":"p@ss\"w0rd"}`, `{"pw":"<redacted>"}`},
+	}
+	for _, tt := range tests {
+		got := r.Redact(tt.in)
+		if got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
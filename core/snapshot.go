@@ -313,6 +313,10 @@ func (s *Snapshot) WalkHost(h *Host, wg *sync.WaitGroup) {
 		s.AddObject(x)
 		s.AddRelationship(h, x)
 	}
+	for _, x := range h.ActiveDirectories {
+		s.AddObject(x)
+		s.AddRelationship(h, x)
+	}
 }
 
 // WalkTeam is used to enumerate the resources of a team
@@ -359,6 +363,10 @@ func (s *Snapshot) WalkProvisionedHost(ph *ProvisionedHost, wg *sync.WaitGroup)
 			s.AddObject(v)
 			s.AddRelationship(ph, v)
 			s.AddRelationship(ps, v)
+		case *ActiveDirectory:
+			s.AddObject(v)
+			s.AddRelationship(ph, v)
+			s.AddRelationship(ps, v)
 		}
 		if psidx == 0 {
 			s.AddRelationship(ph.Conn, ps)
@@ -412,6 +420,8 @@ func DependencyType(d Dependency) string {
 		return ObjectTypeCommand.String()
 	case *DNSRecord:
 		return ObjectTypeDNSRecord.String()
+	case *ActiveDirectory:
+		return ObjectTypeActiveDirectory.String()
 	case *Host:
 		return "host"
 	case *Network:
@@ -446,6 +456,8 @@ func DependencyCost(d Dependency) int64 {
 		return int64(999999)
 	case *DNSRecord:
 		return int64(999999)
+	case *ActiveDirectory:
+		return int64(999999)
 	case *Host:
 		return int64(0)
 	case *Network:
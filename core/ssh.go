@@ -9,7 +9,9 @@ import (
 	"math/rand"
 	"net"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -400,6 +402,257 @@ func (s *SSHClient) DeleteScriptV2(remotefile string) error {
 	return nil
 }
 
+// DownloadV2 uses the 3rd party pkg/sftp Go package to download src from the remote host into dst. src may be a glob
+// pattern. Directories are only downloaded when recursive is set. If dst is an existing directory, or src matches more
+// than one file, everything is downloaded into dst, otherwise the single match is written to dst.
+func (s *SSHClient) DownloadV2(src, dst string, recursive bool) error {
+	sftp, err := sftp.NewClient(s.client)
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec,errcheck
+	defer sftp.Close()
+
+	matches, err := sftp.Glob(src)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no remote files matched %s", src)
+	}
+
+	toDir := len(matches) > 1 || isDownloadDir(dst, src)
+	if toDir {
+		err = os.MkdirAll(dst, 0755)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, match := range matches {
+		fi, err := sftp.Stat(match)
+		if err != nil {
+			return err
+		}
+
+		target := dst
+		if toDir {
+			target = filepath.Join(dst, path.Base(match))
+		}
+
+		if !fi.IsDir() {
+			err = sftpDownloadFile(sftp, match, target, fi.Mode())
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !recursive {
+			return fmt.Errorf("%s is a directory (recursive download was not requested)", match)
+		}
+
+		walker := sftp.Walk(match)
+		for walker.Step() {
+			if walker.Err() != nil {
+				return walker.Err()
+			}
+			rel := strings.TrimPrefix(strings.TrimPrefix(walker.Path(), match), "/")
+			local := filepath.Join(target, filepath.FromSlash(rel))
+			if walker.Stat().IsDir() {
+				err = os.MkdirAll(local, 0755)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			err = sftpDownloadFile(sftp, walker.Path(), local, walker.Stat().Mode())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func sftpDownloadFile(client *sftp.Client, src, dst string, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(dst), 0755)
+	if err != nil {
+		return err
+	}
+
+	remote, err := client.Open(src)
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec,errcheck
+	defer remote.Close()
+
+	//nolint:gosec
+	local, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(local, remote)
+	if err != nil {
+		//nolint:gosec,errcheck
+		local.Close()
+		return err
+	}
+
+	return local.Close()
+}
+
+// DownloadSCP downloads src from the remote host into dst using the sink side of the SCP protocol. src is expanded
+// by the remote shell, so it may contain glob patterns. Directories are only downloaded when recursive is set.
+func (s *SSHClient) DownloadSCP(src, dst string, recursive bool) error {
+	toDir := isDownloadDir(dst, src)
+	if toDir {
+		err := os.MkdirAll(dst, 0755)
+		if err != nil {
+			return err
+		}
+	}
+
+	scpCommand := "scp -f "
+	if recursive {
+		scpCommand = "scp -rf "
+	}
+
+	scpFunc := func(w io.Writer, r *bufio.Reader) error {
+		return scpDownload(dst, toDir, w, r)
+	}
+
+	return s.scpSession(scpCommand+src, scpFunc)
+}
+
+// scpDownload reads the file and directory records sent by a remote scp source, writing them into dst.
+func scpDownload(dst string, toDir bool, w io.Writer, r *bufio.Reader) error {
+	dirs := []string{}
+	target := func(name string) string {
+		if len(dirs) > 0 {
+			return filepath.Join(dirs[len(dirs)-1], name)
+		}
+		if toDir {
+			return filepath.Join(dst, name)
+		}
+		return dst
+	}
+
+	fmt.Fprint(w, "\x00")
+	for {
+		line, err := r.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			continue
+		}
+
+		switch line[0] {
+		case '\x01', '\x02':
+			return errors.New(strings.TrimSpace(line[1:]))
+		case 'T':
+			fmt.Fprint(w, "\x00")
+		case 'E':
+			if len(dirs) == 0 {
+				return errors.New("scp source ended a directory that was never started")
+			}
+			dirs = dirs[:len(dirs)-1]
+			fmt.Fprint(w, "\x00")
+		case 'C', 'D':
+			parts := strings.SplitN(line[1:], " ", 3)
+			if len(parts) != 3 {
+				return fmt.Errorf("malformed scp record: %q", line)
+			}
+			mode, err := strconv.ParseUint(parts[0], 8, 32)
+			if err != nil {
+				return fmt.Errorf("malformed scp mode: %q", line)
+			}
+			size, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("malformed scp size: %q", line)
+			}
+			name := parts[2]
+			if name == "." || name == ".." || strings.ContainsAny(name, "/\\") {
+				return fmt.Errorf("scp source sent an invalid file name: %q", name)
+			}
+
+			if line[0] == 'D' {
+				dir := target(name)
+				err = os.MkdirAll(dir, 0755)
+				if err != nil {
+					return err
+				}
+				dirs = append(dirs, dir)
+				fmt.Fprint(w, "\x00")
+				continue
+			}
+
+			err = scpDownloadFile(target(name), os.FileMode(mode), size, w, r)
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unexpected scp record: %q", line)
+		}
+	}
+}
+
+func scpDownloadFile(dst string, mode os.FileMode, size int64, w io.Writer, r *bufio.Reader) error {
+	err := os.MkdirAll(filepath.Dir(dst), 0755)
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(w, "\x00")
+	_, err = io.CopyN(f, r, size)
+	if err != nil {
+		//nolint:gosec,errcheck
+		f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	if err := checkSCPStatus(r); err != nil {
+		return err
+	}
+
+	fmt.Fprint(w, "\x00")
+	return nil
+}
+
+// isDownloadDir determines if a download of src should be placed inside of dst rather than written to it. This is
+// the case when dst is an existing directory, ends in a path separator, or src is a glob pattern.
+func isDownloadDir(dst, src string) bool {
+	if strings.HasSuffix(dst, "/") || strings.HasSuffix(dst, string(os.PathSeparator)) {
+		return true
+	}
+	if strings.ContainsAny(src, "*?[") {
+		return true
+	}
+	fi, err := os.Stat(dst)
+	return err == nil && fi.IsDir()
+}
+
 // Upload implementation of communicator.Communicator interface
 func (s *SSHClient) Upload(path string, input io.Reader) error {
 	targetDir := filepath.Dir(path)
@@ -0,0 +1,224 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/masterzen/winrm"
+	"github.com/pkg/errors"
+)
+
+// WinRMDownloadChunkSize is the number of bytes of a remote file fetched by each WinRM command during a download.
+var WinRMDownloadChunkSize int64 = 512 * 1024
+
+var winrmListTemplate = `$ErrorActionPreference = 'Stop'
+foreach ($i in @(Get-Item -Path '%s' -Force)) {
+  if ($i.PSIsContainer) {
+    if (-not $%t) { throw "$($i.FullName) is a directory (recursive download was not requested)" }
+    Write-Output ("M|D|0|" + $i.FullName)
+    Get-ChildItem -LiteralPath $i.FullName -Recurse -Force | ForEach-Object {
+      if ($_.PSIsContainer) { Write-Output ("D|D|0|" + $_.FullName) } else { Write-Output ("F|F|" + $_.Length + "|" + $_.FullName) }
+    }
+  } else {
+    Write-Output ("M|F|" + $i.Length + "|" + $i.FullName)
+  }
+}`
+
+var winrmChunkTemplate = `$ErrorActionPreference = 'Stop'
+$f = [System.IO.File]::Open('%s', 'Open', 'Read', 'ReadWrite')
+try {
+  [void]$f.Seek(%d, 'Begin')
+  $b = New-Object byte[] %d
+  $r = $f.Read($b, 0, %d)
+  [Convert]::ToBase64String($b, 0, $r)
+} finally {
+  $f.Close()
+}`
+
+type winrmRemoteEntry struct {
+	top   bool
+	isDir bool
+	size  int64
+	path  string
+}
+
+// Download retrieves src from the remote host into dst. Since WinRM has no file transfer facility, the remote files
+// are enumerated and read back in base64 encoded chunks with PowerShell. src may contain PowerShell wildcards.
+// Directories are only downloaded when recursive is set.
+func (w *WinRMClient) Download(src, dst string, recursive bool) error {
+	client, err := w.newClient()
+	if err != nil {
+		return err
+	}
+
+	listing, err := w.runPowershell(client, fmt.Sprintf(winrmListTemplate, powershellQuote(src), recursive))
+	if err != nil {
+		return errors.WithMessage(err, "could not enumerate remote files")
+	}
+
+	entries := []*winrmRemoteEntry{}
+	matches := 0
+	scanner := bufio.NewScanner(strings.NewReader(listing))
+	for scanner.Scan() {
+		parts := strings.SplitN(strings.TrimSpace(scanner.Text()), "|", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		size, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("malformed remote file listing: %q", scanner.Text())
+		}
+		entry := &winrmRemoteEntry{
+			top:   parts[0] == "M",
+			isDir: parts[1] == "D",
+			size:  size,
+			path:  parts[3],
+		}
+		if entry.top {
+			matches++
+		}
+		entries = append(entries, entry)
+	}
+	if matches == 0 {
+		return fmt.Errorf("no remote files matched %s", src)
+	}
+
+	toDir := matches > 1 || isDownloadDir(dst, src)
+	if toDir {
+		err = os.MkdirAll(dst, 0755)
+		if err != nil {
+			return err
+		}
+	}
+
+	var root, target string
+	for _, entry := range entries {
+		local := ""
+		if entry.top {
+			root = entry.path
+			target = dst
+			if toDir {
+				target = filepath.Join(dst, windowsBase(entry.path))
+			}
+			local = target
+		} else {
+			rel := strings.TrimPrefix(strings.TrimPrefix(entry.path, root), `\`)
+			local = filepath.Join(target, filepath.FromSlash(strings.Replace(rel, `\`, `/`, -1)))
+		}
+
+		if entry.isDir {
+			err = os.MkdirAll(local, 0755)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = w.downloadFile(client, entry, local)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("could not download %s", entry.path))
+		}
+	}
+
+	return nil
+}
+
+func (w *WinRMClient) downloadFile(client *winrm.Client, entry *winrmRemoteEntry, dst string) error {
+	err := os.MkdirAll(filepath.Dir(dst), 0755)
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	for offset := int64(0); offset < entry.size; offset += WinRMDownloadChunkSize {
+		chunk, err := w.runPowershell(client, fmt.Sprintf(winrmChunkTemplate, powershellQuote(entry.path), offset, WinRMDownloadChunkSize, WinRMDownloadChunkSize))
+		if err != nil {
+			//nolint:gosec,errcheck
+			f.Close()
+			return err
+		}
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(chunk))
+		if err != nil {
+			//nolint:gosec,errcheck
+			f.Close()
+			return err
+		}
+		_, err = f.Write(data)
+		if err != nil {
+			//nolint:gosec,errcheck
+			f.Close()
+			return err
+		}
+	}
+
+	return f.Close()
+}
+
+func (w *WinRMClient) newClient() (*winrm.Client, error) {
+	if w.Config == nil {
+		return nil, errors.New("nil auth config provised")
+	}
+	endpoint := winrm.NewEndpoint(
+		w.Config.RemoteAddr,
+		w.Config.Port,
+		w.Config.HTTPS,
+		w.Config.SkipVerify,
+		nil,
+		nil,
+		nil,
+		(time.Duration(DefaultWinRMTimeout) * time.Second),
+	)
+
+	transporter := &AdvancedTransporter{
+		auth:    w.Config,
+		Timeout: DefaultWinRMTimeout,
+	}
+
+	params := winrm.DefaultParameters
+	params.TransportDecorator = func() winrm.Transporter { return transporter }
+	client, err := winrm.NewClientWithParameters(endpoint, w.Config.User, w.Config.Password, params)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not create winrm client")
+	}
+	return client, nil
+}
+
+// runPowershell runs a PowerShell script on the remote host and returns it's standard output
+func (w *WinRMClient) runPowershell(client *winrm.Client, script string) (string, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	cmd := fmt.Sprintf("powershell -NoProfile -NonInteractive -ExecutionPolicy Bypass -EncodedCommand %s", Powershell(script))
+	status, err := client.Run(cmd, stdout, stderr)
+	if err != nil {
+		return "", err
+	}
+	if status != 0 {
+		return "", fmt.Errorf("powershell exited with status %d: %s", status, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// powershellQuote escapes a string for use inside of a single quoted PowerShell string
+func powershellQuote(s string) string {
+	return strings.Replace(s, `'`, `''`, -1)
+}
+
+func windowsBase(p string) string {
+	p = strings.TrimRight(p, `\`)
+	if idx := strings.LastIndex(p, `\`); idx >= 0 {
+		return p[idx+1:]
+	}
+	return p
+}
@@ -0,0 +1,348 @@
+// Package dnsserver implements a small in-process authoritative DNS server which holds it's zones in memory and accepts
+// RFC 2136 dynamic updates (optionally TSIG signed). It is bundled so the dns_record provisioner can be exercised in
+// tests, or against a local environment, without running a real name server.
+package dnsserver
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// DefaultListenAddr is the address the server listens on when Start is given an empty address
+const DefaultListenAddr = `127.0.0.1:0`
+
+// Server is an authoritative DNS server for a set of zones held in memory
+type Server struct {
+	sync.RWMutex
+
+	// TSIGSecrets maps fully qualified TSIG key names to their base64 encoded secrets. When it is not empty, every
+	// update must be signed with one of the keys. It must be populated before the server is started.
+	TSIGSecrets map[string]string
+
+	zones map[string]*zone
+	udp   *dns.Server
+	tcp   *dns.Server
+	addr  string
+}
+
+// zone holds the records of a single zone by their lower case owner name
+type zone struct {
+	name    string
+	records map[string][]dns.RR
+}
+
+// New creates a server which is authoritative for the provided zones
+func New(zones ...string) *Server {
+	s := &Server{
+		TSIGSecrets: map[string]string{},
+		zones:       map[string]*zone{},
+	}
+	for _, z := range zones {
+		s.AddZone(z)
+	}
+	return s
+}
+
+// AddZone makes the server authoritative for an (empty) zone
+func (s *Server) AddZone(name string) {
+	s.Lock()
+	defer s.Unlock()
+	name = canonical(name)
+	if _, found := s.zones[name]; found {
+		return
+	}
+	s.zones[name] = &zone{
+		name:    name,
+		records: map[string][]dns.RR{},
+	}
+}
+
+// AddTSIGKey requires updates to be signed, accepting the provided key. It must be called before the server is started.
+func (s *Server) AddTSIGKey(name, secret string) {
+	s.Lock()
+	defer s.Unlock()
+	s.TSIGSecrets[dns.Fqdn(name)] = secret
+}
+
+// listen binds udp and tcp listeners to the same port of addr. When the system picks the port, the port it picked for
+// udp may already be in use for tcp, so a few other ports are tried.
+func listen(addr string) (net.PacketConn, net.Listener, error) {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid listen address %s", addr)
+	}
+	for attempt := 1; ; attempt++ {
+		pc, err := net.ListenPacket("udp", addr)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "could not listen on udp %s", addr)
+		}
+		l, err := net.Listen("tcp", pc.LocalAddr().String())
+		if err == nil {
+			return pc, l, nil
+		}
+		//nolint:errcheck
+		pc.Close()
+		if port != "0" || attempt == 10 {
+			return nil, nil, errors.Wrapf(err, "could not listen on tcp %s", pc.LocalAddr().String())
+		}
+	}
+}
+
+// Start listens on addr over both UDP and TCP (on the same port) and serves requests in the background until the
+// server is closed. An address with a port of 0 listens on a random port, which Addr returns once started.
+func (s *Server) Start(addr string) error {
+	if addr == "" {
+		addr = DefaultListenAddr
+	}
+	pc, l, err := listen(addr)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	secrets := map[string]string{}
+	for k, v := range s.TSIGSecrets {
+		secrets[k] = v
+	}
+	started := make(chan struct{}, 2)
+	notify := func() {
+		started <- struct{}{}
+	}
+	s.addr = pc.LocalAddr().String()
+	s.udp = &dns.Server{PacketConn: pc, Handler: s, TsigSecret: secrets, MsgAcceptFunc: acceptMsg, NotifyStartedFunc: notify}
+	s.tcp = &dns.Server{Listener: l, Handler: s, TsigSecret: secrets, MsgAcceptFunc: acceptMsg, NotifyStartedFunc: notify}
+	s.Unlock()
+
+	errs := make(chan error, 2)
+	for _, srv := range []*dns.Server{s.udp, s.tcp} {
+		go func(srv *dns.Server) {
+			errs <- srv.ActivateAndServe()
+		}(srv)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case err := <-errs:
+			//nolint:errcheck
+			s.Close()
+			return errors.Wrap(err, "dns server failed to start")
+		}
+	}
+	return nil
+}
+
+// Addr returns the host:port address the server is listening on
+func (s *Server) Addr() string {
+	s.RLock()
+	defer s.RUnlock()
+	return s.addr
+}
+
+// Close stops the server
+func (s *Server) Close() error {
+	s.Lock()
+	udp, tcp := s.udp, s.tcp
+	s.udp, s.tcp = nil, nil
+	s.Unlock()
+	var err error
+	for _, srv := range []*dns.Server{udp, tcp} {
+		if srv == nil {
+			continue
+		}
+		if serr := srv.Shutdown(); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+// Records returns copies of the records of a type held for name (every record of name for dns.TypeANY)
+func (s *Server) Records(name string, rrtype uint16) []dns.RR {
+	s.RLock()
+	defer s.RUnlock()
+	name = canonical(name)
+	z := s.zoneOf(name)
+	if z == nil {
+		return nil
+	}
+	rrs := []dns.RR{}
+	for _, rr := range z.records[name] {
+		if rrtype == dns.TypeANY || rr.Header().Rrtype == rrtype {
+			rrs = append(rrs, dns.Copy(rr))
+		}
+	}
+	return rrs
+}
+
+// Values returns the sorted record data (such as the addresses of A records) of the records of a type held for name
+func (s *Server) Values(name string, rrtype uint16) []string {
+	vals := []string{}
+	for _, rr := range s.Records(name, rrtype) {
+		vals = append(vals, rdata(rr))
+	}
+	sort.Strings(vals)
+	return vals
+}
+
+// acceptMsg accepts update requests, which the dns package rejects by default, along with every message it does accept
+func acceptMsg(dh dns.Header) dns.MsgAcceptAction {
+	const qr = 1 << 15
+	if dh.Bits&qr == 0 && int(dh.Bits>>11)&0xF == dns.OpcodeUpdate {
+		return dns.MsgAccept
+	}
+	return dns.DefaultMsgAcceptFunc(dh)
+}
+
+// ServeDNS implements the dns.Handler interface
+func (s *Server) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	m := new(dns.Msg)
+	switch {
+	case len(r.Question) != 1:
+		m.SetRcode(r, dns.RcodeFormatError)
+	case r.Opcode == dns.OpcodeUpdate:
+		m.SetRcode(r, s.update(w, r))
+	case r.Opcode == dns.OpcodeQuery:
+		s.query(r, m)
+	default:
+		m.SetRcode(r, dns.RcodeNotImplemented)
+	}
+	// NOTAUTH responses are left unsigned, as the dns package treats every signed NOTAUTH as a TSIG failure
+	if t := r.IsTsig(); t != nil && w.TsigStatus() == nil && m.Rcode != dns.RcodeNotAuth {
+		m.SetTsig(t.Hdr.Name, t.Algorithm, 300, time.Now().Unix())
+	}
+	//nolint:errcheck
+	w.WriteMsg(m)
+}
+
+// update applies the update section of an RFC 2136 update message, returning the rcode of the response. Prerequisites
+// are not supported.
+func (s *Server) update(w dns.ResponseWriter, r *dns.Msg) int {
+	s.Lock()
+	defer s.Unlock()
+	if len(s.TSIGSecrets) > 0 && (r.IsTsig() == nil || w.TsigStatus() != nil) {
+		return dns.RcodeNotAuth
+	}
+	z, found := s.zones[canonical(r.Question[0].Name)]
+	if !found {
+		return dns.RcodeNotAuth
+	}
+	if len(r.Answer) > 0 {
+		return dns.RcodeNotImplemented
+	}
+
+	// the whole update is checked before any of it is applied so that updates are atomic
+	for _, rr := range r.Ns {
+		h := rr.Header()
+		if !dns.IsSubDomain(z.name, canonical(h.Name)) {
+			return dns.RcodeNotZone
+		}
+		switch h.Class {
+		case dns.ClassINET, dns.ClassANY, dns.ClassNONE:
+		default:
+			return dns.RcodeFormatError
+		}
+	}
+	for _, rr := range r.Ns {
+		h := rr.Header()
+		name := canonical(h.Name)
+		switch h.Class {
+		case dns.ClassINET:
+			z.add(name, rr)
+		case dns.ClassANY:
+			z.remove(name, h.Rrtype, nil)
+		case dns.ClassNONE:
+			z.remove(name, h.Rrtype, rr)
+		}
+	}
+	return dns.RcodeSuccess
+}
+
+// query answers a standard query from the zone the question falls within
+func (s *Server) query(r *dns.Msg, m *dns.Msg) {
+	s.RLock()
+	defer s.RUnlock()
+	q := r.Question[0]
+	name := canonical(q.Name)
+	z := s.zoneOf(name)
+	if z == nil {
+		m.SetRcode(r, dns.RcodeRefused)
+		return
+	}
+	records, found := z.records[name]
+	if !found && name != z.name {
+		m.SetRcode(r, dns.RcodeNameError)
+		m.Authoritative = true
+		return
+	}
+	m.SetReply(r)
+	m.Authoritative = true
+	for _, rr := range records {
+		t := rr.Header().Rrtype
+		if t == q.Qtype || q.Qtype == dns.TypeANY || t == dns.TypeCNAME {
+			m.Answer = append(m.Answer, dns.Copy(rr))
+		}
+	}
+}
+
+// zoneOf returns the most specific zone name falls within
+func (s *Server) zoneOf(name string) *zone {
+	var match *zone
+	for zname, z := range s.zones {
+		if !dns.IsSubDomain(zname, name) {
+			continue
+		}
+		if match == nil || len(zname) > len(match.name) {
+			match = z
+		}
+	}
+	return match
+}
+
+// add adds a record to the zone, replacing an existing record with the same data (updating it's TTL)
+func (z *zone) add(name string, rr dns.RR) {
+	rr = dns.Copy(rr)
+	rr.Header().Name = name
+	rr.Header().Class = dns.ClassINET
+	for i, x := range z.records[name] {
+		if x.Header().Rrtype == rr.Header().Rrtype && rdata(x) == rdata(rr) {
+			z.records[name][i] = rr
+			return
+		}
+	}
+	z.records[name] = append(z.records[name], rr)
+}
+
+// remove deletes the records of a type (every type for dns.TypeANY) from name, or only the record with the same data
+// as match when it is not nil
+func (z *zone) remove(name string, rrtype uint16, match dns.RR) {
+	kept := []dns.RR{}
+	for _, x := range z.records[name] {
+		if rrtype != dns.TypeANY && x.Header().Rrtype != rrtype {
+			kept = append(kept, x)
+			continue
+		}
+		if match != nil && rdata(x) != rdata(match) {
+			kept = append(kept, x)
+		}
+	}
+	if len(kept) == 0 {
+		delete(z.records, name)
+		return
+	}
+	z.records[name] = kept
+}
+
+// rdata returns the presentation format of a record without it's header
+func rdata(rr dns.RR) string {
+	return strings.TrimPrefix(rr.String(), rr.Header().String())
+}
+
+func canonical(name string) string {
+	return strings.ToLower(dns.Fqdn(name))
+}
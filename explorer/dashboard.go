@@ -0,0 +1,486 @@
+package explorer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hashicorp/terraform/tfdiags"
+	"github.com/rivo/tview"
+
+	"github.com/gen0cide/laforge/core"
+	"github.com/gen0cide/laforge/core/cli"
+)
+
+var (
+	// DashboardOutputLines caps how many lines of output the dashboard keeps for each step
+	DashboardOutputLines = 2000
+
+	dashboardPage = "*dashboard*"
+	stepsPage     = "*steps*"
+	outputPage    = "*output*"
+)
+
+// stepProgress is the dashboard's view of a single task of the plan
+type stepProgress struct {
+	ID        string
+	Name      string
+	Status    core.JobStatus
+	Timeout   int
+	StartedAt time.Time
+	EndedAt   time.Time
+	Error     string
+	Output    []string
+}
+
+// elapsed returns how long the step has been running, or how long it ran for once it has finished
+func (s *stepProgress) elapsed() time.Duration {
+	if s.StartedAt.IsZero() {
+		return 0
+	}
+	if s.EndedAt.IsZero() {
+		return time.Since(s.StartedAt).Round(time.Second)
+	}
+	return s.EndedAt.Sub(s.StartedAt).Round(time.Second)
+}
+
+// timing formats the step's elapsed time against it's timeout
+func (s *stepProgress) timing() string {
+	return fmt.Sprintf("%s/%s", s.elapsed(), time.Duration(s.Timeout)*time.Second)
+}
+
+// hostProgress is a cell of the dashboard - the steps of one host of one team, in the order they are performed
+type hostProgress struct {
+	ID    string
+	Steps []*stepProgress
+}
+
+// current returns the step that best describes what the host is doing: the most recently started step that is still
+// in progress, otherwise the most recent failure, otherwise the most recently finished step.
+func (h *hostProgress) current() *stepProgress {
+	var running, failed, finished *stepProgress
+	for _, s := range h.Steps {
+		switch s.Status {
+		case core.JobStatusInProgress:
+			if running == nil || s.StartedAt.After(running.StartedAt) {
+				running = s
+			}
+		case core.JobStatusFailed:
+			if failed == nil || s.EndedAt.After(failed.EndedAt) {
+				failed = s
+			}
+		case core.JobStatusSuccessful:
+			if finished == nil || s.EndedAt.After(finished.EndedAt) {
+				finished = s
+			}
+		}
+	}
+	switch {
+	case running != nil:
+		return running
+	case failed != nil:
+		return failed
+	}
+	return finished
+}
+
+// completed returns how many of the host's steps finished successfully
+func (h *hostProgress) completed() int {
+	count := 0
+	for _, s := range h.Steps {
+		if s.Status == core.JobStatusSuccessful {
+			count++
+		}
+	}
+	return count
+}
+
+// Dashboard is a terminal UI which shows the progress of an apply as a grid of teams and hosts. It implements the
+// core.Monitor interface.
+type Dashboard struct {
+	sync.Mutex
+	plan     *core.Plan
+	app      *tview.Application
+	pages    *tview.Pages
+	grid     *tview.Table
+	details  *tview.TextView
+	logs     *tview.TextView
+	steps    *tview.List
+	output   *tview.TextView
+	teams    []string
+	hosts    []string
+	cells    map[string]map[string]*hostProgress
+	progress map[string]*stepProgress
+	watching string
+	finished bool
+	summary  string
+	stopped  int32
+}
+
+// NewDashboard creates a dashboard for the tainted tasks of a plan. The plan's tasks must already be set up.
+func NewDashboard(plan *core.Plan) *Dashboard {
+	d := &Dashboard{
+		plan:     plan,
+		app:      tview.NewApplication(),
+		cells:    map[string]map[string]*hostProgress{},
+		progress: map[string]*stepProgress{},
+	}
+
+	teams := map[string]bool{}
+	hosts := map[string]bool{}
+	for _, id := range plan.GlobalOrder {
+		task, ok := plan.Tasks[id]
+		if !ok || !plan.Tainted[id] {
+			continue
+		}
+		team := core.PathPrefix(id, "teams")
+		host := core.PathPrefix(id, "hosts")
+		if team == "" || host == "" {
+			continue
+		}
+		key := strings.TrimPrefix(host, team+"/")
+		teams[team] = true
+		hosts[key] = true
+		if d.cells[team] == nil {
+			d.cells[team] = map[string]*hostProgress{}
+		}
+		cell, ok := d.cells[team][key]
+		if !ok {
+			cell = &hostProgress{ID: host}
+			d.cells[team][key] = cell
+		}
+		step := &stepProgress{
+			ID:      id,
+			Name:    path.Base(id),
+			Status:  core.JobStatusEnqueued,
+			Timeout: task.GetTimeout(),
+		}
+		cell.Steps = append(cell.Steps, step)
+		d.progress[id] = step
+	}
+	for team := range teams {
+		d.teams = append(d.teams, team)
+	}
+	for host := range hosts {
+		d.hosts = append(d.hosts, host)
+	}
+	sort.Strings(d.teams)
+	sort.Strings(d.hosts)
+
+	d.layout()
+	d.render()
+	if len(d.teams) > 0 {
+		d.grid.Select(1, 1)
+	}
+	return d
+}
+
+// hostLabel shortens a host's path relative to it's team (networks/corp/hosts/dc) to network/host (corp/dc)
+func hostLabel(key string) string {
+	network := core.PathPrefix(key, "networks")
+	if network == "" {
+		return path.Base(key)
+	}
+	return path.Join(path.Base(network), path.Base(key))
+}
+
+func statusColor(status core.JobStatus) string {
+	switch status {
+	case core.JobStatusInProgress:
+		return "yellow"
+	case core.JobStatusFailed:
+		return "red"
+	case core.JobStatusSuccessful:
+		return "green"
+	}
+	return "white"
+}
+
+func (d *Dashboard) layout() {
+	d.grid = tview.NewTable().SetFixed(1, 1).SetSelectable(true, true)
+	d.grid.SetBorder(true).SetTitle("Apply")
+	d.grid.SetSelectionChangedFunc(func(row, column int) {
+		d.renderDetails()
+	})
+	d.grid.SetSelectedFunc(func(row, column int) {
+		cell := d.cell(row, column)
+		if cell == nil {
+			return
+		}
+		d.showSteps(cell)
+	})
+	d.grid.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'q' {
+			d.app.Stop()
+			return nil
+		}
+		return event
+	})
+
+	d.details = tview.NewTextView().SetDynamicColors(true)
+	d.details.SetBorder(true).SetTitle("Host")
+
+	d.logs = tview.NewTextView().SetDynamicColors(true).SetScrollable(true)
+	d.logs.SetBorder(true).SetTitle("Log")
+	d.logs.SetChangedFunc(func() {
+		d.queue(func() {})
+	})
+
+	top := tview.NewFlex().
+		AddItem(d.grid, 0, 3, true).
+		AddItem(d.details, 0, 2, false)
+	dashboard := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(top, 0, 3, true).
+		AddItem(d.logs, 10, 0, false)
+
+	d.steps = tview.NewList()
+	d.steps.SetBorder(true)
+	d.steps.SetDoneFunc(func() {
+		d.pages.SwitchToPage(dashboardPage)
+		d.app.SetFocus(d.grid)
+	})
+
+	d.output = tview.NewTextView().SetDynamicColors(true).SetScrollable(true)
+	d.output.SetBorder(true)
+	d.output.SetDoneFunc(func(key tcell.Key) {
+		d.Lock()
+		d.watching = ""
+		d.Unlock()
+		d.pages.SwitchToPage(stepsPage)
+		d.app.SetFocus(d.steps)
+	})
+
+	d.pages = tview.NewPages().
+		AddPage(dashboardPage, dashboard, true, true).
+		AddPage(stepsPage, d.steps, true, false).
+		AddPage(outputPage, d.output, true, false)
+	d.app.SetRoot(d.pages, true)
+}
+
+// cell returns the host shown in a cell of the grid
+func (d *Dashboard) cell(row, column int) *hostProgress {
+	if row < 1 || column < 1 || row > len(d.teams) || column > len(d.hosts) {
+		return nil
+	}
+	return d.cells[d.teams[row-1]][d.hosts[column-1]]
+}
+
+// render redraws the grid and the details of the selected host. It must be called from the UI goroutine.
+func (d *Dashboard) render() {
+	d.Lock()
+	for i, host := range d.hosts {
+		d.grid.SetCell(0, i+1, tview.NewTableCell(hostLabel(host)).SetSelectable(false).SetTextColor(tcell.ColorYellow))
+	}
+	for r, team := range d.teams {
+		d.grid.SetCell(r+1, 0, tview.NewTableCell(path.Base(team)).SetSelectable(false).SetTextColor(tcell.ColorYellow))
+		for c, key := range d.hosts {
+			cell := d.cells[team][key]
+			if cell == nil {
+				d.grid.SetCell(r+1, c+1, tview.NewTableCell("").SetSelectable(false))
+				continue
+			}
+			d.grid.SetCell(r+1, c+1, tview.NewTableCell(cellText(cell)).SetMaxWidth(48))
+		}
+	}
+	title := "Apply (enter: steps, q: quit)"
+	if d.finished {
+		title = fmt.Sprintf("Apply %s (q: quit)", d.summary)
+	}
+	d.grid.SetTitle(title)
+	d.Unlock()
+	d.renderDetails()
+}
+
+// cellText summarizes a host in a single line of the grid
+func cellText(cell *hostProgress) string {
+	progress := fmt.Sprintf("%d/%d", cell.completed(), len(cell.Steps))
+	step := cell.current()
+	if step == nil {
+		return fmt.Sprintf("[gray]%s waiting", progress)
+	}
+	text := fmt.Sprintf("[%s]%s %s %s %s", statusColor(step.Status), progress, tview.Escape(step.Name), step.Status, step.timing())
+	if step.Error != "" {
+		text += " " + tview.Escape(step.Error)
+	}
+	return text
+}
+
+// renderDetails shows every step of the host selected in the grid
+func (d *Dashboard) renderDetails() {
+	d.Lock()
+	defer d.Unlock()
+	d.details.Clear()
+	cell := d.cell(d.grid.GetSelection())
+	if cell == nil {
+		return
+	}
+	d.details.SetTitle(cell.ID)
+	for _, s := range cell.Steps {
+		fmt.Fprintf(d.details, "[%s]%-11s[-] %s %s\n", statusColor(s.Status), s.Status, tview.Escape(s.Name), s.timing())
+		if s.Error != "" {
+			fmt.Fprintf(d.details, "            [red]%s[-]\n", tview.Escape(s.Error))
+		}
+	}
+}
+
+// showSteps lists the steps of a host so one can be chosen to watch it's output
+func (d *Dashboard) showSteps(cell *hostProgress) {
+	d.Lock()
+	defer d.Unlock()
+	d.steps.Clear()
+	d.steps.SetTitle(cell.ID + " (enter: output, esc: back)")
+	for _, s := range cell.Steps {
+		step := s
+		secondary := fmt.Sprintf("%s %s %s", step.Status, step.timing(), step.Error)
+		d.steps.AddItem(step.Name, secondary, 0, func() {
+			d.watch(step)
+		})
+	}
+	if current := cell.current(); current != nil {
+		for i, s := range cell.Steps {
+			if s == current {
+				d.steps.SetCurrentItem(i)
+			}
+		}
+	}
+	d.pages.SwitchToPage(stepsPage)
+	d.app.SetFocus(d.steps)
+}
+
+// watch shows the output of a step, following any new lines as they are written
+func (d *Dashboard) watch(step *stepProgress) {
+	d.Lock()
+	d.watching = step.ID
+	d.output.Clear()
+	d.output.SetTitle(step.ID + " (esc: back)")
+	for _, line := range step.Output {
+		fmt.Fprintln(d.output, line)
+	}
+	d.Unlock()
+	d.output.ScrollToEnd()
+	d.pages.SwitchToPage(outputPage)
+	d.app.SetFocus(d.output)
+}
+
+// TaskStatus implements the core.Monitor interface
+func (d *Dashboard) TaskStatus(id string, task core.Doer, status core.JobStatus, cause error) {
+	d.Lock()
+	step, ok := d.progress[id]
+	if ok {
+		step.Status = status
+		switch status {
+		case core.JobStatusInProgress:
+			step.StartedAt = time.Now()
+			step.EndedAt = time.Time{}
+			step.Error = ""
+			step.Timeout = task.GetTimeout()
+		case core.JobStatusFailed, core.JobStatusSuccessful:
+			step.EndedAt = time.Now()
+		}
+		if cause != nil {
+			step.Error = cause.Error()
+		}
+	}
+	d.Unlock()
+	if ok {
+		d.queue(d.render)
+	}
+}
+
+// TaskOutput implements the core.Monitor interface
+func (d *Dashboard) TaskOutput(id string, stream string, line string) {
+	line = tview.Escape(line)
+	if stream == core.StreamStderr {
+		line = "[red]" + line + "[-]"
+	}
+	d.Lock()
+	step, ok := d.progress[id]
+	if ok {
+		step.Output = append(step.Output, line)
+		if len(step.Output) > DashboardOutputLines {
+			step.Output = step.Output[len(step.Output)-DashboardOutputLines:]
+		}
+	}
+	watched := ok && d.watching == id
+	d.Unlock()
+	if watched {
+		d.queue(func() {
+			fmt.Fprintln(d.output, line)
+		})
+	}
+}
+
+// queue runs f on the UI goroutine and redraws the screen, unless the dashboard has been closed
+func (d *Dashboard) queue(f func()) {
+	if atomic.LoadInt32(&d.stopped) == 1 {
+		return
+	}
+	d.app.QueueUpdateDraw(f)
+}
+
+// tick redraws the dashboard every second so the elapsed time of running steps stays current
+func (d *Dashboard) tick() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if atomic.LoadInt32(&d.stopped) == 1 {
+			return
+		}
+		d.queue(d.render)
+	}
+}
+
+// finish records the outcome of the apply on the dashboard
+func (d *Dashboard) finish(diags tfdiags.Diagnostics) {
+	d.Lock()
+	failed := 0
+	for _, s := range d.progress {
+		if s.Status == core.JobStatusFailed {
+			failed++
+		}
+	}
+	d.finished = true
+	if diags.HasErrors() {
+		d.summary = fmt.Sprintf("failed: %d steps failed, %d errors", failed, len(diags))
+	} else {
+		d.summary = "completed successfully"
+	}
+	d.Unlock()
+	d.queue(d.render)
+}
+
+// RunApplyDashboard executes a plan while showing it's progress on a dashboard, returning the plan's diagnostics. If the
+// dashboard is closed before the plan has finished, the apply continues with it's logs written to the terminal.
+func RunApplyDashboard(plan *core.Plan) tfdiags.Diagnostics {
+	d := NewDashboard(plan)
+	plan.Monitor = d
+	cli.SetLogOutput(tview.ANSIWriter(d.logs))
+
+	done := make(chan tfdiags.Diagnostics, 1)
+	go func() {
+		diags := plan.Execute()
+		d.finish(diags)
+		done <- diags
+	}()
+	go d.tick()
+
+	err := d.app.Run()
+	atomic.StoreInt32(&d.stopped, 1)
+	cli.SetLogOutput(nil)
+	if err != nil {
+		cli.Logger.Errorf("Apply dashboard failed: %v", err)
+	}
+
+	select {
+	case diags := <-done:
+		return diags
+	default:
+	}
+	cli.Logger.Warnf("Dashboard closed, waiting for the apply to finish")
+	return <-done
+}
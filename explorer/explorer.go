@@ -67,14 +67,14 @@ func (sa *statusapp) finder(base *core.Laforge) {
 		sa.app.SetFocus(objlist)
 		objlist.SetChangedFunc(func(i int, name string, t string, s rune) {
 			node := tview.NewTreeNode(name)
-			node.SetReference(base.Competition)
+			node.SetReference(base.CurrentCompetition)
 			objtree.SetRoot(node)
 			objtree.SetCurrentNode(node)
 		})
 		objlist.SetCurrentItem(0)
 		objlist.SetSelectedFunc(func(i int, name string, t string, s rune) {
 			node := tview.NewTreeNode(name)
-			node.SetReference(base.Competition)
+			node.SetReference(base.CurrentCompetition)
 			objtree.SetRoot(node)
 			objtree.SetCurrentNode(node)
 		})
@@ -86,14 +86,14 @@ func (sa *statusapp) finder(base *core.Laforge) {
 		sa.app.SetFocus(objlist)
 		objlist.SetChangedFunc(func(i int, name string, t string, s rune) {
 			node := tview.NewTreeNode(name)
-			node.SetReference(base.Environment)
+			node.SetReference(base.CurrentEnv)
 			objtree.SetRoot(node)
 			objtree.SetCurrentNode(node)
 		})
 		objlist.SetCurrentItem(0)
 		objlist.SetSelectedFunc(func(i int, name string, t string, s rune) {
 			node := tview.NewTreeNode(name)
-			node.SetReference(base.Environment)
+			node.SetReference(base.CurrentEnv)
 			objtree.SetRoot(node)
 			objtree.SetCurrentNode(node)
 		})
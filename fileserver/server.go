@@ -0,0 +1,285 @@
+// Package fileserver implements the build scoped HTTP server started by `laforge serve`. It serves the rendered
+// scripts and remote file assets of a build so that provisioned hosts can pull their own provisioning steps rather
+// than having them pushed over SSH or WinRM.
+package fileserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gen0cide/laforge/core"
+	"github.com/gen0cide/laforge/core/cli"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// DefaultListenAddr is the address the file server listens on by default
+const DefaultListenAddr = `0.0.0.0:9970`
+
+// Server serves the provisioning steps of every provisioned host within a build
+type Server struct {
+	sync.Mutex
+
+	// Base is the laforge base of the build being served
+	Base *core.Laforge
+
+	// Tokens authenticates requests on behalf of a provisioned host
+	Tokens *TokenSet
+
+	// Hosts indexes the provisioned hosts of the build by their HostKey
+	Hosts map[string]*core.ProvisionedHost
+
+	// FetchLog receives a JSON line for every step that is fetched (optional)
+	FetchLog io.Writer
+}
+
+// Manifest describes the provisioning steps of a single provisioned host
+type Manifest struct {
+	ProvisionedHostID string          `json:"provisioned_host_id"`
+	Hostname          string          `json:"hostname"`
+	OS                string          `json:"os"`
+	Steps             []*ManifestStep `json:"steps"`
+}
+
+// ManifestStep describes a single provisioning step and where it's content can be fetched from
+type ManifestStep struct {
+	StepNumber      int    `json:"step_number"`
+	ProvisionerType string `json:"provisioner_type"`
+	ProvisionerID   string `json:"provisioner_id"`
+	URL             string `json:"url,omitempty"`
+	Filename        string `json:"filename,omitempty"`
+	Destination     string `json:"destination,omitempty"`
+	Perms           string `json:"perms,omitempty"`
+	Command         string `json:"command,omitempty"`
+	IgnoreErrors    bool   `json:"ignore_errors,omitempty"`
+	Disabled        bool   `json:"disabled,omitempty"`
+	Timeout         int    `json:"timeout,omitempty"`
+}
+
+// FetchRecord is written to the fetch log whenever a host retrieves a manifest or step
+type FetchRecord struct {
+	Time              time.Time `json:"time"`
+	ProvisionedHostID string    `json:"provisioned_host_id"`
+	StepNumber        int       `json:"step_number"`
+	ProvisionerID     string    `json:"provisioner_id,omitempty"`
+	RemoteAddr        string    `json:"remote_addr"`
+	Bytes             int64     `json:"bytes"`
+}
+
+// HostKey returns the key a provisioned host is served under (team/network/host)
+func HostKey(ph *core.ProvisionedHost) string {
+	return fmt.Sprintf("%d/%s/%s", ph.Team.TeamNumber, ph.ProvisionedNetwork.Base(), ph.Base())
+}
+
+// NewServer indexes the provisioned hosts of the base's current build. The build's teams must already have
+// been created (see core.NewSnapshotFromEnv).
+func NewServer(base *core.Laforge, tokens *TokenSet) (*Server, error) {
+	if base.CurrentEnv == nil || base.CurrentEnv.Build == nil {
+		return nil, errors.New("laforge base does not have a current build")
+	}
+	s := &Server{
+		Base:   base,
+		Tokens: tokens,
+		Hosts:  map[string]*core.ProvisionedHost{},
+	}
+	for _, team := range base.CurrentEnv.Build.Teams {
+		for _, pn := range team.ProvisionedNetworks {
+			for _, ph := range pn.ProvisionedHosts {
+				s.Hosts[HostKey(ph)] = ph
+			}
+		}
+	}
+	if len(s.Hosts) == 0 {
+		return nil, errors.New("the current build does not contain any provisioned hosts")
+	}
+	return s, nil
+}
+
+// Handler returns the HTTP handler of the server
+func (s *Server) Handler() http.Handler {
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	r.GET("/provision/:team/:network/:host", s.ReqGetManifest)
+	r.GET("/provision/:team/:network/:host/steps/:step", s.ReqGetStep)
+
+	return r
+}
+
+// ListenAndServe serves the build on addr until an error occurs
+func (s *Server) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, s.Handler())
+}
+
+// authorize locates the provisioned host of a request and validates it's token
+func (s *Server) authorize(c *gin.Context) (*core.ProvisionedHost, bool) {
+	key := fmt.Sprintf("%s/%s/%s", c.Param("team"), c.Param("network"), c.Param("host"))
+	ph, found := s.Hosts[key]
+	token := c.GetHeader(TokenHeader)
+	if token == "" {
+		token = c.Query("token")
+	}
+	if !found || !s.Tokens.Valid(ph, token) {
+		cli.Logger.Warnf("Rejected unauthorized request for %s from %s", c.Request.URL.Path, c.ClientIP())
+		c.JSON(401, map[string]string{
+			"status":  "error",
+			"message": "unauthorized",
+		})
+		return nil, false
+	}
+	return ph, true
+}
+
+// ReqGetManifest returns the provisioning manifest of a host
+func (s *Server) ReqGetManifest(c *gin.Context) {
+	ph, ok := s.authorize(c)
+	if !ok {
+		return
+	}
+
+	m := &Manifest{
+		ProvisionedHostID: ph.Path(),
+		Hostname:          ph.Host.Hostname,
+		OS:                ph.Host.OS,
+		Steps:             []*ManifestStep{},
+	}
+	for _, ps := range ph.StepsByOffset {
+		m.Steps = append(m.Steps, s.manifestStep(ph, ps))
+	}
+
+	s.logFetch(ph, -1, "", c.ClientIP(), 0)
+	c.JSON(200, m)
+}
+
+func (s *Server) manifestStep(ph *core.ProvisionedHost, ps *core.ProvisioningStep) *ManifestStep {
+	ms := &ManifestStep{
+		StepNumber:      ps.StepNumber,
+		ProvisionerType: ps.ProvisionerType,
+		ProvisionerID:   ps.ProvisionerID,
+	}
+	url := fmt.Sprintf("/provision/%s/steps/%d", HostKey(ph), ps.StepNumber)
+	switch p := ps.Provisioner.(type) {
+	case *core.Script:
+		ms.URL = url
+		ms.Filename = p.SourceBase()
+		ms.Command = p.SourceBase() + p.ArgString()
+		ms.IgnoreErrors = p.IgnoreErrors
+		ms.Disabled = p.Disabled
+		ms.Timeout = p.Timeout
+	case *core.RemoteFile:
+		ms.URL = url
+		ms.Filename = filepath.Base(p.Source)
+		ms.Destination = p.Destination
+		ms.Perms = p.Perms
+		ms.Disabled = p.Disabled
+	case *core.Command:
+		ms.Command = p.CommandString()
+		ms.IgnoreErrors = p.IgnoreErrors
+		ms.Disabled = p.Disabled
+		ms.Timeout = p.Timeout
+	case *core.DNSRecord:
+		ms.Disabled = p.Disabled
+	}
+	return ms
+}
+
+// ReqGetStep returns the content of a script or remote file step
+func (s *Server) ReqGetStep(c *gin.Context) {
+	ph, ok := s.authorize(c)
+	if !ok {
+		return
+	}
+
+	num, err := strconv.Atoi(c.Param("step"))
+	if err != nil || num < 0 || num >= len(ph.StepsByOffset) {
+		c.JSON(404, map[string]string{
+			"status":  "error",
+			"message": "no such step",
+		})
+		return
+	}
+	ps := ph.StepsByOffset[num]
+
+	assetPath, err := s.AssetPath(ph, ps)
+	if err != nil {
+		c.JSON(404, map[string]string{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	fi, err := os.Stat(assetPath)
+	if err != nil {
+		cli.Logger.Errorf("Asset for %s is missing (has the build been rendered?): %v", ps.Path(), err)
+		c.JSON(500, map[string]string{
+			"status":  "error",
+			"message": "step asset is missing",
+		})
+		return
+	}
+
+	s.logFetch(ph, ps.StepNumber, ps.ProvisionerID, c.ClientIP(), fi.Size())
+	c.FileAttachment(assetPath, filepath.Base(assetPath))
+}
+
+// AssetPath returns the location of the rendered asset of a provisioning step within the build
+func (s *Server) AssetPath(ph *core.ProvisionedHost, ps *core.ProvisioningStep) (string, error) {
+	return AssetPath(s.Base, ph, ps)
+}
+
+// AssetPath returns the location of the rendered asset of a script or remote file step within the base's
+// current build
+func AssetPath(base *core.Laforge, ph *core.ProvisionedHost, ps *core.ProvisioningStep) (string, error) {
+	switch p := ps.Provisioner.(type) {
+	case *core.Script:
+		return filepath.Join(base.BaseDir, ph.Path(), "assets", p.SourceBase()), nil
+	case *core.RemoteFile:
+		name, err := p.AssetName()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(base.BaseDir, base.CurrentEnv.Build.Path(), "data", name), nil
+	default:
+		return "", errors.Errorf("%s steps do not have any content to serve", ps.ProvisionerType)
+	}
+}
+
+// logFetch records that a host fetched it's manifest (step -1) or one of it's steps
+func (s *Server) logFetch(ph *core.ProvisionedHost, step int, provisionerID, remoteAddr string, size int64) {
+	if step < 0 {
+		cli.Logger.Infof("%s fetched it's manifest from %s", ph.Path(), remoteAddr)
+	} else {
+		cli.Logger.Infof("%s fetched step %d (%s) from %s", ph.Path(), step, provisionerID, remoteAddr)
+	}
+
+	if s.FetchLog == nil {
+		return
+	}
+
+	data, err := json.Marshal(&FetchRecord{
+		Time:              time.Now(),
+		ProvisionedHostID: ph.Path(),
+		StepNumber:        step,
+		ProvisionerID:     provisionerID,
+		RemoteAddr:        remoteAddr,
+		Bytes:             size,
+	})
+	if err != nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	_, err = s.FetchLog.Write(append(data, '\n'))
+	if err != nil {
+		cli.Logger.Errorf("Could not write to the fetch log: %v", err)
+	}
+}
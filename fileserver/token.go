@@ -0,0 +1,58 @@
+package fileserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/gen0cide/laforge/agent"
+	"github.com/gen0cide/laforge/core"
+	"github.com/pkg/errors"
+)
+
+// TokenHeader is the HTTP header a host can present it's token in (the token query parameter is also accepted)
+const TokenHeader = `X-Laforge-Token`
+
+// TokenSet derives the per host download tokens of a build. Tokens are an HMAC of the provisioned host's ID keyed by
+// the build's agent CA private key, which every builder creates, so they are stable across runs of the server and can
+// not be forged without access to the build's data directory.
+type TokenSet struct {
+	key []byte
+}
+
+// NewTokenSet creates a token set keyed by the provided secret
+func NewTokenSet(secret []byte) *TokenSet {
+	sum := sha256.Sum256(secret)
+	return &TokenSet{key: sum[:]}
+}
+
+// LoadTokenSet creates the token set of the base's current build from the agent CA key in the build's data directory.
+func LoadTokenSet(base *core.Laforge) (*TokenSet, error) {
+	if base.CurrentBuild == nil {
+		return nil, errors.New("laforge base does not have a current build")
+	}
+	keyfile := filepath.Join(base.BaseDir, base.CurrentBuild.Path(), "data", agent.BuildCAKeyFilename)
+	data, err := ioutil.ReadFile(keyfile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read build key %s", keyfile)
+	}
+	return NewTokenSet(data), nil
+}
+
+// Token returns the token of a provisioned host
+func (t *TokenSet) Token(ph *core.ProvisionedHost) string {
+	mac := hmac.New(sha256.New, t.key)
+	//nolint:gosec,errcheck
+	mac.Write([]byte("laforge-serve:" + ph.Path()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Valid returns true if token belongs to the provisioned host
+func (t *TokenSet) Valid(ph *core.ProvisionedHost, token string) bool {
+	if token == "" {
+		return false
+	}
+	return hmac.Equal([]byte(t.Token(ph)), []byte(token))
+}
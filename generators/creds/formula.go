@@ -1,6 +1,8 @@
 package creds
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"math/rand"
 	"strings"
@@ -66,10 +68,11 @@ var (
 	}
 )
 
+// Step is a weighted mutation applied to a weak password
 type Step struct {
 	Name   string
 	Weight int
-	Func   func(i string) string
+	Func   func(r *rand.Rand, i string) string
 }
 
 func init() {
@@ -77,57 +80,93 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
-	Top500BadPasswords = strings.Split(string(tmpldata), "\n")
+	for _, x := range strings.Split(string(tmpldata), "\n") {
+		x = strings.TrimSpace(x)
+		if x != "" {
+			Top500BadPasswords = append(Top500BadPasswords, x)
+		}
+	}
 
 	for _, x := range Steps {
 		TotalWeight += x.Weight
 	}
 }
 
+// Generator creates passwords from it's own source of randomness, so that a generator created with the same seed
+// always creates the same passwords
+type Generator struct {
+	rng *rand.Rand
+}
+
+// NewGenerator creates a generator with the provided seed
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rng: rand.New(rand.NewSource(seed))}
+}
+
+// SeedFrom derives a generator seed from a list of values, such as a build's seed, a team number and an object ID
+func SeedFrom(parts ...string) int64 {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return int64(binary.BigEndian.Uint64(sum[:8]))
+}
+
+// RandomPassword creates a weak password with stepCount mutations, seeded from the current time
 func RandomPassword(stepCount int) string {
-	rand.Seed(time.Now().UnixNano())
-	s := appendRandomBadPassword("")
+	return NewGenerator(time.Now().UnixNano()).WeakPassword(stepCount)
+}
+
+// WeakPassword picks a password from the top 500 bad passwords and applies stepCount weighted mutations to it
+func (g *Generator) WeakPassword(stepCount int) string {
+	s := appendRandomBadPassword(g.rng, "")
 	for i := 0; i < stepCount; i++ {
 		beginningS := s
 		for {
-			s = RandomWeightedStep().Func(s)
+			s = g.WeightedStep().Func(g.rng, s)
 			if s != beginningS {
 				break
 			}
 		}
 	}
-
-	time.Sleep(10 * time.Millisecond)
 	return s
 }
 
-func appendRandomBadPassword(s string) string {
-	r := rand.Intn(len(Top500BadPasswords) - 1)
-	w := Top500BadPasswords[r]
+// WeightedStep picks a mutation, favoring those with the highest weight
+func (g *Generator) WeightedStep() Step {
+	r := g.rng.Intn(TotalWeight)
+	for _, x := range Steps {
+		r -= x.Weight
+		if r < 0 {
+			return x
+		}
+	}
+	return Steps[len(Steps)-1]
+}
+
+func appendRandomBadPassword(r *rand.Rand, s string) string {
+	w := Top500BadPasswords[r.Intn(len(Top500BadPasswords))]
 	return fmt.Sprintf("%s%s", s, w)
 }
 
-func appendYear(s string) string {
-	return fmt.Sprintf("%s%d", s, random(1960, 2019))
+func appendYear(r *rand.Rand, s string) string {
+	return fmt.Sprintf("%s%d", s, random(r, 1960, 2019))
 }
 
-func appendSpecial(s string) string {
-	return fmt.Sprintf("%s%s", s, string(specialChars[rand.Intn(len(specialChars))]))
+func appendSpecial(r *rand.Rand, s string) string {
+	return fmt.Sprintf("%s%s", s, string(specialChars[r.Intn(len(specialChars))]))
 }
 
-func appendNumber(s string) string {
-	return fmt.Sprintf("%s%d", s, random(0, 9))
+func appendNumber(r *rand.Rand, s string) string {
+	return fmt.Sprintf("%s%d", s, random(r, 0, 9))
 }
 
-func prependNumber(s string) string {
-	return fmt.Sprintf("%d%s", random(0, 9), s)
+func prependNumber(r *rand.Rand, s string) string {
+	return fmt.Sprintf("%d%s", random(r, 0, 9), s)
 }
 
-func capitalizeFirst(s string) string {
+func capitalizeFirst(r *rand.Rand, s string) string {
 	return strings.Title(s)
 }
 
-func leetspeakEverything(s string) string {
+func leetspeakEverything(r *rand.Rand, s string) string {
 	wat, err := formatifier.ToLeet(s)
 	if err != nil {
 		return s
@@ -135,18 +174,6 @@ func leetspeakEverything(s string) string {
 	return wat
 }
 
-func random(min, max int) int {
-	return rand.Intn(max-min) + min
-}
-
-func RandomWeightedStep() Step {
-	for {
-		r := rand.Intn(TotalWeight)
-		for _, g := range Steps {
-			r -= g.Weight
-			if r <= 0 {
-				return g
-			}
-		}
-	}
+func random(r *rand.Rand, min, max int) int {
+	return r.Intn(max-min) + min
 }
@@ -0,0 +1,157 @@
+package creds
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// UpperChars are the upper case letters used by strong passwords
+	UpperChars = `ABCDEFGHIJKLMNOPQRSTUVWXYZ`
+
+	// LowerChars are the lower case letters used by strong passwords
+	LowerChars = `abcdefghijklmnopqrstuvwxyz`
+
+	// DigitChars are the digits used by strong passwords
+	DigitChars = `0123456789`
+
+	// SpecialChars are the special characters used by strong passwords
+	SpecialChars = `!@#$%^&*-_=+?`
+
+	// DefaultCharset is the charset of a strong password policy which does not set one
+	DefaultCharset = UpperChars + LowerChars + DigitChars + SpecialChars
+
+	// DefaultStrongLength is the length of a strong password policy which does not set one
+	DefaultStrongLength = 16
+
+	// DefaultWeakSteps is the number of mutations of a weak password policy which does not set them
+	DefaultWeakSteps = 2
+
+	maxStrongAttempts = 1000
+)
+
+var (
+	// Policies are the built in password policies
+	Policies = map[string]*Policy{
+		"weak": {
+			Weak:  true,
+			Steps: DefaultWeakSteps,
+		},
+		"strong": {
+			Length:         DefaultStrongLength,
+			RequireUpper:   true,
+			RequireLower:   true,
+			RequireDigit:   true,
+			RequireSpecial: true,
+		},
+	}
+)
+
+// Policy describes how a password is generated. Weak policies mutate one of the top 500 bad passwords so that they
+// can be cracked on purpose, while strong policies draw every character at random from the charset.
+type Policy struct {
+	Weak           bool
+	Steps          int
+	Length         int
+	Charset        string
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSpecial bool
+}
+
+// required returns the character classes the policy requires
+func (p *Policy) required() []string {
+	classes := []string{}
+	if p.RequireUpper {
+		classes = append(classes, UpperChars)
+	}
+	if p.RequireLower {
+		classes = append(classes, LowerChars)
+	}
+	if p.RequireDigit {
+		classes = append(classes, DigitChars)
+	}
+	if p.RequireSpecial {
+		classes = append(classes, SpecialChars)
+	}
+	return classes
+}
+
+// Password generates a password that satisfies the policy
+func (g *Generator) Password(p *Policy) (string, error) {
+	if p.Weak {
+		return g.weakPassword(p), nil
+	}
+	return g.strongPassword(p)
+}
+
+// weakPassword mutates a bad password, then satisfies the policy's rules the way a person would - by capitalizing it
+// or tacking digits and a special character onto the end
+func (g *Generator) weakPassword(p *Policy) string {
+	steps := p.Steps
+	if steps == 0 {
+		steps = DefaultWeakSteps
+	}
+	s := g.WeakPassword(steps)
+	if p.RequireUpper && !strings.ContainsAny(s, UpperChars) {
+		s = capitalizeFirst(g.rng, s)
+	}
+	if p.RequireDigit && !strings.ContainsAny(s, DigitChars) {
+		s = appendNumber(g.rng, s)
+	}
+	if p.RequireSpecial && !strings.ContainsAny(s, SpecialChars) {
+		s = appendSpecial(g.rng, s)
+	}
+	// capitalizing does nothing to a password which starts with a digit, and leetspeak can leave no letters at all,
+	// so a letter of any class that is still missing is tacked onto the end
+	for _, class := range p.required() {
+		if !strings.ContainsAny(s, class) {
+			s += string(class[g.rng.Intn(len(class))])
+		}
+	}
+	for len(s) < p.Length {
+		s = appendNumber(g.rng, s)
+	}
+	return s
+}
+
+// strongPassword draws random characters from the policy's charset until every required character class is present
+func (g *Generator) strongPassword(p *Policy) (string, error) {
+	length := p.Length
+	if length == 0 {
+		length = DefaultStrongLength
+	}
+	charset := []rune(p.Charset)
+	if len(charset) == 0 {
+		charset = []rune(DefaultCharset)
+	}
+	classes := p.required()
+	if len(classes) > length {
+		return "", fmt.Errorf("a password of length %d can not contain %d required character classes", length, len(classes))
+	}
+	for _, class := range classes {
+		if !strings.ContainsAny(string(charset), class) {
+			return "", fmt.Errorf("charset %q does not contain any of the required characters %q", string(charset), class)
+		}
+	}
+
+	buf := make([]rune, length)
+	for attempt := 0; attempt < maxStrongAttempts; attempt++ {
+		for i := range buf {
+			buf[i] = charset[g.rng.Intn(len(charset))]
+		}
+		s := string(buf)
+		satisfied := true
+		for _, class := range classes {
+			if !strings.ContainsAny(s, class) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("could not generate a password satisfying the policy in %d attempts", maxStrongAttempts)
+}
@@ -0,0 +1,32 @@
+package creds
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWeakPasswordSatisfiesPolicy(t *testing.T) {
+	p := &Policy{
+		Weak:           true,
+		Steps:          3,
+		Length:         8,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireDigit:   true,
+		RequireSpecial: true,
+	}
+	for seed := int64(0); seed < 2000; seed++ {
+		s, err := NewGenerator(seed).Password(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(s) < p.Length {
+			t.Errorf("seed %d: %q is shorter than %d", seed, s, p.Length)
+		}
+		for _, class := range p.required() {
+			if !strings.ContainsAny(s, class) {
+				t.Errorf("seed %d: %q does not contain any of %q", seed, s, class)
+			}
+		}
+	}
+}
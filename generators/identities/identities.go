@@ -0,0 +1,142 @@
+package identities
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+var (
+	// FirstNames are the given names generated people are drawn from
+	FirstNames = []string{
+		"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda", "William", "Elizabeth",
+		"David", "Barbara", "Richard", "Susan", "Joseph", "Jessica", "Thomas", "Sarah", "Charles", "Karen",
+		"Christopher", "Nancy", "Daniel", "Lisa", "Matthew", "Betty", "Anthony", "Margaret", "Mark", "Sandra",
+		"Donald", "Ashley", "Steven", "Kimberly", "Paul", "Emily", "Andrew", "Donna", "Joshua", "Michelle",
+		"Kenneth", "Carol", "Kevin", "Amanda", "Brian", "Melissa", "George", "Deborah", "Timothy", "Stephanie",
+		"Ronald", "Rebecca", "Jason", "Laura", "Edward", "Sharon", "Jeffrey", "Cynthia", "Ryan", "Kathleen",
+		"Jacob", "Amy", "Gary", "Angela", "Nicholas", "Shirley", "Eric", "Anna", "Jonathan", "Ruth",
+		"Priya", "Wei", "Carlos", "Fatima", "Hiroshi", "Olga", "Mateo", "Aisha", "Lars", "Mei",
+	}
+
+	// LastNames are the family names generated people are drawn from
+	LastNames = []string{
+		"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez",
+		"Hernandez", "Lopez", "Gonzalez", "Wilson", "Anderson", "Thomas", "Taylor", "Moore", "Jackson", "Martin",
+		"Lee", "Perez", "Thompson", "White", "Harris", "Sanchez", "Clark", "Ramirez", "Lewis", "Robinson",
+		"Walker", "Young", "Allen", "King", "Wright", "Scott", "Torres", "Nguyen", "Hill", "Flores",
+		"Green", "Adams", "Nelson", "Baker", "Hall", "Rivera", "Campbell", "Mitchell", "Carter", "Roberts",
+		"Patel", "Chen", "Kim", "Singh", "Novak", "Kowalski", "Murphy", "O'Brien", "Schmidt", "Tanaka",
+	}
+
+	// Departments maps the departments of a generated company to the job titles within them
+	Departments = map[string][]string{
+		"Engineering":     {"Software Engineer", "Senior Software Engineer", "Engineering Manager", "QA Analyst", "DevOps Engineer"},
+		"IT":              {"Systems Administrator", "Help Desk Technician", "Network Engineer", "IT Manager", "Database Administrator"},
+		"Finance":         {"Accountant", "Financial Analyst", "Payroll Specialist", "Controller"},
+		"Human Resources": {"HR Generalist", "Recruiter", "HR Manager", "Benefits Coordinator"},
+		"Sales":           {"Account Executive", "Sales Representative", "Sales Manager", "Sales Engineer"},
+		"Marketing":       {"Marketing Coordinator", "Content Strategist", "Marketing Manager", "Graphic Designer"},
+		"Legal":           {"Paralegal", "Corporate Counsel", "Compliance Officer"},
+		"Operations":      {"Operations Analyst", "Facilities Coordinator", "Office Manager", "Logistics Specialist"},
+		"Executive":       {"Chief Executive Officer", "Chief Financial Officer", "Chief Technology Officer", "Executive Assistant"},
+	}
+)
+
+// Person is a generated or imported member of a fake organization
+type Person struct {
+	Firstname  string
+	Lastname   string
+	Username   string
+	Department string
+	Title      string
+}
+
+// Email returns the person's email address within the provided domain
+func (p *Person) Email(domain string) string {
+	return fmt.Sprintf("%s@%s", p.Username, domain)
+}
+
+// Generator creates people reproducibly from a seed
+type Generator struct {
+	rng         *rand.Rand
+	departments []string
+	usernames   map[string]bool
+}
+
+// NewGenerator creates a generator whose output is determined entirely by the seed
+func NewGenerator(seed int64) *Generator {
+	g := &Generator{
+		rng:         rand.New(rand.NewSource(seed)),
+		departments: []string{},
+		usernames:   map[string]bool{},
+	}
+	for d := range Departments {
+		g.departments = append(g.departments, d)
+	}
+	sort.Strings(g.departments)
+	return g
+}
+
+// Reserve marks usernames as taken so that generated people do not collide with them
+func (g *Generator) Reserve(usernames ...string) {
+	for _, u := range usernames {
+		g.usernames[strings.ToLower(u)] = true
+	}
+}
+
+// Generate creates count people, each with a unique username
+func (g *Generator) Generate(count int) []*Person {
+	people := make([]*Person, 0, count)
+	for i := 0; i < count; i++ {
+		people = append(people, g.Person())
+	}
+	return people
+}
+
+// Person creates a single person with a unique username
+func (g *Generator) Person() *Person {
+	p := &Person{
+		Firstname:  FirstNames[g.rng.Intn(len(FirstNames))],
+		Lastname:   LastNames[g.rng.Intn(len(LastNames))],
+		Department: g.departments[g.rng.Intn(len(g.departments))],
+	}
+	titles := Departments[p.Department]
+	p.Title = titles[g.rng.Intn(len(titles))]
+	p.Username = g.UniqueUsername(p.Firstname, p.Lastname)
+	return p
+}
+
+// UniqueUsername derives a first initial and last name style username, numbering it if it is already taken
+func (g *Generator) UniqueUsername(firstname, lastname string) string {
+	base := Username(firstname, lastname)
+	username := base
+	for i := 2; g.usernames[username]; i++ {
+		username = fmt.Sprintf("%s%d", base, i)
+	}
+	g.usernames[username] = true
+	return username
+}
+
+// Username derives a first initial and last name style username containing only lower case letters and digits
+func Username(firstname, lastname string) string {
+	first := Sanitize(firstname)
+	if len(first) > 1 {
+		first = first[:1]
+	}
+	return first + Sanitize(lastname)
+}
+
+// Sanitize lower cases s and strips every character which is not a letter or digit
+func Sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return -1
+	}, s)
+}